@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.13.0
+// source: go_opentracing_example/validate/v1/validate.proto
+
+package v1
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// StringRules describes the constraints a string field has to satisfy.
+// On repeated fields the rules apply to every element.
+type StringRules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// min_len is the minimum number of characters of the value.
+	MinLen uint32 `protobuf:"varint,1,opt,name=min_len,json=minLen,proto3" json:"min_len,omitempty"`
+	// max_len is the maximum number of characters of the value. Zero means no limit.
+	MaxLen uint32 `protobuf:"varint,2,opt,name=max_len,json=maxLen,proto3" json:"max_len,omitempty"`
+	// utf8 requires the value to be valid UTF-8.
+	Utf8 bool `protobuf:"varint,3,opt,name=utf8,proto3" json:"utf8,omitempty"`
+	// no_control_chars rejects values containing control characters.
+	NoControlChars bool `protobuf:"varint,4,opt,name=no_control_chars,json=noControlChars,proto3" json:"no_control_chars,omitempty"`
+}
+
+func (x *StringRules) Reset() {
+	*x = StringRules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_go_opentracing_example_validate_v1_validate_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StringRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringRules) ProtoMessage() {}
+
+func (x *StringRules) ProtoReflect() protoreflect.Message {
+	mi := &file_go_opentracing_example_validate_v1_validate_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringRules.ProtoReflect.Descriptor instead.
+func (*StringRules) Descriptor() ([]byte, []int) {
+	return file_go_opentracing_example_validate_v1_validate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StringRules) GetMinLen() uint32 {
+	if x != nil {
+		return x.MinLen
+	}
+	return 0
+}
+
+func (x *StringRules) GetMaxLen() uint32 {
+	if x != nil {
+		return x.MaxLen
+	}
+	return 0
+}
+
+func (x *StringRules) GetUtf8() bool {
+	if x != nil {
+		return x.Utf8
+	}
+	return false
+}
+
+func (x *StringRules) GetNoControlChars() bool {
+	if x != nil {
+		return x.NoControlChars
+	}
+	return false
+}
+
+var file_go_opentracing_example_validate_v1_validate_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*StringRules)(nil),
+		Field:         51000,
+		Name:          "go_opentracing_example.validate.v1.string",
+		Tag:           "bytes,51000,opt,name=string",
+		Filename:      "go_opentracing_example/validate/v1/validate.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// string holds the rules of a string field.
+	//
+	// optional go_opentracing_example.validate.v1.StringRules string = 51000;
+	E_String = &file_go_opentracing_example_validate_v1_validate_proto_extTypes[0]
+)
+
+var File_go_opentracing_example_validate_v1_validate_proto protoreflect.FileDescriptor
+
+var file_go_opentracing_example_validate_v1_validate_proto_rawDesc = []byte{
+	0x0a, 0x31, 0x67, 0x6f, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67,
+	0x5f, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x22, 0x67, 0x6f, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x7d, 0x0a, 0x0b, 0x53, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f,
+	0x6c, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6d, 0x69, 0x6e, 0x4c, 0x65,
+	0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x6d, 0x61, 0x78, 0x4c, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x74,
+	0x66, 0x38, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x75, 0x74, 0x66, 0x38, 0x12, 0x28,
+	0x0a, 0x10, 0x6e, 0x6f, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x63, 0x68, 0x61,
+	0x72, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6e, 0x6f, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x72, 0x73, 0x3a, 0x68, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0xb8, 0x8e, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x67, 0x6f, 0x5f, 0x6f,
+	0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x61, 0x6d, 0x70,
+	0x6c, 0x65, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x42, 0x63, 0x5a, 0x61, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x61, 0x6e, 0x64, 0x72, 0x65, 0x61, 0x6d, 0x31, 0x36, 0x2f, 0x67, 0x6f, 0x2d, 0x6f, 0x70,
+	0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x2f, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x2f, 0x67, 0x6f, 0x2f, 0x67, 0x6f, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_go_opentracing_example_validate_v1_validate_proto_rawDescOnce sync.Once
+	file_go_opentracing_example_validate_v1_validate_proto_rawDescData = file_go_opentracing_example_validate_v1_validate_proto_rawDesc
+)
+
+func file_go_opentracing_example_validate_v1_validate_proto_rawDescGZIP() []byte {
+	file_go_opentracing_example_validate_v1_validate_proto_rawDescOnce.Do(func() {
+		file_go_opentracing_example_validate_v1_validate_proto_rawDescData = protoimpl.X.CompressGZIP(file_go_opentracing_example_validate_v1_validate_proto_rawDescData)
+	})
+	return file_go_opentracing_example_validate_v1_validate_proto_rawDescData
+}
+
+var file_go_opentracing_example_validate_v1_validate_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_go_opentracing_example_validate_v1_validate_proto_goTypes = []interface{}{
+	(*StringRules)(nil),               // 0: go_opentracing_example.validate.v1.StringRules
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_go_opentracing_example_validate_v1_validate_proto_depIdxs = []int32{
+	1, // 0: go_opentracing_example.validate.v1.string:extendee -> google.protobuf.FieldOptions
+	0, // 1: go_opentracing_example.validate.v1.string:type_name -> go_opentracing_example.validate.v1.StringRules
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_go_opentracing_example_validate_v1_validate_proto_init() }
+func file_go_opentracing_example_validate_v1_validate_proto_init() {
+	if File_go_opentracing_example_validate_v1_validate_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_go_opentracing_example_validate_v1_validate_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StringRules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_go_opentracing_example_validate_v1_validate_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_go_opentracing_example_validate_v1_validate_proto_goTypes,
+		DependencyIndexes: file_go_opentracing_example_validate_v1_validate_proto_depIdxs,
+		MessageInfos:      file_go_opentracing_example_validate_v1_validate_proto_msgTypes,
+		ExtensionInfos:    file_go_opentracing_example_validate_v1_validate_proto_extTypes,
+	}.Build()
+	File_go_opentracing_example_validate_v1_validate_proto = out.File
+	file_go_opentracing_example_validate_v1_validate_proto_rawDesc = nil
+	file_go_opentracing_example_validate_v1_validate_proto_goTypes = nil
+	file_go_opentracing_example_validate_v1_validate_proto_depIdxs = nil
+}
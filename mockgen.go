@@ -6,7 +6,15 @@ package gen
 //go:generate mockgen -package todoclientmock -destination src/test/mock/todoclient/todoclient_mock.go -source contracts/build/go/go_opentracing_example/grpc_server/todo/v1/todo_service_grpc.pb.go TodoServiceClient
 //go:generate mockgen -package sendermock -destination src/test/mock/kafka/sender_mock.go -source src/shared/kafka/sender.go Sender
 //go:generate mockgen -package todocreatormock -destination src/test/mock/kafka-consumer/todo/repository/repository_mock.go -source src/kafka-consumer/todo/repository/repository.go Creator
-//go:generate mockgen -package executormock -destination src/test/mock/database/postgres/executor_mock.go -source src/shared/database/postgres/executor.go Executor
+//go:generate mockgen -package todorepositorymock -destination src/test/mock/grpc-server/todo/repository/repository_mock.go -source src/grpc-server/todo/repository/repository.go Repository
+//go:generate mockgen -package watchermock -destination src/test/mock/grpc-server/todo/watcher/watcher_mock.go -source src/grpc-server/todo/watcher/watcher.go Subscriber
+//go:generate mockgen -package operationrepositorymock -destination src/test/mock/grpc-server/operation/repository/repository_mock.go -source src/grpc-server/operation/repository/repository.go Repository
+//go:generate mockgen -package operationrecordermock -destination src/test/mock/kafka-consumer/operation/repository/repository_mock.go -source src/kafka-consumer/operation/repository/repository.go Recorder
+//go:generate mockgen -package transportkafkamock -destination src/test/mock/kafka-consumer/transport/kafka/deadletter_mock.go -source src/kafka-consumer/transport/kafka/deadletter.go DeadLetterer
+//go:generate mockgen -package transportkafkamock -destination src/test/mock/kafka-consumer/transport/kafka/retry_mock.go -source src/kafka-consumer/transport/kafka/retry.go Retrier
+//go:generate mockgen -package outboxrepositorymock -destination src/test/mock/grpc-server/outbox/repository/repository_mock.go -source src/grpc-server/outbox/repository/repository.go Writer,Reader
+//go:generate mockgen -package offsetrepositorymock -destination src/test/mock/kafka-consumer/offset/repository/repository_mock.go -source src/kafka-consumer/offset/repository/repository.go Store
+//go:generate mockgen -package executormock -destination src/test/mock/database/postgres/executor_mock.go -source src/shared/database/postgres/executor.go Executor,Transactor,Querier,Row,Rows,Listener,ListenerFactory
 
 // External
 //go:generate mockgen -package opentracingmock -destination src/test/mock/opentracing/opentracing_mock.go -source vendor/github.com/opentracing/opentracing-go/span.go Span,SpanContext
@@ -6,15 +6,24 @@ import (
 	"log"
 	"net"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
+	"github.com/opentracing/opentracing-go"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 
 	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	operationrepository "github.com/andream16/go-opentracing-example/src/grpc-server/operation/repository"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/outbox/relay"
+	outboxrepository "github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/todo/repository"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/todo/watcher"
 	"github.com/andream16/go-opentracing-example/src/grpc-server/transport/grpc/todo"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres/migrator"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres/pgxwrapper"
 	"github.com/andream16/go-opentracing-example/src/shared/kafka"
 	"github.com/andream16/go-opentracing-example/src/shared/tracing"
 )
@@ -26,16 +35,14 @@ func main() {
 		grpcServerPort     string
 		kafkaTodoTopic     string
 		kafkaBrokerAddress string
-		jaegerAgentHost    string
-		jaegerAgentPort    string
+		databaseDSN        string
 	)
 
 	for k, v := range map[string]*string{
 		"GRPC_SERVER_PORT":     &grpcServerPort,
 		"KAFKA_TODO_TOPIC":     &kafkaTodoTopic,
 		"KAFKA_BROKER_ADDRESS": &kafkaBrokerAddress,
-		"JAEGER_AGENT_HOST":    &jaegerAgentHost,
-		"JAEGER_AGENT_PORT":    &jaegerAgentPort,
+		"DATABASE_DSN":         &databaseDSN,
 	} {
 		var ok bool
 		*v, ok = os.LookupEnv(k)
@@ -47,7 +54,19 @@ func main() {
 	var ctx, cancel = context.WithCancel(context.Background())
 	defer cancel()
 
-	tracer, err := tracing.NewJaegerTracer(serviceName, jaegerAgentHost, jaegerAgentPort)
+	samplerCfg, err := tracing.SamplerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("could not read sampler configuration: %v", err)
+	}
+
+	tracer, err := tracing.New(ctx, tracing.Config{
+		Backend:         os.Getenv("TRACING_BACKEND"),
+		ServiceName:     serviceName,
+		JaegerAgentHost: os.Getenv("JAEGER_AGENT_HOST"),
+		JaegerAgentPort: os.Getenv("JAEGER_AGENT_PORT"),
+		Sampler:         samplerCfg,
+		Propagation:     tracing.ParsePropagation(os.Getenv("TRACING_PROPAGATION")),
+	})
 	if err != nil {
 		log.Fatalf("could not create new tracer: %v", err)
 	}
@@ -59,6 +78,13 @@ func main() {
 	kafkaCfg.Producer.Retry.Max = 10
 	kafkaCfg.Producer.Return.Successes = true
 
+	// The relay produces todo events again when it can't mark them as sent, an idempotent producer at least
+	// prevents its own retries from writing duplicates in exactly-once mode. It isn't transactional, as sarama
+	// v1.27.2 doesn't support kafka transactions: consumers collapse the duplicates by todo id.
+	if kafka.ExactlyOnceFromEnv() {
+		kafka.EnableIdempotence(kafkaCfg)
+	}
+
 	kafkaClient, err := kafka.NewClient([]string{kafkaBrokerAddress}, kafkaCfg, time.Second*10)
 	if err != nil {
 		log.Fatalf("could not create new kafka client: %v", err)
@@ -69,13 +95,128 @@ func main() {
 		log.Fatalf("could not create new kafka producer: %v", err)
 	}
 
-	service, err := todo.NewService(kafkaTodoTopic, kafkaProducer, tracer)
+	querier, err := pgxwrapper.New(ctx, databaseDSN, 10*time.Second, tracer, os.Getenv("DATABASE_TRACE_ARGS") == "true")
+	if err != nil {
+		log.Fatalf("could not initialise a new querier: %v", err)
+	}
+
+	migrationCtx, migrationCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer migrationCancel()
+
+	migrationConn, err := querier.GetConn(migrationCtx)
+	if err != nil {
+		log.Fatalf("could not get migration connection: %v", err)
+	}
+
+	defer migrationConn.Close(ctx)
+
+	// The grpc-server versions the tables it owns apart from the kafka-consumer's, which used to create them: they're
+	// only created when missing.
+	m, err := migrator.NewPgxMigrator(migrationCtx, migrationConn, "grpc_server_v1")
+	if err != nil {
+		log.Fatalf("could not create a new migration: %v", err)
+	}
+
+	m.AppendMigration(
+		"create_operations_table",
+		`CREATE TABLE IF NOT EXISTS operations (
+			id TEXT PRIMARY KEY,
+			todo_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`,
+		"DROP TABLE operations;",
+	)
+
+	// The events creating todos are added to the outbox, the relay produces them to kafka.
+	m.AppendMigration(
+		"create_outbox_table",
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id BIGSERIAL PRIMARY KEY,
+			topic TEXT NOT NULL,
+			key TEXT NOT NULL,
+			value BYTEA NOT NULL,
+			headers JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			sent_at TIMESTAMPTZ
+		);
+		CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;`,
+		"DROP TABLE outbox;",
+	)
+
+	// Operations created with an idempotency key keep the hash of their request, to reject the key being reused.
+	m.AppendMigration(
+		"add_operation_request_hash",
+		"ALTER TABLE operations ADD COLUMN request_hash TEXT;",
+		"ALTER TABLE operations DROP COLUMN request_hash;",
+	)
+
+	if err := m.Migrate(ctx); err != nil {
+		log.Fatalf("could not run migration: %v", err)
+	}
+
+	repo, err := repository.New(querier)
+	if err != nil {
+		log.Fatalf("could not initialise a new repository: %v", err)
+	}
+
+	operations, err := operationrepository.New(querier)
+	if err != nil {
+		log.Fatalf("could not initialise a new operation repository: %v", err)
+	}
+
+	// The watcher listens on its own connections, listening again a second after losing one.
+	todoWatcher, err := watcher.New(querier, time.Second)
+	if err != nil {
+		log.Fatalf("could not initialise a new watcher: %v", err)
+	}
+
+	outbox, err := outboxrepository.New(querier, querier)
+	if err != nil {
+		log.Fatalf("could not initialise a new outbox repository: %v", err)
+	}
+
+	// The relay polls the outbox on its own untraced connections, so that idle polls don't report a trace each.
+	// Relayed messages are traced by the relay itself.
+	relayQuerier, err := pgxwrapper.New(ctx, databaseDSN, 10*time.Second, opentracing.NoopTracer{}, false)
+	if err != nil {
+		log.Fatalf("could not initialise a new relay querier: %v", err)
+	}
+
+	relayOutbox, err := outboxrepository.New(relayQuerier, relayQuerier)
+	if err != nil {
+		log.Fatalf("could not initialise a new relay outbox repository: %v", err)
+	}
+
+	relayInterval := 100 * time.Millisecond
+	if v, ok := os.LookupEnv("OUTBOX_RELAY_INTERVAL"); ok {
+		if relayInterval, err = time.ParseDuration(v); err != nil {
+			log.Fatalf("could not parse OUTBOX_RELAY_INTERVAL: %v", err)
+		}
+	}
+
+	relayBatchSize := 100
+	if v, ok := os.LookupEnv("OUTBOX_RELAY_BATCH_SIZE"); ok {
+		if relayBatchSize, err = strconv.Atoi(v); err != nil {
+			log.Fatalf("could not parse OUTBOX_RELAY_BATCH_SIZE: %v", err)
+		}
+	}
+
+	outboxRelay, err := relay.New(relayOutbox, relayQuerier, kafkaProducer, tracer, relayInterval, relayBatchSize)
+	if err != nil {
+		log.Fatalf("could not create new outbox relay: %v", err)
+	}
+
+	service, err := todo.NewService(kafkaTodoTopic, querier, outbox, repo, operations, todoWatcher, tracer)
 	if err != nil {
 		log.Fatalf("could not create new service: %v", err)
 	}
 
 	grpcSrv := grpc.NewServer(
 		grpc.UnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer)),
+		grpc.StreamInterceptor(otgrpc.OpenTracingStreamServerInterceptor(tracer)),
 	)
 
 	todov1.RegisterTodoServiceServer(grpcSrv, service)
@@ -95,6 +236,14 @@ func main() {
 		return grpcSrv.Serve(l)
 	})
 
+	g.Go(func() error {
+		return todoWatcher.Run(ctx)
+	})
+
+	g.Go(func() error {
+		return outboxRelay.Run(ctx)
+	})
+
 	g.Go(func() error {
 		<-ctx.Done()
 
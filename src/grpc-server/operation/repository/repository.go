@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+)
+
+var (
+	// ErrNotFound is returned when the requested operation does not exist.
+	ErrNotFound = errors.New("operation not found")
+	// ErrRequestMismatch is returned when an operation is created again by a request other than the one that
+	// created it first, like one reusing its idempotency key.
+	ErrRequestMismatch = errors.New("operation was created by a different request")
+)
+
+// Repository describes the operations repository interface.
+type Repository interface {
+	// Create stores a new pending operation. Creating it again is a no-op, unless it failed: it's pending again then.
+	// Creating it again with another request hash returns an ErrRequestMismatch.
+	Create(ctx context.Context, op *operation.Operation) error
+	// Get returns the operation with the given id.
+	Get(ctx context.Context, id string) (*operation.Operation, error)
+}
+
+// OperationRepository is the operations repository.
+type OperationRepository struct {
+	querier postgres.Querier
+}
+
+// New returns a new OperationRepository.
+func New(querier postgres.Querier) (OperationRepository, error) {
+	if querier == nil {
+		return OperationRepository{}, errors.New("querier cannot be nil")
+	}
+	return OperationRepository{
+		querier: querier,
+	}, nil
+}
+
+// Create inserts a new pending operation in the operations table. Operations retried with the same id, as
+// the ones derived from an idempotency key, are left as they are unless they failed. Operations stored before
+// their request was hashed match any request.
+func (or OperationRepository) Create(ctx context.Context, op *operation.Operation) error {
+	const (
+		createOperationQueryName         = "create_operation"
+		getOperationRequestHashQueryName = "get_operation_request_hash"
+	)
+
+	var id string
+	err := or.querier.QueryRow(
+		ctx,
+		createOperationQueryName,
+		`INSERT INTO operations(id, todo_id, status, request_hash) VALUES($1::text, $2::text, $3::text, NULLIF($5::text, ''))
+		ON CONFLICT (id) DO UPDATE
+		SET status = EXCLUDED.status, error = NULL, request_hash = EXCLUDED.request_hash, updated_at = now()
+		WHERE operations.status = $4::text
+			AND COALESCE(operations.request_hash, EXCLUDED.request_hash) IS NOT DISTINCT FROM EXCLUDED.request_hash
+		RETURNING id`,
+		op.ID,
+		op.TodoID,
+		string(operation.StatusPending),
+		string(operation.StatusFailed),
+		op.RequestHash,
+	).Scan(&id)
+	if err == nil {
+		return nil
+	}
+	// No row is returned when the operation is already pending or succeeded, or was created by another request.
+	if !errors.Is(err, postgres.ErrNoRows) {
+		return fmt.Errorf("could not insert operation: %w", err)
+	}
+
+	var requestHash string
+	if err := or.querier.QueryRow(
+		ctx,
+		getOperationRequestHashQueryName,
+		`SELECT COALESCE(request_hash, '') FROM operations WHERE id = $1::text`,
+		op.ID,
+	).Scan(&requestHash); err != nil {
+		return fmt.Errorf("could not select operation request hash: %w", err)
+	}
+
+	if requestHash != "" && requestHash != op.RequestHash {
+		return ErrRequestMismatch
+	}
+
+	return nil
+}
+
+// Get selects an operation from the operations table.
+func (or OperationRepository) Get(ctx context.Context, id string) (*operation.Operation, error) {
+	const getOperationQueryName = "get_operation"
+
+	var (
+		op     operation.Operation
+		status string
+	)
+	if err := or.querier.QueryRow(
+		ctx,
+		getOperationQueryName,
+		`SELECT id, todo_id, status, COALESCE(error, ''), created_at, updated_at FROM operations WHERE id = $1::text`,
+		id,
+	).Scan(&op.ID, &op.TodoID, &status, &op.Error, &op.CreatedAt, &op.UpdatedAt); err != nil {
+		if errors.Is(err, postgres.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("could not select operation: %w", err)
+	}
+
+	op.Status = operation.Status(status)
+
+	return &op, nil
+}
@@ -0,0 +1,162 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/grpc-server/operation/repository"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("it should return an error because the querier is not valid", func(t *testing.T) {
+		repo, err := repository.New(nil)
+		require.Error(t, err)
+		assert.Empty(t, repo)
+	})
+	t.Run("it should return a new repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo, err := repository.New(executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+		assert.NotEmpty(t, repo)
+	})
+}
+
+func TestOperationRepository_Create(t *testing.T) {
+	t.Run("it should create a pending operation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().
+				QueryRow(ctx, "create_operation", gomock.Any(), "someID", "someTodoID", string(operation.StatusPending), string(operation.StatusFailed), "someHash").
+				Return(mockRow).
+				Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(nil).Times(1),
+		)
+
+		require.NoError(t, repo.Create(ctx, &operation.Operation{ID: "someID", TodoID: "someTodoID", RequestHash: "someHash"}))
+	})
+	t.Run("it should leave the operation as it is because it already exists and did not fail", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "create_operation", gomock.Any(), gomock.Any()).Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(postgres.ErrNoRows).Times(1),
+			mockQuerier.EXPECT().QueryRow(ctx, "get_operation_request_hash", gomock.Any(), "someID").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*string) = "someHash"
+				return nil
+			}).Times(1),
+		)
+
+		require.NoError(t, repo.Create(ctx, &operation.Operation{ID: "someID", TodoID: "someTodoID", RequestHash: "someHash"}))
+	})
+	t.Run("it should return an ErrRequestMismatch because the operation was created by a different request", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "create_operation", gomock.Any(), gomock.Any()).Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(postgres.ErrNoRows).Times(1),
+			mockQuerier.EXPECT().QueryRow(ctx, "get_operation_request_hash", gomock.Any(), "someID").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*string) = "otherHash"
+				return nil
+			}).Times(1),
+		)
+
+		err = repo.Create(ctx, &operation.Operation{ID: "someID", TodoID: "someTodoID", RequestHash: "someHash"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, repository.ErrRequestMismatch))
+	})
+}
+
+func TestOperationRepository_Get(t *testing.T) {
+	t.Run("it should return not found because the operation does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "get_operation", gomock.Any(), "someID").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(postgres.ErrNoRows).Times(1),
+		)
+
+		_, err = repo.Get(ctx, "someID")
+		assert.True(t, errors.Is(err, repository.ErrNotFound))
+	})
+	t.Run("it should return an operation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "get_operation", gomock.Any(), "someID").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*string) = "someID"
+				*dest[1].(*string) = "someTodoID"
+				*dest[2].(*string) = "succeeded"
+				return nil
+			}).Times(1),
+		)
+
+		got, err := repo.Get(ctx, "someID")
+		require.NoError(t, err)
+		assert.Equal(t, "someTodoID", got.TodoID)
+		assert.Equal(t, operation.StatusSucceeded, got.Status)
+	})
+}
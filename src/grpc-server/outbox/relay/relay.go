@@ -0,0 +1,171 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const (
+	spanName = "outbox_relay"
+
+	outboxIDTag = "outbox.id"
+)
+
+// Relay produces the messages of the outbox to kafka, in the order they were added.
+type Relay struct {
+	reader     repository.Reader
+	transactor postgres.Transactor
+	sender     kafka.Sender
+	tracer     tracing.Tracer
+	interval   time.Duration
+	batchSize  int
+}
+
+// New returns a new Relay polling reader every interval for up to batchSize messages at a time, each batch being
+// claimed within a transaction of transactor.
+func New(
+	reader repository.Reader,
+	transactor postgres.Transactor,
+	sender kafka.Sender,
+	tracer tracing.Tracer,
+	interval time.Duration,
+	batchSize int,
+) (Relay, error) {
+	switch {
+	case reader == nil:
+		return Relay{}, errors.New("reader cannot be nil")
+	case transactor == nil:
+		return Relay{}, errors.New("transactor cannot be nil")
+	case sender == nil:
+		return Relay{}, errors.New("sender cannot be nil")
+	case tracer == nil:
+		return Relay{}, errors.New("tracer cannot be nil")
+	case interval <= 0:
+		return Relay{}, errors.New("interval must be positive")
+	case batchSize <= 0:
+		return Relay{}, errors.New("batch size must be positive")
+	}
+	return Relay{
+		reader:     reader,
+		transactor: transactor,
+		sender:     sender,
+		tracer:     tracer,
+		interval:   interval,
+		batchSize:  batchSize,
+	}, nil
+}
+
+// Run relays the pending messages every interval until ctx is done.
+func (r Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			log.Println(fmt.Sprintf("could not relay outbox messages: %v", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending produces the pending messages in order and marks them as sent, until none is left.
+// Each batch is claimed within a transaction locking its messages, so that the relays of other replicas skip them
+// and relay the following ones instead: the order is kept within a batch, and every todo has its own key anyway.
+// It stops at the first message that can't be relayed, so that the following ones of its batch don't overtake it.
+// A message that is produced but can't be marked is produced again, consumers collapse the duplicates.
+func (r Relay) RelayPending(ctx context.Context) error {
+	for {
+		var (
+			pending  int
+			relayErr error
+		)
+
+		if err := r.transactor.InTx(ctx, func(ctx context.Context) error {
+			messages, err := r.reader.Pending(ctx, r.batchSize)
+			if err != nil {
+				return err
+			}
+			pending = len(messages)
+
+			for _, m := range messages {
+				// The messages relayed before are committed as sent all the same.
+				if relayErr = r.relay(ctx, m); relayErr != nil {
+					return nil
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if relayErr != nil {
+			return relayErr
+		}
+
+		if pending < r.batchSize {
+			return nil
+		}
+	}
+}
+
+// relay produces m within a span following from the span that added it, whose context replaces the one
+// carried by m, so that the trace goes on from the relay.
+func (r Relay) relay(ctx context.Context, m *repository.Message) error {
+	opts := []opentracing.StartSpanOption{ext.SpanKindProducer}
+
+	origin, err := r.tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(m.Headers))
+	if err == nil {
+		opts = append(opts, opentracing.FollowsFrom(origin))
+	}
+
+	span := r.tracer.StartSpan(spanName, opts...)
+	defer span.Finish()
+
+	tracing.SetBaggage(span, origin)
+	ext.MessageBusDestination.Set(span, m.Topic)
+	span.SetTag(outboxIDTag, m.ID)
+
+	headers := make(map[string]string, len(m.Headers))
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+
+	if err := r.tracer.Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(headers)); err != nil {
+		tracing.SetError(span, err)
+	}
+
+	if err := r.sender.SendMessage(&sarama.ProducerMessage{
+		Topic:   m.Topic,
+		Key:     sarama.StringEncoder(m.Key),
+		Value:   sarama.ByteEncoder(m.Value),
+		Headers: kafka.ProducerHeaders(headers),
+	}); err != nil {
+		tracing.SetError(span, err)
+		return fmt.Errorf("could not produce outbox message %d: %w", m.ID, err)
+	}
+
+	if err := r.reader.MarkSent(opentracing.ContextWithSpan(ctx, span), m.ID); err != nil {
+		tracing.SetError(span, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package relay_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/grpc-server/outbox/relay"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+	outboxrepositorymock "github.com/andream16/go-opentracing-example/src/test/mock/grpc-server/outbox/repository"
+	sendermock "github.com/andream16/go-opentracing-example/src/test/mock/kafka"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockReader     = outboxrepositorymock.NewMockReader(ctrl)
+		mockTransactor = executormock.NewMockTransactor(ctrl)
+		mockSender     = sendermock.NewMockSender(ctrl)
+		tracer         = recorder.New()
+	)
+
+	t.Run("it should return an error because a parameter is not valid", func(t *testing.T) {
+		for name, newRelay := range map[string]func() (relay.Relay, error){
+			"reader": func() (relay.Relay, error) {
+				return relay.New(nil, mockTransactor, mockSender, tracer, time.Second, 10)
+			},
+			"transactor": func() (relay.Relay, error) { return relay.New(mockReader, nil, mockSender, tracer, time.Second, 10) },
+			"sender": func() (relay.Relay, error) {
+				return relay.New(mockReader, mockTransactor, nil, tracer, time.Second, 10)
+			},
+			"tracer": func() (relay.Relay, error) {
+				return relay.New(mockReader, mockTransactor, mockSender, nil, time.Second, 10)
+			},
+			"interval": func() (relay.Relay, error) { return relay.New(mockReader, mockTransactor, mockSender, tracer, 0, 10) },
+			"batch size": func() (relay.Relay, error) {
+				return relay.New(mockReader, mockTransactor, mockSender, tracer, time.Second, 0)
+			},
+		} {
+			r, err := newRelay()
+			assert.Error(t, err, name)
+			assert.Empty(t, r, name)
+		}
+	})
+	t.Run("it should return a new relay", func(t *testing.T) {
+		r, err := relay.New(mockReader, mockTransactor, mockSender, tracer, time.Second, 10)
+		require.NoError(t, err)
+		assert.NotEmpty(t, r)
+	})
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	t.Run("it should produce the pending messages in order and mark them as sent", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx        = context.Background()
+			mockReader = outboxrepositorymock.NewMockReader(ctrl)
+			mockSender = sendermock.NewMockSender(ctrl)
+			tracer     = recorder.New()
+			headers    = map[string]string{"someHeader": "someHeaderValue"}
+			produced   []*sarama.ProducerMessage
+		)
+
+		requestSpan := tracer.StartSpan("request")
+		require.NoError(t, tracer.Inject(requestSpan.Context(), opentracing.TextMap, opentracing.TextMapCarrier(headers)))
+		requestSpan.Finish()
+
+		r, err := relay.New(mockReader, inTx(ctrl), mockSender, tracer, time.Second, 2)
+		require.NoError(t, err)
+
+		send := func(message *sarama.ProducerMessage) error {
+			produced = append(produced, message)
+			return nil
+		}
+
+		gomock.InOrder(
+			mockReader.EXPECT().Pending(ctx, 2).Return([]*repository.Message{
+				{ID: 1, Topic: "todos", Key: "someKey", Value: []byte("someValue"), Headers: headers},
+				{ID: 2, Topic: "todos", Key: "otherKey", Value: []byte("otherValue")},
+			}, nil).Times(1),
+			mockSender.EXPECT().SendMessage(gomock.Any()).DoAndReturn(send).Times(1),
+			mockReader.EXPECT().MarkSent(gomock.Any(), int64(1)).Return(nil).Times(1),
+			mockSender.EXPECT().SendMessage(gomock.Any()).DoAndReturn(send).Times(1),
+			mockReader.EXPECT().MarkSent(gomock.Any(), int64(2)).Return(nil).Times(1),
+			mockReader.EXPECT().Pending(ctx, 2).Return(nil, nil).Times(1),
+		)
+
+		require.NoError(t, r.RelayPending(ctx))
+
+		require.Len(t, produced, 2)
+		assert.Equal(t, "todos", produced[0].Topic)
+		assert.Equal(t, sarama.StringEncoder("someKey"), produced[0].Key)
+		assert.Equal(t, sarama.ByteEncoder("someValue"), produced[0].Value)
+		assert.Equal(t, sarama.StringEncoder("otherKey"), produced[1].Key)
+
+		carrier := make(opentracing.TextMapCarrier)
+		for _, header := range produced[0].Headers {
+			carrier[string(header.Key)] = string(header.Value)
+		}
+		assert.Equal(t, "someHeaderValue", carrier["someHeader"])
+
+		spanCtx, err := tracer.Extract(opentracing.TextMap, carrier)
+		require.NoError(t, err)
+		tracer.StartSpan("consumer", opentracing.FollowsFrom(spanCtx)).Finish()
+
+		var (
+			spans      = tracer.FinishedSpans()
+			relaySpans = traceassert.Spans(spans, "outbox_relay")
+		)
+
+		require.Len(t, relaySpans, 2)
+		traceassert.FollowsFrom(t, relaySpans[0], traceassert.Span(t, spans, "request"))
+		traceassert.FollowsFrom(t, traceassert.Span(t, spans, "consumer"), relaySpans[0])
+		traceassert.HasTag(t, relaySpans[0], string(ext.SpanKind), ext.SpanKindProducerEnum)
+		traceassert.HasTag(t, relaySpans[0], string(ext.MessageBusDestination), "todos")
+		traceassert.HasTag(t, relaySpans[0], "outbox.id", int64(1))
+		traceassert.Root(t, relaySpans[1])
+	})
+	t.Run("it should stop at the first message that could not be produced and commit the ones relayed before", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx            = context.Background()
+			mockReader     = outboxrepositorymock.NewMockReader(ctrl)
+			mockTransactor = executormock.NewMockTransactor(ctrl)
+			mockSender     = sendermock.NewMockSender(ctrl)
+			tracer         = recorder.New()
+		)
+
+		r, err := relay.New(mockReader, mockTransactor, mockSender, tracer, time.Second, 10)
+		require.NoError(t, err)
+
+		mockTransactor.EXPECT().InTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				assert.NoError(t, fn(ctx))
+				return nil
+			},
+		).Times(1)
+		gomock.InOrder(
+			mockReader.EXPECT().Pending(ctx, 10).Return([]*repository.Message{
+				{ID: 1, Topic: "todos"},
+				{ID: 2, Topic: "todos"},
+				{ID: 3, Topic: "todos"},
+			}, nil).Times(1),
+			mockSender.EXPECT().SendMessage(gomock.Any()).Return(nil).Times(1),
+			mockReader.EXPECT().MarkSent(gomock.Any(), int64(1)).Return(nil).Times(1),
+			mockSender.EXPECT().SendMessage(gomock.Any()).Return(errors.New("someErr")).Times(1),
+		)
+
+		require.Error(t, r.RelayPending(ctx))
+
+		spans := traceassert.Spans(tracer.FinishedSpans(), "outbox_relay")
+		require.Len(t, spans, 2)
+		traceassert.HasTag(t, spans[1], string(ext.Error), true)
+	})
+	t.Run("it should return an error because the claimed messages could not be committed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockReader     = outboxrepositorymock.NewMockReader(ctrl)
+			mockTransactor = executormock.NewMockTransactor(ctrl)
+		)
+
+		r, err := relay.New(mockReader, mockTransactor, sendermock.NewMockSender(ctrl), recorder.New(), time.Second, 10)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockTransactor.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(context.Context) error) error {
+					assert.NoError(t, fn(ctx))
+					return errors.New("someErr")
+				},
+			).Times(1),
+			mockReader.EXPECT().Pending(gomock.Any(), 10).Return(nil, nil).Times(1),
+		)
+
+		assert.Error(t, r.RelayPending(context.Background()))
+	})
+	t.Run("it should return an error because the pending messages could not be read", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReader := outboxrepositorymock.NewMockReader(ctrl)
+
+		r, err := relay.New(mockReader, inTx(ctrl), sendermock.NewMockSender(ctrl), recorder.New(), time.Second, 10)
+		require.NoError(t, err)
+
+		mockReader.EXPECT().Pending(gomock.Any(), 10).Return(nil, errors.New("someErr")).Times(1)
+
+		assert.Error(t, r.RelayPending(context.Background()))
+	})
+}
+
+// inTx returns a transactor running the functions it is passed, as within a transaction.
+func inTx(ctrl *gomock.Controller) *executormock.MockTransactor {
+	mockTransactor := executormock.NewMockTransactor(ctrl)
+	mockTransactor.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).AnyTimes()
+	return mockTransactor
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+)
+
+// Message is a kafka record waiting in the outbox to be produced.
+type Message struct {
+	// ID is assigned by the outbox when the message is added, in the order messages are added.
+	ID      int64
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
+}
+
+// Writer describes the outbox writer interface.
+type Writer interface {
+	// Add stores messages in the outbox, in order, for them to be produced later on.
+	Add(ctx context.Context, messages ...*Message) error
+}
+
+// Reader describes the outbox reader interface.
+type Reader interface {
+	// Pending returns up to limit messages not produced yet, in the order they were added.
+	// Within a transaction, they're locked until it ends and skipped by the other transactions.
+	Pending(ctx context.Context, limit int) ([]*Message, error)
+	// MarkSent marks the message with the given id as produced.
+	MarkSent(ctx context.Context, id int64) error
+}
+
+// Repository describes the outbox repository interface.
+type Repository interface {
+	Writer
+	Reader
+}
+
+// OutboxRepository is the outbox repository.
+type OutboxRepository struct {
+	executor postgres.Executor
+	querier  postgres.Querier
+}
+
+// New returns a new OutboxRepository.
+func New(executor postgres.Executor, querier postgres.Querier) (OutboxRepository, error) {
+	switch {
+	case executor == nil:
+		return OutboxRepository{}, errors.New("executor cannot be nil")
+	case querier == nil:
+		return OutboxRepository{}, errors.New("querier cannot be nil")
+	}
+	return OutboxRepository{
+		executor: executor,
+		querier:  querier,
+	}, nil
+}
+
+// outboxRow is the json representation of a message inserted by Add.
+type outboxRow struct {
+	Topic   string            `json:"topic"`
+	Key     string            `json:"key"`
+	Value   []byte            `json:"value"`
+	Headers map[string]string `json:"headers"`
+}
+
+// Add inserts messages in the outbox table with a single statement, passing them as a json array,
+// so that either all of them or none are stored. Their ids follow the order of messages.
+func (or OutboxRepository) Add(ctx context.Context, messages ...*Message) error {
+	const addOutboxMessagesQueryName = "add_outbox_messages"
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	rows := make([]outboxRow, 0, len(messages))
+	for _, m := range messages {
+		headers := m.Headers
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		rows = append(rows, outboxRow{
+			Topic:   m.Topic,
+			Key:     m.Key,
+			Value:   m.Value,
+			Headers: headers,
+		})
+	}
+
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("could not serialise outbox messages: %w", err)
+	}
+
+	// values are base64 encoded by encoding/json.
+	if err := or.executor.Exec(
+		ctx,
+		addOutboxMessagesQueryName,
+		`INSERT INTO outbox(topic, key, value, headers)
+		SELECT m->>'topic', m->>'key', decode(m->>'value', 'base64'), (m->'headers')::jsonb
+		FROM json_array_elements($1::json) WITH ORDINALITY AS t(m, i)
+		ORDER BY i`,
+		string(b),
+	); err != nil {
+		return fmt.Errorf("could not insert outbox messages: %w", err)
+	}
+
+	return nil
+}
+
+// Pending selects up to limit messages not produced yet from the outbox table, ordered by id, skipping the ones
+// locked by other transactions.
+func (or OutboxRepository) Pending(ctx context.Context, limit int) ([]*Message, error) {
+	const pendingOutboxMessagesQueryName = "pending_outbox_messages"
+
+	rows, err := or.querier.Query(
+		ctx,
+		pendingOutboxMessagesQueryName,
+		`SELECT id, topic, key, value, headers::text FROM outbox WHERE sent_at IS NULL ORDER BY id LIMIT $1::int FOR UPDATE SKIP LOCKED`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not select outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*Message
+	for rows.Next() {
+		var (
+			m       Message
+			headers string
+		)
+		if err := rows.Scan(&m.ID, &m.Topic, &m.Key, &m.Value, &headers); err != nil {
+			return nil, fmt.Errorf("could not scan outbox message: %w", err)
+		}
+		if err := json.Unmarshal([]byte(headers), &m.Headers); err != nil {
+			return nil, fmt.Errorf("could not deserialise headers of outbox message %d: %w", m.ID, err)
+		}
+		messages = append(messages, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not select outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// MarkSent sets the time a message has been produced at in the outbox table.
+func (or OutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	const markOutboxMessageSentQueryName = "mark_outbox_message_sent"
+
+	if err := or.executor.Exec(
+		ctx,
+		markOutboxMessageSentQueryName,
+		`UPDATE outbox SET sent_at = now() WHERE id = $1::bigint`,
+		id,
+	); err != nil {
+		return fmt.Errorf("could not mark outbox message as sent: %w", err)
+	}
+
+	return nil
+}
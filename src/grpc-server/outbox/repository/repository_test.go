@@ -0,0 +1,178 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("it should return an error because the executor is not valid", func(t *testing.T) {
+		repo, err := repository.New(nil, executormock.NewMockQuerier(ctrl))
+		require.Error(t, err)
+		assert.Empty(t, repo)
+	})
+	t.Run("it should return an error because the querier is not valid", func(t *testing.T) {
+		repo, err := repository.New(executormock.NewMockExecutor(ctrl), nil)
+		require.Error(t, err)
+		assert.Empty(t, repo)
+	})
+	t.Run("it should return a new repository", func(t *testing.T) {
+		repo, err := repository.New(executormock.NewMockExecutor(ctrl), executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+		assert.NotEmpty(t, repo)
+	})
+}
+
+func TestOutboxRepository_Add(t *testing.T) {
+	t.Run("it should insert the messages in order with a single statement", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			mockExecutor = executormock.NewMockExecutor(ctrl)
+		)
+
+		repo, err := repository.New(mockExecutor, executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+
+		mockExecutor.EXPECT().Exec(ctx, "add_outbox_messages", gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _ string, args ...interface{}) error {
+				require.Len(t, args, 1)
+
+				var rows []map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(args[0].(string)), &rows))
+				require.Len(t, rows, 2)
+
+				assert.Equal(t, "someTopic", rows[0]["topic"])
+				assert.Equal(t, "someKey", rows[0]["key"])
+				assert.Equal(t, "c29tZVZhbHVl", rows[0]["value"])
+				assert.Equal(t, map[string]interface{}{"someHeader": "someHeaderValue"}, rows[0]["headers"])
+				assert.Equal(t, "otherKey", rows[1]["key"])
+				assert.Equal(t, map[string]interface{}{}, rows[1]["headers"])
+
+				return nil
+			},
+		).Times(1)
+
+		require.NoError(t, repo.Add(
+			ctx,
+			&repository.Message{
+				Topic:   "someTopic",
+				Key:     "someKey",
+				Value:   []byte("someValue"),
+				Headers: map[string]string{"someHeader": "someHeaderValue"},
+			},
+			&repository.Message{Topic: "someTopic", Key: "otherKey"},
+		))
+	})
+	t.Run("it should not insert anything because there are no messages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo, err := repository.New(executormock.NewMockExecutor(ctrl), executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Add(context.Background()))
+	})
+	t.Run("it should return an error because the messages could not be inserted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockExecutor := executormock.NewMockExecutor(ctrl)
+
+		repo, err := repository.New(mockExecutor, executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+
+		mockExecutor.EXPECT().Exec(gomock.Any(), "add_outbox_messages", gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1)
+
+		assert.Error(t, repo.Add(context.Background(), &repository.Message{Topic: "someTopic"}))
+	})
+}
+
+func TestOutboxRepository_Pending(t *testing.T) {
+	t.Run("it should return the pending messages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRows    = executormock.NewMockRows(ctrl)
+		)
+
+		repo, err := repository.New(executormock.NewMockExecutor(ctrl), mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().Query(ctx, "pending_outbox_messages", gomock.Any(), 10).Return(mockRows, nil).Times(1),
+			mockRows.EXPECT().Next().Return(true).Times(1),
+			mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*int64) = 42
+				*dest[1].(*string) = "someTopic"
+				*dest[2].(*string) = "someKey"
+				*dest[3].(*[]byte) = []byte("someValue")
+				*dest[4].(*string) = `{"someHeader": "someHeaderValue"}`
+				return nil
+			}).Times(1),
+			mockRows.EXPECT().Next().Return(false).Times(1),
+			mockRows.EXPECT().Err().Return(nil).Times(1),
+			mockRows.EXPECT().Close().Times(1),
+		)
+
+		messages, err := repo.Pending(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []*repository.Message{{
+			ID:      42,
+			Topic:   "someTopic",
+			Key:     "someKey",
+			Value:   []byte("someValue"),
+			Headers: map[string]string{"someHeader": "someHeaderValue"},
+		}}, messages)
+	})
+	t.Run("it should return an error because the messages could not be selected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockQuerier := executormock.NewMockQuerier(ctrl)
+
+		repo, err := repository.New(executormock.NewMockExecutor(ctrl), mockQuerier)
+		require.NoError(t, err)
+
+		mockQuerier.EXPECT().Query(gomock.Any(), "pending_outbox_messages", gomock.Any(), 10).Return(nil, errors.New("someErr")).Times(1)
+
+		_, err = repo.Pending(context.Background(), 10)
+		assert.Error(t, err)
+	})
+}
+
+func TestOutboxRepository_MarkSent(t *testing.T) {
+	t.Run("it should mark the message as sent", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			mockExecutor = executormock.NewMockExecutor(ctrl)
+		)
+
+		repo, err := repository.New(mockExecutor, executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+
+		mockExecutor.EXPECT().Exec(ctx, "mark_outbox_message_sent", gomock.Any(), int64(42)).Return(nil).Times(1)
+
+		require.NoError(t, repo.MarkSent(ctx, 42))
+	})
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+)
+
+// ErrNotFound is returned when the requested todo does not exist.
+var ErrNotFound = errors.New("todo not found")
+
+// UpdatableFields are the fields an update can be restricted to, named after their columns.
+var UpdatableFields = []string{"message", "title", "description", "due_at", "priority", "tags", "completed"}
+
+// ListFilter describes which todos should be listed.
+type ListFilter struct {
+	// AfterID is the keyset cursor: only todos with a row id greater than it are listed.
+	AfterID int64
+	// Limit is the maximum number of todos to list.
+	Limit int
+	// CreatedAfter, when not zero, only lists todos created at or after it.
+	CreatedAfter time.Time
+	// CreatedBefore, when not zero, only lists todos created before it.
+	CreatedBefore time.Time
+	// MessageContains, when not empty, only lists todos whose message contains it.
+	MessageContains string
+}
+
+// Repository describes the todos repository interface.
+type Repository interface {
+	// Get returns the todo with the given id.
+	Get(ctx context.Context, id string) (*todo.Todo, error)
+	// List returns the todos matching the filter, ordered by row id, and the cursor of the next page.
+	// The row id is the order todos were stored in, unlike their ids, which are ULIDs or legacy row ids as text.
+	// The returned cursor is zero when there are no more todos to list.
+	List(ctx context.Context, filter ListFilter) ([]*todo.Todo, int64, error)
+	// Update sets the given fields, among UpdatableFields, of an existing todo to the ones of t and returns it.
+	// Every field is set when none is given.
+	// An updated change is notified on todo.ChangeChannel.
+	Update(ctx context.Context, t *todo.Todo, fields []string) (*todo.Todo, error)
+	// Delete deletes the todo with the given id.
+	// A deleted change is notified on todo.ChangeChannel.
+	Delete(ctx context.Context, id string) error
+}
+
+// TodoRepository is the todos repository.
+type TodoRepository struct {
+	querier postgres.Querier
+}
+
+// New returns a new TodoRepository.
+func New(querier postgres.Querier) (TodoRepository, error) {
+	if querier == nil {
+		return TodoRepository{}, errors.New("querier cannot be nil")
+	}
+	return TodoRepository{
+		querier: querier,
+	}, nil
+}
+
+// Get selects a todo from the todos table.
+func (tr TodoRepository) Get(ctx context.Context, id string) (*todo.Todo, error) {
+	const getTodoQueryName = "get_todo"
+
+	var t todo.Todo
+	if err := scanTodo(tr.querier.QueryRow(
+		ctx,
+		getTodoQueryName,
+		`SELECT `+todoColumns+` FROM todos WHERE uid = $1::text`,
+		id,
+	), &t); err != nil {
+		if errors.Is(err, postgres.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("could not select todo: %w", err)
+	}
+
+	return &t, nil
+}
+
+// List selects a page of todos from the todos table using keyset pagination on the row id.
+func (tr TodoRepository) List(ctx context.Context, filter ListFilter) ([]*todo.Todo, int64, error) {
+	const listTodosQueryName = "list_todos"
+
+	var createdAfter, createdBefore *time.Time
+	if !filter.CreatedAfter.IsZero() {
+		createdAfter = &filter.CreatedAfter
+	}
+	if !filter.CreatedBefore.IsZero() {
+		createdBefore = &filter.CreatedBefore
+	}
+
+	// One extra row is selected to know whether there is a next page.
+	rows, err := tr.querier.Query(
+		ctx,
+		listTodosQueryName,
+		`SELECT id, `+todoColumns+` FROM todos
+		WHERE id > $1
+			AND ($2::timestamptz IS NULL OR created_at >= $2)
+			AND ($3::timestamptz IS NULL OR created_at < $3)
+			AND ($4::text = '' OR strpos(message, $4) > 0)
+		ORDER BY id
+		LIMIT $5`,
+		filter.AfterID,
+		createdAfter,
+		createdBefore,
+		filter.MessageContains,
+		filter.Limit+1,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not select todos: %w", err)
+	}
+
+	defer rows.Close()
+
+	var (
+		todos  []*todo.Todo
+		rowIDs []int64
+	)
+	for rows.Next() {
+		var (
+			t     todo.Todo
+			rowID int64
+		)
+		if err := scanTodo(rows, &t, &rowID); err != nil {
+			return nil, 0, fmt.Errorf("could not scan todo: %w", err)
+		}
+		todos = append(todos, &t)
+		rowIDs = append(rowIDs, rowID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("could not iterate over todos: %w", err)
+	}
+
+	if len(todos) <= filter.Limit {
+		return todos, 0, nil
+	}
+
+	return todos[:filter.Limit], rowIDs[filter.Limit-1], nil
+}
+
+// Update updates a todo in the todos table.
+// When fields are given, the columns that are not among them keep their value.
+func (tr TodoRepository) Update(ctx context.Context, t *todo.Todo, fields []string) (*todo.Todo, error) {
+	const updateTodoQueryName = "update_todo"
+
+	var dueAt *time.Time
+	if !t.DueAt.IsZero() {
+		dueAt = &t.DueAt
+	}
+
+	trace, err := todo.ChangeTrace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated todo.Todo
+	if err := scanTodo(tr.querier.QueryRow(
+		ctx,
+		updateTodoQueryName,
+		`WITH updated AS (
+			UPDATE todos SET
+				`+updateColumn("message", "$2::text")+`,
+				`+updateColumn("title", "$3::text")+`,
+				`+updateColumn("description", "$4::text")+`,
+				`+updateColumn("due_at", "$5::timestamptz")+`,
+				`+updateColumn("priority", "$6::text")+`,
+				`+updateColumn("tags", "COALESCE($7::text[], '{}')")+`,
+				`+updateColumn("completed", "$8::boolean")+`,
+				updated_at = now()
+			WHERE uid = $1::text
+			RETURNING `+todoColumns+`
+		)
+		SELECT `+todoColumns+`
+		FROM updated, pg_notify($9::text, json_build_object('type', $10::text, 'id', uid, 'trace', $11::json)::text)`,
+		t.ID,
+		t.Message,
+		t.Title,
+		t.Description,
+		dueAt,
+		string(t.Priority),
+		t.Tags,
+		t.Completed,
+		todo.ChangeChannel,
+		string(todo.ChangeUpdated),
+		trace,
+		fields,
+	), &updated); err != nil {
+		if errors.Is(err, postgres.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("could not update todo: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// Delete deletes a todo from the todos table.
+func (tr TodoRepository) Delete(ctx context.Context, id string) error {
+	const deleteTodoQueryName = "delete_todo"
+
+	trace, err := todo.ChangeTrace(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deletedID string
+	if err := tr.querier.QueryRow(
+		ctx,
+		deleteTodoQueryName,
+		`WITH deleted AS (
+			DELETE FROM todos WHERE uid = $1::text RETURNING uid
+		)
+		SELECT uid
+		FROM deleted, pg_notify($2::text, json_build_object('type', $3::text, 'id', uid, 'trace', $4::json)::text)`,
+		id,
+		todo.ChangeChannel,
+		string(todo.ChangeDeleted),
+		trace,
+	).Scan(&deletedID); err != nil {
+		if errors.Is(err, postgres.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("could not delete todo: %w", err)
+	}
+
+	return nil
+}
+
+// updateColumn returns the assignment of value to column, skipped when the fields to update, $12, are given
+// but don't include column.
+func updateColumn(column, value string) string {
+	return column + ` = CASE WHEN COALESCE(cardinality($12::text[]), 0) = 0 OR '` + column + `' = ANY($12::text[]) THEN ` +
+		value + ` ELSE ` + column + ` END`
+}
+
+// todoColumns are the columns scanned by scanTodo, in order.
+const todoColumns = `uid, message, title, description, due_at, priority, tags, completed, created_at, updated_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTodo scans the todoColumns into t, after the columns scanned into leading.
+func scanTodo(s scanner, t *todo.Todo, leading ...interface{}) error {
+	var (
+		dueAt    *time.Time
+		priority string
+	)
+
+	dest := append(
+		leading,
+		&t.ID,
+		&t.Message,
+		&t.Title,
+		&t.Description,
+		&dueAt,
+		&priority,
+		&t.Tags,
+		&t.Completed,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err := s.Scan(dest...); err != nil {
+		return err
+	}
+
+	if dueAt != nil {
+		t.DueAt = *dueAt
+	}
+	t.Priority = todo.Priority(priority)
+
+	return nil
+}
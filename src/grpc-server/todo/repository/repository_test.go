@@ -0,0 +1,336 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/grpc-server/todo/repository"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("it should return an error because the querier is not valid", func(t *testing.T) {
+		repo, err := repository.New(nil)
+		require.Error(t, err)
+		assert.Empty(t, repo)
+	})
+	t.Run("it should return a new repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo, err := repository.New(executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+		assert.NotEmpty(t, repo)
+	})
+}
+
+func TestTodoRepository_Get(t *testing.T) {
+	t.Run("it should return not found because the todo does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "get_todo", gomock.Any(), "someID").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(postgres.ErrNoRows).Times(1),
+		)
+
+		_, err = repo.Get(ctx, "someID")
+		assert.True(t, errors.Is(err, repository.ErrNotFound))
+	})
+	t.Run("it should return a todo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "get_todo", gomock.Any(), "someID").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*string) = "someID"
+				*dest[1].(*string) = "hello"
+				return nil
+			}).Times(1),
+		)
+
+		got, err := repo.Get(ctx, "someID")
+		require.NoError(t, err)
+		assert.Equal(t, "someID", got.ID)
+		assert.Equal(t, "hello", got.Message)
+	})
+}
+
+func TestTodoRepository_List(t *testing.T) {
+	t.Run("it should return an error because the query failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		mockQuerier.EXPECT().
+			Query(ctx, "list_todos", gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("someErr")).
+			Times(1)
+
+		_, _, err = repo.List(ctx, repository.ListFilter{Limit: 1})
+		require.Error(t, err)
+	})
+	t.Run("it should return the todos and no cursor because there are no more pages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRows    = executormock.NewMockRows(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().
+				Query(ctx, "list_todos", gomock.Any(), int64(0), nil, nil, "", 3).
+				Return(mockRows, nil).
+				Times(1),
+			mockRows.EXPECT().Next().Return(true).Times(1),
+			mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(scanRowID(1)).Times(1),
+			mockRows.EXPECT().Next().Return(false).Times(1),
+			mockRows.EXPECT().Err().Return(nil).Times(1),
+			mockRows.EXPECT().Close().Times(1),
+		)
+
+		todos, next, err := repo.List(ctx, repository.ListFilter{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, todos, 1)
+		assert.Equal(t, "someID1", todos[0].ID)
+		assert.Zero(t, next)
+	})
+	t.Run("it should return a page of todos and the cursor of the next page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx           = context.Background()
+			createdBefore = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			mockQuerier   = executormock.NewMockQuerier(ctrl)
+			mockRows      = executormock.NewMockRows(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().
+				Query(ctx, "list_todos", gomock.Any(), int64(5), nil, &createdBefore, "hello", 2).
+				Return(mockRows, nil).
+				Times(1),
+			mockRows.EXPECT().Next().Return(true).Times(1),
+			mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(scanRowID(6)).Times(1),
+			mockRows.EXPECT().Next().Return(true).Times(1),
+			mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(scanRowID(7)).Times(1),
+			mockRows.EXPECT().Next().Return(false).Times(1),
+			mockRows.EXPECT().Err().Return(nil).Times(1),
+			mockRows.EXPECT().Close().Times(1),
+		)
+
+		todos, next, err := repo.List(ctx, repository.ListFilter{
+			AfterID:         5,
+			Limit:           1,
+			CreatedBefore:   createdBefore,
+			MessageContains: "hello",
+		})
+		require.NoError(t, err)
+		require.Len(t, todos, 1)
+		assert.Equal(t, "someID6", todos[0].ID)
+		assert.Equal(t, int64(6), next)
+	})
+}
+
+func TestTodoRepository_Update(t *testing.T) {
+	t.Run("it should return not found because the todo does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().
+				QueryRow(
+					ctx,
+					"update_todo",
+					gomock.Any(),
+					"someID",
+					"hello",
+					"",
+					"",
+					(*time.Time)(nil),
+					"",
+					[]string(nil),
+					false,
+					todo.ChangeChannel,
+					"updated",
+					"{}",
+					[]string(nil),
+				).
+				Return(mockRow).
+				Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(postgres.ErrNoRows).Times(1),
+		)
+
+		_, err = repo.Update(ctx, &todo.Todo{ID: "someID", Message: "hello"}, nil)
+		assert.True(t, errors.Is(err, repository.ErrNotFound))
+	})
+	t.Run("it should update the given fields of a todo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+			dueAt       = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().
+				QueryRow(
+					ctx,
+					"update_todo",
+					gomock.Any(),
+					"someID",
+					"hello",
+					"someTitle",
+					"",
+					&dueAt,
+					"high",
+					[]string{"home"},
+					true,
+					todo.ChangeChannel,
+					"updated",
+					"{}",
+					[]string{"completed"},
+				).
+				Return(mockRow).
+				Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*string) = "someID"
+				*dest[1].(*string) = "hello"
+				*dest[2].(*string) = "someTitle"
+				*dest[4].(**time.Time) = &dueAt
+				*dest[5].(*string) = "high"
+				*dest[6].(*[]string) = []string{"home"}
+				*dest[7].(*bool) = true
+				return nil
+			}).Times(1),
+		)
+
+		got, err := repo.Update(ctx, &todo.Todo{
+			ID:        "someID",
+			Message:   "hello",
+			Title:     "someTitle",
+			DueAt:     dueAt,
+			Priority:  todo.PriorityHigh,
+			Tags:      []string{"home"},
+			Completed: true,
+		}, []string{"completed"})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", got.Message)
+		assert.Equal(t, "someTitle", got.Title)
+		assert.True(t, dueAt.Equal(got.DueAt))
+		assert.Equal(t, todo.PriorityHigh, got.Priority)
+		assert.Equal(t, []string{"home"}, got.Tags)
+		assert.True(t, got.Completed)
+	})
+}
+
+func TestTodoRepository_Delete(t *testing.T) {
+	t.Run("it should return not found because the todo does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "delete_todo", gomock.Any(), "someID", todo.ChangeChannel, "deleted", "{}").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(postgres.ErrNoRows).Times(1),
+		)
+
+		assert.True(t, errors.Is(repo.Delete(ctx, "someID"), repository.ErrNotFound))
+	})
+	t.Run("it should delete a todo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRow     = executormock.NewMockRow(ctrl)
+		)
+
+		repo, err := repository.New(mockQuerier)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().QueryRow(ctx, "delete_todo", gomock.Any(), "someID", todo.ChangeChannel, "deleted", "{}").Return(mockRow).Times(1),
+			mockRow.EXPECT().Scan(gomock.Any()).Return(nil).Times(1),
+		)
+
+		require.NoError(t, repo.Delete(ctx, "someID"))
+	})
+}
+
+func scanRowID(id int64) func(dest ...interface{}) error {
+	return func(dest ...interface{}) error {
+		*dest[0].(*int64) = id
+		*dest[1].(*string) = fmt.Sprintf("someID%d", id)
+		return nil
+	}
+}
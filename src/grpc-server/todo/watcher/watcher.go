@@ -0,0 +1,180 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+)
+
+const (
+	// subscriberBufferSize is the number of changes buffered for each subscriber.
+	// Changes are dropped for subscribers that fall further behind.
+	subscriberBufferSize = 64
+	// maxReconnectBackoff caps the delay between the attempts to listen again, which doubles after each failure.
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// Subscriber describes the subscriber interface.
+type Subscriber interface {
+	// Subscribe returns a channel receiving the todo changes committed from now on
+	// and a function to be called to stop receiving them.
+	// The channel is closed when the changes are not watched anymore, or may have been missed.
+	Subscribe() (<-chan todo.Change, func())
+}
+
+// Watcher fans out the todo changes notified by postgres to its subscribers.
+type Watcher struct {
+	listeners postgres.ListenerFactory
+	backoff   time.Duration
+
+	mu          sync.Mutex
+	subscribers map[chan todo.Change]struct{}
+	stopped     bool
+}
+
+// New returns a new Watcher listening on the listeners of listeners, waiting for backoff before listening again
+// when one fails.
+func New(listeners postgres.ListenerFactory, backoff time.Duration) (*Watcher, error) {
+	switch {
+	case listeners == nil:
+		return nil, errors.New("listener factory cannot be nil")
+	case backoff <= 0:
+		return nil, errors.New("backoff must be positive")
+	}
+	return &Watcher{
+		listeners:   listeners,
+		backoff:     backoff,
+		subscribers: make(map[chan todo.Change]struct{}),
+	}, nil
+}
+
+// Run listens to the todo changes until ctx is done, on a new listener whenever the current one fails.
+// The changes notified in the meantime are lost, so the subscribers' channels are closed when a listener fails,
+// as well as when it returns.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer w.stop()
+
+	backoff := w.backoff
+	for {
+		listened, err := w.watch(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		w.disconnect()
+
+		if listened {
+			backoff = w.backoff
+		}
+
+		log.Println(fmt.Sprintf("could not watch todo changes, listening again in %s: %v", backoff, err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// watch publishes the todo changes received by a new listener until it fails or ctx is done.
+// It reports whether the listener got to listen to the changes.
+func (w *Watcher) watch(ctx context.Context) (bool, error) {
+	listener, err := w.listeners.NewListener(ctx)
+	if err != nil {
+		return false, fmt.Errorf("could not create listener: %w", err)
+	}
+	defer listener.Close(context.Background())
+
+	if err := listener.Listen(ctx, todo.ChangeChannel); err != nil {
+		return false, fmt.Errorf("could not listen to todo changes: %w", err)
+	}
+
+	for {
+		payload, err := listener.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("could not wait for todo changes: %w", err)
+		}
+
+		var change todo.Change
+		if err := json.Unmarshal([]byte(payload), &change); err != nil {
+			log.Println(fmt.Sprintf("could not deserialise todo change: %v", err))
+			continue
+		}
+
+		w.publish(change)
+	}
+}
+
+// Subscribe returns a channel receiving the todo changes committed from now on
+// and a function to be called to stop receiving them.
+func (w *Watcher) Subscribe() (<-chan todo.Change, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan todo.Change, subscriberBufferSize)
+	if w.stopped {
+		close(ch)
+		return ch, func() {}
+	}
+
+	w.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if _, ok := w.subscribers[ch]; ok {
+			delete(w.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (w *Watcher) publish(change todo.Change) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subscribers {
+		select {
+		case ch <- change:
+		default:
+			log.Println(fmt.Sprintf("dropping %s change of todo %s for a slow subscriber", change.Type, change.ID))
+		}
+	}
+}
+
+// disconnect closes the channels of the current subscribers, whose changes may be missed.
+func (w *Watcher) disconnect() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closeSubscribers()
+}
+
+func (w *Watcher) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closeSubscribers()
+	w.stopped = true
+}
+
+// closeSubscribers closes the channels of the current subscribers. It must be called with mu locked.
+func (w *Watcher) closeSubscribers() {
+	for ch := range w.subscribers {
+		delete(w.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,184 @@
+package watcher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/grpc-server/todo/watcher"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("it should return an error because the listener factory is not valid", func(t *testing.T) {
+		w, err := watcher.New(nil, time.Second)
+		require.Error(t, err)
+		assert.Nil(t, w)
+	})
+	t.Run("it should return an error because the backoff is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w, err := watcher.New(executormock.NewMockListenerFactory(ctrl), 0)
+		require.Error(t, err)
+		assert.Nil(t, w)
+	})
+	t.Run("it should return a new watcher", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w, err := watcher.New(executormock.NewMockListenerFactory(ctrl), time.Second)
+		require.NoError(t, err)
+		assert.NotNil(t, w)
+	})
+}
+
+func TestWatcher_Run(t *testing.T) {
+	t.Run("it should listen again on a new listener when the channel could not be listened", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx, cancel   = context.WithCancel(context.Background())
+			mockListeners = executormock.NewMockListenerFactory(ctrl)
+			mockFailing   = executormock.NewMockListener(ctrl)
+			mockListener  = executormock.NewMockListener(ctrl)
+		)
+
+		defer cancel()
+
+		w, err := watcher.New(mockListeners, time.Millisecond)
+		require.NoError(t, err)
+
+		changes, _ := w.Subscribe()
+
+		gomock.InOrder(
+			mockListeners.EXPECT().NewListener(ctx).Return(nil, errors.New("someErr")).Times(1),
+			mockListeners.EXPECT().NewListener(ctx).Return(mockFailing, nil).Times(1),
+			mockFailing.EXPECT().Listen(ctx, todo.ChangeChannel).Return(errors.New("someErr")).Times(1),
+			mockFailing.EXPECT().Close(gomock.Any()).Return(nil).Times(1),
+			mockListeners.EXPECT().NewListener(ctx).Return(mockListener, nil).Times(1),
+			mockListener.EXPECT().Listen(ctx, todo.ChangeChannel).Return(nil).Times(1),
+			mockListener.EXPECT().WaitForNotification(ctx).DoAndReturn(func(context.Context) (string, error) {
+				cancel()
+				return "", context.Canceled
+			}).Times(1),
+			mockListener.EXPECT().Close(gomock.Any()).Return(nil).Times(1),
+		)
+
+		require.NoError(t, w.Run(ctx))
+
+		_, ok := <-changes
+		assert.False(t, ok)
+	})
+	t.Run("it should disconnect the subscribers and listen again when the listener fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx, cancel   = context.WithCancel(context.Background())
+			mockListeners = executormock.NewMockListenerFactory(ctrl)
+			mockDropped   = executormock.NewMockListener(ctrl)
+			mockListener  = executormock.NewMockListener(ctrl)
+			resubscribed  <-chan todo.Change
+		)
+
+		defer cancel()
+
+		w, err := watcher.New(mockListeners, time.Millisecond)
+		require.NoError(t, err)
+
+		disconnected, _ := w.Subscribe()
+
+		gomock.InOrder(
+			mockListeners.EXPECT().NewListener(ctx).Return(mockDropped, nil).Times(1),
+			mockDropped.EXPECT().Listen(ctx, todo.ChangeChannel).Return(nil).Times(1),
+			mockDropped.EXPECT().WaitForNotification(ctx).Return("", errors.New("conn closed")).Times(1),
+			mockDropped.EXPECT().Close(gomock.Any()).Return(nil).Times(1),
+			mockListeners.EXPECT().NewListener(ctx).DoAndReturn(func(context.Context) (*executormock.MockListener, error) {
+				resubscribed, _ = w.Subscribe()
+				return mockListener, nil
+			}).Times(1),
+			mockListener.EXPECT().Listen(ctx, todo.ChangeChannel).Return(nil).Times(1),
+			mockListener.EXPECT().WaitForNotification(ctx).Return(`{"type":"deleted","id":"someID"}`, nil).Times(1),
+			mockListener.EXPECT().WaitForNotification(ctx).DoAndReturn(func(context.Context) (string, error) {
+				cancel()
+				return "", context.Canceled
+			}).Times(1),
+			mockListener.EXPECT().Close(gomock.Any()).Return(nil).Times(1),
+		)
+
+		require.NoError(t, w.Run(ctx))
+
+		_, ok := <-disconnected
+		assert.False(t, ok)
+
+		assert.Equal(t, todo.Change{Type: todo.ChangeDeleted, ID: "someID"}, <-resubscribed)
+	})
+	t.Run("it should fan out the notified changes to the subscribers until the context is done", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx, cancel   = context.WithCancel(context.Background())
+			mockListeners = executormock.NewMockListenerFactory(ctrl)
+			mockListener  = executormock.NewMockListener(ctrl)
+		)
+
+		defer cancel()
+
+		w, err := watcher.New(mockListeners, time.Second)
+		require.NoError(t, err)
+
+		var (
+			first, _                = w.Subscribe()
+			second, unsubscribe     = w.Subscribe()
+			unsubscribed, stopWatch = w.Subscribe()
+		)
+
+		stopWatch()
+
+		gomock.InOrder(
+			mockListeners.EXPECT().NewListener(ctx).Return(mockListener, nil).Times(1),
+			mockListener.EXPECT().Listen(ctx, todo.ChangeChannel).Return(nil).Times(1),
+			mockListener.EXPECT().
+				WaitForNotification(ctx).
+				Return(`{"type":"created","id":"someID","trace":{"uber-trace-id":"someTrace"}}`, nil).
+				Times(1),
+			mockListener.EXPECT().WaitForNotification(ctx).Return("notJSON", nil).Times(1),
+			mockListener.EXPECT().WaitForNotification(ctx).DoAndReturn(func(context.Context) (string, error) {
+				cancel()
+				return "", context.Canceled
+			}).Times(1),
+			mockListener.EXPECT().Close(gomock.Any()).Return(nil).Times(1),
+		)
+
+		require.NoError(t, w.Run(ctx))
+
+		want := todo.Change{
+			Type:  todo.ChangeCreated,
+			ID:    "someID",
+			Trace: map[string]string{"uber-trace-id": "someTrace"},
+		}
+
+		assert.Equal(t, want, <-first)
+		assert.Equal(t, want, <-second)
+
+		_, ok := <-first
+		assert.False(t, ok)
+		_, ok = <-unsubscribed
+		assert.False(t, ok)
+
+		unsubscribe()
+
+		stopped, _ := w.Subscribe()
+		_, ok = <-stopped
+		assert.False(t, ok)
+	})
+}
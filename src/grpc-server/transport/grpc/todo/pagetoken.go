@@ -0,0 +1,73 @@
+package todo
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+var errPageTokenFilterMismatch = errors.New("page token does not match the request filters")
+
+// pageToken is the decoded form of the opaque token handed out to clients.
+// The filters fingerprint prevents a token from being replayed against a different query.
+type pageToken struct {
+	AfterID int64  `json:"a"`
+	Filters string `json:"f"`
+}
+
+func encodePageToken(afterID int64, req *todov1.ListTodosRequest) (string, error) {
+	b, err := json.Marshal(pageToken{
+		AfterID: afterID,
+		Filters: filtersFingerprint(req),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not marshal page token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodePageToken(req *todov1.ListTodosRequest) (int64, error) {
+	if req.PageToken == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(req.PageToken)
+	if err != nil {
+		return 0, fmt.Errorf("could not decode page token: %w", err)
+	}
+
+	var token pageToken
+	if err := json.Unmarshal(b, &token); err != nil {
+		return 0, fmt.Errorf("could not unmarshal page token: %w", err)
+	}
+
+	if token.Filters != filtersFingerprint(req) {
+		return 0, errPageTokenFilterMismatch
+	}
+
+	return token.AfterID, nil
+}
+
+func filtersFingerprint(req *todov1.ListTodosRequest) string {
+	var createdAfter, createdBefore string
+	if req.CreatedAfter != nil {
+		createdAfter = req.CreatedAfter.AsTime().Format(time.RFC3339Nano)
+	}
+	if req.CreatedBefore != nil {
+		createdBefore = req.CreatedBefore.AsTime().Format(time.RFC3339Nano)
+	}
+
+	sum := sha256.Sum256([]byte(createdAfter + "\x00" + createdBefore + "\x00" + req.MessageContains))
+	return hex.EncodeToString(sum[:8])
+}
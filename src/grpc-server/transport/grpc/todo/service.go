@@ -2,24 +2,42 @@ package todo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 
-	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
 	"github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
-	"github.com/andream16/go-opentracing-example/src/shared/kafka"
+	operationrepository "github.com/andream16/go-opentracing-example/src/grpc-server/operation/repository"
+	outboxrepository "github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/todo/repository"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/todo/watcher"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
 	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
 )
 
+// batchIndexKey is the key of the index of the todo that failed within a batch.
+const batchIndexKey = "todo.index"
+
 // Service implements the grpc service.
 type Service struct {
 	kafkaTopic string
-	sender     kafka.Sender
+	transactor postgres.Transactor
+	outbox     outboxrepository.Writer
+	repo       repository.Repository
+	operations operationrepository.Repository
+	watcher    watcher.Subscriber
 	tracer     tracing.Tracer
 }
 
@@ -34,16 +52,44 @@ func (i InvalidServiceParameterError) Error() string {
 }
 
 // NewService returns a new Service.
-func NewService(kafkaTopic string, sender kafka.Sender, tracer tracing.Tracer) (Service, error) {
+func NewService(
+	kafkaTopic string,
+	transactor postgres.Transactor,
+	outbox outboxrepository.Writer,
+	repo repository.Repository,
+	operations operationrepository.Repository,
+	watcher watcher.Subscriber,
+	tracer tracing.Tracer,
+) (Service, error) {
 	switch {
 	case kafkaTopic == "":
 		return Service{}, InvalidServiceParameterError{
 			parameter: "kafka topic",
 			reason:    "must be not empty",
 		}
-	case sender == nil:
+	case transactor == nil:
+		return Service{}, InvalidServiceParameterError{
+			parameter: "transactor",
+			reason:    "must be not nil",
+		}
+	case outbox == nil:
+		return Service{}, InvalidServiceParameterError{
+			parameter: "outbox",
+			reason:    "must be not nil",
+		}
+	case repo == nil:
+		return Service{}, InvalidServiceParameterError{
+			parameter: "repo",
+			reason:    "must be not nil",
+		}
+	case operations == nil:
+		return Service{}, InvalidServiceParameterError{
+			parameter: "operations",
+			reason:    "must be not nil",
+		}
+	case watcher == nil:
 		return Service{}, InvalidServiceParameterError{
-			parameter: "sender",
+			parameter: "watcher",
 			reason:    "must be not nil",
 		}
 	case tracer == nil:
@@ -55,20 +101,220 @@ func NewService(kafkaTopic string, sender kafka.Sender, tracer tracing.Tracer) (
 
 	return Service{
 		kafkaTopic: kafkaTopic,
-		sender:     sender,
+		transactor: transactor,
+		outbox:     outbox,
+		repo:       repo,
+		operations: operations,
+		watcher:    watcher,
 		tracer:     tracer,
 	}, nil
 }
 
-// Creates a new todo.
+// Create assigns an id to a new todo and adds the event creating it to the outbox, to be relayed to kafka.
+// A pending operation is recorded along with it so that callers can poll for the todo to be persisted.
 func (svc Service) Create(ctx context.Context, req *todov1.CreateRequest) (*todov1.CreateResponse, error) {
 	if req == nil {
 		log.Println("received nil request for creating a todo")
-		return nil, status.Error(codes.InvalidArgument, "received nil request for creating a todo")
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "received nil request for creating a todo"))
+	}
+
+	// validation errors carry their field violations in the returned InvalidArgument status.
+	if err := validation.Validate(req); err != nil {
+		return nil, tracing.Fail(ctx, err)
+	}
+
+	// The idempotency key travels with the record so that the consumer can collapse duplicates.
+	key, err := scopedKey(ctx)
+	if err != nil {
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, err.Error()))
+	}
+
+	id, operationID := newIDs(key)
+
+	message, err := svc.newMessage(id, operationID, req, svc.recordHeaders(ctx, key))
+	if err != nil {
+		log.Println(fmt.Sprintf("could not marshal event: %v", err))
+		return nil, tracing.Fail(ctx, status.Error(codes.Internal, "could not marshal event"), otlog.Error(err))
+	}
+
+	op, err := newOperation(id, operationID, key, req)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not hash request: %v", err))
+		return nil, tracing.Fail(ctx, status.Error(codes.Internal, "could not hash request"), otlog.Error(err))
+	}
+
+	if reason, err := svc.store(ctx, []*operation.Operation{op}, message); err != nil {
+		log.Println(fmt.Sprintf("%s: %v", reason, err))
+		return nil, tracing.Fail(ctx, status.Error(storeCode(err), reason), otlog.Error(err))
+	}
+
+	return &todov1.CreateResponse{Id: id, OperationId: operationID}, nil
+}
+
+// BatchCreate assigns ids to up to todo.MaxBatchSize new todos and adds the events creating them to the outbox
+// at once. Each request is validated on its own: the response reports, in request order, whether it has been
+// accepted. The valid ones are stored all together, or rejected all together when storing them fails.
+func (svc Service) BatchCreate(ctx context.Context, req *todov1.BatchCreateRequest) (*todov1.BatchCreateResponse, error) {
+	if req == nil {
+		log.Println("received nil request for creating a batch of todos")
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "received nil request for creating a batch of todos"))
+	}
+
+	switch {
+	case len(req.Requests) == 0:
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "batch must contain at least one todo"))
+	case len(req.Requests) > todo.MaxBatchSize:
+		return nil, tracing.Fail(ctx, status.Errorf(codes.InvalidArgument, "batch cannot contain more than %d todos", todo.MaxBatchSize))
+	}
+
+	key, err := scopedKey(ctx)
+	if err != nil {
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, err.Error()))
+	}
+
+	var (
+		results    = make([]*todov1.BatchCreateResult, len(req.Requests))
+		operations []*operation.Operation
+		messages   []*outboxrepository.Message
+		indexes    []int
+	)
+
+	for i, r := range req.Requests {
+		if r == nil {
+			results[i] = batchCreateError(status.New(codes.InvalidArgument, "todo must be not nil"))
+			continue
+		}
+
+		if err := validation.Validate(r); err != nil {
+			results[i] = batchCreateError(status.Convert(err))
+			continue
+		}
+
+		// Each todo gets its own idempotency key so that retrying the batch collapses todo by todo.
+		itemKey := idempotency.ItemKey(key, i)
+		id, operationID := newIDs(itemKey)
+
+		message, err := svc.newMessage(id, operationID, r, svc.recordHeaders(ctx, itemKey))
+		if err != nil {
+			log.Println(fmt.Sprintf("could not marshal event: %v", err))
+			tracing.SetError(opentracing.SpanFromContext(ctx), err, otlog.Int(batchIndexKey, i))
+			results[i] = batchCreateError(status.New(codes.Internal, "could not marshal event"))
+			continue
+		}
+
+		op, err := newOperation(id, operationID, itemKey, r)
+		if err != nil {
+			log.Println(fmt.Sprintf("could not hash request: %v", err))
+			tracing.SetError(opentracing.SpanFromContext(ctx), err, otlog.Int(batchIndexKey, i))
+			results[i] = batchCreateError(status.New(codes.Internal, "could not hash request"))
+			continue
+		}
+
+		results[i] = &todov1.BatchCreateResult{Id: id, OperationId: operationID}
+		operations = append(operations, op)
+		messages = append(messages, message)
+		indexes = append(indexes, i)
 	}
 
-	var saramaHeaders []sarama.RecordHeader
+	if len(messages) == 0 {
+		return &todov1.BatchCreateResponse{Results: results}, nil
+	}
+
+	if reason, err := svc.store(ctx, operations, messages...); err != nil {
+		log.Println(fmt.Sprintf("%s: %v", reason, err))
+		tracing.SetError(opentracing.SpanFromContext(ctx), err)
+
+		for _, i := range indexes {
+			results[i] = batchCreateError(status.New(storeCode(err), reason))
+		}
+	}
+
+	return &todov1.BatchCreateResponse{Results: results}, nil
+}
+
+// store creates the pending operations and adds the events completing them to the outbox within a single
+// transaction, so that an operation is never left pending without an event to complete it.
+// When that fails, it returns the reason to report along with the error.
+func (svc Service) store(ctx context.Context, operations []*operation.Operation, messages ...*outboxrepository.Message) (string, error) {
+	reason := "could not store operations and events"
+
+	err := svc.transactor.InTx(ctx, func(ctx context.Context) error {
+		for _, op := range operations {
+			if err := svc.operations.Create(ctx, op); err != nil {
+				reason = "could not create operation"
+				if errors.Is(err, operationrepository.ErrRequestMismatch) {
+					reason = "idempotency key was already used for a different request"
+				}
+				return err
+			}
+		}
+
+		if err := svc.outbox.Add(ctx, messages...); err != nil {
+			reason = "could not add event to outbox"
+			return err
+		}
+
+		return nil
+	})
+
+	return reason, err
+}
+
+// storeCode returns the code of the status reporting that storing operations failed with err.
+func storeCode(err error) codes.Code {
+	if errors.Is(err, operationrepository.ErrRequestMismatch) {
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+// scopedKey returns the idempotency key carried by the incoming metadata, if any, scoped to the tenant and the user
+// carried by the baggage. It returns an error when the key is not valid.
+func scopedKey(ctx context.Context) (string, error) {
+	key := idempotency.FromIncomingContext(ctx)
+	if err := idempotency.Validate(key); err != nil {
+		return "", err
+	}
+	return idempotency.Scope(
+		key,
+		tracing.BaggageItem(ctx, tracing.BaggageTenantID),
+		tracing.BaggageItem(ctx, tracing.BaggageUserID),
+	), nil
+}
+
+// newOperation returns the pending operation creating the todo requested by req. Operations created with an
+// idempotency key carry the hash of req, so that reusing the key for a different request can be told apart from
+// retrying it.
+func newOperation(id, operationID, idempotencyKey string, req *todov1.CreateRequest) (*operation.Operation, error) {
+	op := &operation.Operation{
+		ID:     operationID,
+		TodoID: id,
+		Status: operation.StatusPending,
+	}
+	if idempotencyKey == "" {
+		return op, nil
+	}
+
+	requestHash, err := idempotency.RequestHash(req)
+	if err != nil {
+		return nil, err
+	}
+	op.RequestHash = requestHash
+
+	return op, nil
+}
+
+// newIDs returns the ids of a new todo and of the operation creating it. They're derived from the scoped idempotency
+// key, when there's one, so that retried requests return the ids of the todo created by the first attempt.
+func newIDs(idempotencyKey string) (string, string) {
+	if idempotencyKey == "" {
+		return todo.NewID(), todo.NewID()
+	}
+	return todo.IDFromKey(idempotencyKey), todo.OperationIDFromKey(idempotencyKey)
+}
 
+// recordHeaders returns the headers of a record carrying the span in ctx and the idempotency key, if any.
+func (svc Service) recordHeaders(ctx context.Context, idempotencyKey string) map[string]string {
 	headers := make(map[string]string)
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		_ = svc.tracer.Inject(
@@ -78,27 +324,349 @@ func (svc Service) Create(ctx context.Context, req *todov1.CreateRequest) (*todo
 		)
 	}
 
-	for k, v := range headers {
-		saramaHeaders = append(saramaHeaders, sarama.RecordHeader{
-			Key:   []byte(k),
-			Value: []byte(v),
-		})
+	if idempotencyKey != "" {
+		headers[idempotency.RecordHeaderKey] = idempotencyKey
 	}
 
-	b, err := proto.Marshal(req)
+	return headers
+}
+
+// newMessage returns the outbox message producing the event that creates the todo requested by req.
+func (svc Service) newMessage(
+	id, operationID string,
+	req *todov1.CreateRequest,
+	headers map[string]string,
+) (*outboxrepository.Message, error) {
+	b, err := proto.Marshal(&todov1.CreateTodoEvent{
+		Id:          id,
+		Message:     req.Message,
+		OperationId: operationID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       req.DueAt,
+		Priority:    req.Priority,
+		Tags:        req.Tags,
+	})
 	if err != nil {
-		log.Println(fmt.Sprintf("could not marshal request: %v", err))
-		return nil, status.Error(codes.Internal, "could not marshal request")
+		return nil, err
 	}
 
-	if err := svc.sender.SendMessage(&sarama.ProducerMessage{
+	return &outboxrepository.Message{
 		Topic:   svc.kafkaTopic,
-		Value:   sarama.ByteEncoder(b),
-		Headers: saramaHeaders,
-	}); err != nil {
-		log.Println(fmt.Sprintf("could not produce message: %v", err))
-		return nil, status.Error(codes.Internal, "could not produce message")
+		Key:     id,
+		Value:   b,
+		Headers: headers,
+	}, nil
+}
+
+// GetTodo returns a todo given its id.
+func (svc Service) GetTodo(ctx context.Context, req *todov1.GetTodoRequest) (*todov1.GetTodoResponse, error) {
+	if req == nil {
+		log.Println("received nil request for getting a todo")
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "received nil request for getting a todo"))
+	}
+
+	if req.Id == "" {
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "todo id must be not empty"))
+	}
+
+	t, err := svc.repo.Get(ctx, req.Id)
+	if err != nil {
+		return nil, repositoryError(ctx, "could not get todo", err)
+	}
+
+	return &todov1.GetTodoResponse{Todo: todo.ToProto(t)}, nil
+}
+
+// ListTodos returns a page of the stored todos.
+func (svc Service) ListTodos(ctx context.Context, req *todov1.ListTodosRequest) (*todov1.ListTodosResponse, error) {
+	if req == nil {
+		log.Println("received nil request for listing todos")
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "received nil request for listing todos"))
+	}
+
+	filter, err := listFilter(req)
+	if err != nil {
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, err.Error()))
+	}
+
+	todos, nextID, err := svc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, repositoryError(ctx, "could not list todos", err)
+	}
+
+	resp := &todov1.ListTodosResponse{Todos: make([]*todov1.Todo, 0, len(todos))}
+	for _, t := range todos {
+		resp.Todos = append(resp.Todos, todo.ToProto(t))
+	}
+
+	if nextID != 0 {
+		resp.NextPageToken, err = encodePageToken(nextID, req)
+		if err != nil {
+			log.Println(fmt.Sprintf("could not create next page token: %v", err))
+			return nil, tracing.Fail(ctx, status.Error(codes.Internal, "could not create next page token"), otlog.Error(err))
+		}
+	}
+
+	return resp, nil
+}
+
+// UpdateTodo updates the fields of an existing todo listed in the update mask, or all of them when it's empty.
+func (svc Service) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest) (*todov1.UpdateTodoResponse, error) {
+	if req == nil {
+		log.Println("received nil request for updating a todo")
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "received nil request for updating a todo"))
+	}
+
+	if req.Id == "" {
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "todo id must be not empty"))
+	}
+
+	paths := req.GetUpdateMask().GetPaths()
+	for _, path := range paths {
+		if !slices.Contains(repository.UpdatableFields, path) {
+			return nil, tracing.Fail(ctx, status.Errorf(codes.InvalidArgument, "update mask path %q is not an updatable field", path))
+		}
+	}
+
+	var validationErr error
+	if len(paths) > 0 {
+		// Fields left out of the mask are not updated, so they don't have to be valid.
+		validationErr = validation.ValidateFields(req, paths...)
+	} else {
+		validationErr = validation.Validate(req)
+	}
+	if validationErr != nil {
+		return nil, tracing.Fail(ctx, validationErr)
+	}
+
+	t, err := svc.repo.Update(ctx, &todo.Todo{
+		ID:          req.Id,
+		Message:     req.Message,
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       todo.TimeFromProto(req.DueAt),
+		Priority:    todo.PriorityFromProto(req.Priority),
+		Tags:        req.Tags,
+		Completed:   req.Completed,
+	}, paths)
+	if err != nil {
+		return nil, repositoryError(ctx, "could not update todo", err)
+	}
+
+	return &todov1.UpdateTodoResponse{Todo: todo.ToProto(t)}, nil
+}
+
+// DeleteTodo deletes an existing todo.
+func (svc Service) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest) (*todov1.DeleteTodoResponse, error) {
+	if req == nil {
+		log.Println("received nil request for deleting a todo")
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "received nil request for deleting a todo"))
+	}
+
+	if req.Id == "" {
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "todo id must be not empty"))
+	}
+
+	if err := svc.repo.Delete(ctx, req.Id); err != nil {
+		return nil, repositoryError(ctx, "could not delete todo", err)
+	}
+
+	return &todov1.DeleteTodoResponse{}, nil
+}
+
+// GetOperation returns the status of the operation creating a todo.
+func (svc Service) GetOperation(ctx context.Context, req *todov1.GetOperationRequest) (*todov1.GetOperationResponse, error) {
+	if req == nil {
+		log.Println("received nil request for getting an operation")
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "received nil request for getting an operation"))
+	}
+
+	if req.Id == "" {
+		return nil, tracing.Fail(ctx, status.Error(codes.InvalidArgument, "operation id must be not empty"))
+	}
+
+	op, err := svc.operations.Get(ctx, req.Id)
+	switch {
+	case errors.Is(err, operationrepository.ErrNotFound):
+		return nil, tracing.Fail(ctx, status.Error(codes.NotFound, "operation not found"))
+	case err != nil:
+		log.Println(fmt.Sprintf("could not get operation: %v", err))
+		return nil, tracing.Fail(ctx, status.Error(codes.Internal, "could not get operation"), otlog.Error(err))
+	}
+
+	return &todov1.GetOperationResponse{Operation: toProtoOperation(op)}, nil
+}
+
+// WatchTodos streams the todos being created, updated and deleted until the client goes away.
+// Each change is streamed within a span following from the span that originated it.
+func (svc Service) WatchTodos(req *todov1.WatchTodosRequest, stream todov1.TodoService_WatchTodosServer) error {
+	if req == nil {
+		log.Println("received nil request for watching todos")
+		return tracing.Fail(stream.Context(), status.Error(codes.InvalidArgument, "received nil request for watching todos"))
 	}
 
-	return &todov1.CreateResponse{}, nil
+	changes, unsubscribe := svc.watcher.Subscribe()
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				return tracing.Fail(ctx, status.Error(codes.Unavailable, "todo changes are not being watched anymore"))
+			}
+			if err := svc.sendChange(ctx, stream, change); err != nil {
+				return tracing.Fail(ctx, err)
+			}
+		}
+	}
+}
+
+func (svc Service) sendChange(ctx context.Context, stream todov1.TodoService_WatchTodosServer, change todo.Change) error {
+	eventType := toProtoEventType(change.Type)
+	if eventType == todov1.TodoEventType_TODO_EVENT_TYPE_UNSPECIFIED {
+		log.Println(fmt.Sprintf("skipping unknown todo change %s", change.Type))
+		return nil
+	}
+
+	var (
+		opts    []opentracing.StartSpanOption
+		traceID string
+	)
+
+	origin, err := svc.tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(change.Trace))
+	switch {
+	case err == nil:
+		opts = append(opts, opentracing.FollowsFrom(origin))
+		traceID = tracing.SpanContextTraceID(origin)
+	case opentracing.SpanFromContext(ctx) != nil:
+		opts = append(opts, opentracing.ChildOf(opentracing.SpanFromContext(ctx).Context()))
+	}
+
+	span := svc.tracer.StartSpan("watch_todos_change", opts...)
+	defer span.Finish()
+
+	span.SetTag("todo.id", change.ID)
+	span.SetTag("todo.change", string(change.Type))
+
+	resp := &todov1.WatchTodosResponse{
+		Type:    eventType,
+		TodoId:  change.ID,
+		TraceId: traceID,
+	}
+
+	if change.Type != todo.ChangeDeleted {
+		t, err := svc.repo.Get(opentracing.ContextWithSpan(ctx, span), change.ID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			// the todo has been deleted in the meantime and its deleted change follows.
+			return nil
+		case err != nil:
+			log.Println(fmt.Sprintf("could not get changed todo: %v", err))
+			tracing.SetError(span, err)
+			return status.Error(codes.Internal, "could not get changed todo")
+		}
+		resp.Todo = todo.ToProto(t)
+	}
+
+	if err := stream.Send(resp); err != nil {
+		log.Println(fmt.Sprintf("could not send todo change: %v", err))
+		tracing.SetError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+// repositoryError translates a repository error into a grpc status error, marking the span in ctx as failed.
+func repositoryError(ctx context.Context, msg string, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return tracing.Fail(ctx, status.Error(codes.NotFound, "todo not found"))
+	}
+
+	log.Println(fmt.Sprintf("%s: %v", msg, err))
+	return tracing.Fail(ctx, status.Error(codes.Internal, msg), otlog.Error(err))
+}
+
+func listFilter(req *todov1.ListTodosRequest) (repository.ListFilter, error) {
+	var filter repository.ListFilter
+
+	switch {
+	case req.PageSize < 0:
+		return filter, errors.New("page size must be not negative")
+	case req.PageSize == 0:
+		filter.Limit = defaultPageSize
+	case req.PageSize > maxPageSize:
+		filter.Limit = maxPageSize
+	default:
+		filter.Limit = int(req.PageSize)
+	}
+
+	afterID, err := decodePageToken(req)
+	if err != nil {
+		return filter, fmt.Errorf("invalid page token: %w", err)
+	}
+
+	filter.AfterID = afterID
+	filter.MessageContains = req.MessageContains
+
+	if req.CreatedAfter != nil {
+		filter.CreatedAfter = req.CreatedAfter.AsTime()
+	}
+	if req.CreatedBefore != nil {
+		filter.CreatedBefore = req.CreatedBefore.AsTime()
+	}
+
+	return filter, nil
+}
+
+func batchCreateError(st *status.Status) *todov1.BatchCreateResult {
+	e := &todov1.BatchCreateError{
+		Code:    int32(st.Code()),
+		Message: st.Message(),
+	}
+	for _, v := range validation.ViolationsFromStatus(st) {
+		e.Violations = append(e.Violations, &todov1.FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	return &todov1.BatchCreateResult{Error: e}
+}
+
+func toProtoEventType(changeType todo.ChangeType) todov1.TodoEventType {
+	switch changeType {
+	case todo.ChangeCreated:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_CREATED
+	case todo.ChangeUpdated:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_UPDATED
+	case todo.ChangeDeleted:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_DELETED
+	default:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_UNSPECIFIED
+	}
+}
+
+func toProtoOperation(op *operation.Operation) *todov1.Operation {
+	var st todov1.OperationStatus
+	switch op.Status {
+	case operation.StatusPending:
+		st = todov1.OperationStatus_OPERATION_STATUS_PENDING
+	case operation.StatusSucceeded:
+		st = todov1.OperationStatus_OPERATION_STATUS_SUCCEEDED
+	case operation.StatusFailed:
+		st = todov1.OperationStatus_OPERATION_STATUS_FAILED
+	}
+
+	return &todov1.Operation{
+		Id:           op.ID,
+		TodoId:       op.TodoID,
+		Status:       st,
+		ErrorMessage: op.Error,
+		CreatedAt:    timestamppb.New(op.CreatedAt),
+		UpdatedAt:    timestamppb.New(op.UpdatedAt),
+	}
 }
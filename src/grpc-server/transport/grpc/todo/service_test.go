@@ -4,23 +4,44 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
-	"github.com/Shopify/sarama"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	operationrepository "github.com/andream16/go-opentracing-example/src/grpc-server/operation/repository"
+	outboxrepository "github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/todo/repository"
 	"github.com/andream16/go-opentracing-example/src/grpc-server/transport/grpc/todo"
-	sendermock "github.com/andream16/go-opentracing-example/src/test/mock/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	sharedtodo "github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+	operationrepositorymock "github.com/andream16/go-opentracing-example/src/test/mock/grpc-server/operation/repository"
+	outboxrepositorymock "github.com/andream16/go-opentracing-example/src/test/mock/grpc-server/outbox/repository"
+	todorepositorymock "github.com/andream16/go-opentracing-example/src/test/mock/grpc-server/todo/repository"
+	watchermock "github.com/andream16/go-opentracing-example/src/test/mock/grpc-server/todo/watcher"
+	opentracingmock "github.com/andream16/go-opentracing-example/src/test/mock/opentracing"
+	todoclientmock "github.com/andream16/go-opentracing-example/src/test/mock/todoclient"
 	tracingmock "github.com/andream16/go-opentracing-example/src/test/mock/tracing"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
 )
 
 func TestNewService(t *testing.T) {
 	t.Run("it should return an error because the topic is not valid", func(t *testing.T) {
-		svc, err := todo.NewService("", nil, nil)
+		svc, err := todo.NewService("", nil, nil, nil, nil, nil, nil)
 
 		require.Error(t, err)
 		var e todo.InvalidServiceParameterError
@@ -28,20 +49,100 @@ func TestNewService(t *testing.T) {
 		assert.Equal(t, "invalid parameter kafka topic: must be not empty", err.Error())
 		assert.Empty(t, svc)
 	})
-	t.Run("it should return an error because the sender is not valid", func(t *testing.T) {
-		svc, err := todo.NewService("someTopic", nil, nil)
+	t.Run("it should return an error because the transactor is not valid", func(t *testing.T) {
+		svc, err := todo.NewService("someTopic", nil, nil, nil, nil, nil, nil)
 
 		require.Error(t, err)
 		var e todo.InvalidServiceParameterError
 		require.True(t, errors.As(err, &e))
-		assert.Equal(t, "invalid parameter sender: must be not nil", err.Error())
+		assert.Equal(t, "invalid parameter transactor: must be not nil", err.Error())
+		assert.Empty(t, svc)
+	})
+	t.Run("it should return an error because the outbox is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService("someTopic", executormock.NewMockTransactor(ctrl), nil, nil, nil, nil, nil)
+
+		require.Error(t, err)
+		var e todo.InvalidServiceParameterError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, "invalid parameter outbox: must be not nil", err.Error())
+		assert.Empty(t, svc)
+	})
+	t.Run("it should return an error because the repo is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+
+		require.Error(t, err)
+		var e todo.InvalidServiceParameterError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, "invalid parameter repo: must be not nil", err.Error())
+		assert.Empty(t, svc)
+	})
+	t.Run("it should return an error because the operations repo is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			nil,
+			nil,
+			nil,
+		)
+
+		require.Error(t, err)
+		var e todo.InvalidServiceParameterError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, "invalid parameter operations: must be not nil", err.Error())
+		assert.Empty(t, svc)
+	})
+	t.Run("it should return an error because the watcher is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			nil,
+			nil,
+		)
+
+		require.Error(t, err)
+		var e todo.InvalidServiceParameterError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, "invalid parameter watcher: must be not nil", err.Error())
 		assert.Empty(t, svc)
 	})
 	t.Run("it should return an error because the tracer is not valid", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, err := todo.NewService("someTopic", sendermock.NewMockSender(ctrl), nil)
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			nil,
+		)
 
 		require.Error(t, err)
 		var e todo.InvalidServiceParameterError
@@ -55,7 +156,11 @@ func TestNewService(t *testing.T) {
 
 		svc, err := todo.NewService(
 			"someTopic",
-			sendermock.NewMockSender(ctrl),
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
 			tracingmock.NewMockTracer(ctrl),
 		)
 
@@ -71,7 +176,11 @@ func TestService_Create(t *testing.T) {
 
 		svc, err := todo.NewService(
 			"someTopic",
-			sendermock.NewMockSender(ctrl),
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
 			tracingmock.NewMockTracer(ctrl),
 		)
 
@@ -86,33 +195,96 @@ func TestService_Create(t *testing.T) {
 		assert.Equal(t, "received nil request for creating a todo", st.Message())
 		assert.Nil(t, resp)
 	})
-	t.Run("it should return an error sending a message failed", func(t *testing.T) {
+	t.Run("it should return an error with the field violations because the message is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		resp, err := svc.Create(context.Background(), &todov1.CreateRequest{Message: "hello\x07"})
+		require.Error(t, err)
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, []validation.FieldViolation{
+			{Field: "message", Description: "must not contain control characters"},
+		}, validation.ViolationsFromStatus(st))
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return an error because creating the operation failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			inTx(ctrl),
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1)
+
+		resp, err := svc.Create(context.Background(), &todov1.CreateRequest{Message: "hello"})
+		require.Error(t, err)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return an error and roll the operation back because adding the event to the outbox failed", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		const topic = "someTopic"
 
 		var (
-			req        = &todov1.CreateRequest{}
-			mockSender = sendermock.NewMockSender(ctrl)
-			mockTracer = tracingmock.NewMockTracer(ctrl)
+			req            = &todov1.CreateRequest{Message: "hello"}
+			mockTransactor = executormock.NewMockTransactor(ctrl)
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			outboxErr      = errors.New("someErr")
 		)
 
 		svc, err := todo.NewService(
 			topic,
-			mockSender,
+			mockTransactor,
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
 			mockTracer,
 		)
 
 		require.NoError(t, err)
 		assert.NotNil(t, svc)
 
+		mockTransactor.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				// The transaction is rolled back, along with the operation, as fn fails.
+				err := fn(ctx)
+				assert.Equal(t, outboxErr, err)
+				return err
+			},
+		).Times(1)
 		gomock.InOrder(
-			mockSender.EXPECT().SendMessage(&sarama.ProducerMessage{
-				Topic:   topic,
-				Value:   sarama.ByteEncoder([]byte{}),
-				Headers: nil,
-			}).Return(errors.New("someErr")).Times(1),
+			mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1),
+			mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any()).Return(outboxErr).Times(1),
 		)
 
 		resp, err := svc.Create(context.Background(), req)
@@ -120,40 +292,1165 @@ func TestService_Create(t *testing.T) {
 		st, ok := status.FromError(err)
 		require.True(t, ok)
 		assert.Equal(t, codes.Internal, st.Code())
-		assert.Equal(t, "could not produce message", st.Message())
+		assert.Equal(t, "could not add event to outbox", st.Message())
 		assert.Nil(t, resp)
 	})
-	t.Run("it should return publish a message", func(t *testing.T) {
+	t.Run("it should add the event to the outbox", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		const topic = "someTopic"
 
 		var (
-			req        = &todov1.CreateRequest{}
-			mockSender = sendermock.NewMockSender(ctrl)
-			mockTracer = tracingmock.NewMockTracer(ctrl)
+			req = &todov1.CreateRequest{
+				Message:  "hello",
+				Title:    "someTitle",
+				DueAt:    timestamppb.New(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)),
+				Priority: todov1.Priority_PRIORITY_HIGH,
+				Tags:     []string{"home"},
+			}
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
 		)
 
 		svc, err := todo.NewService(
 			topic,
-			mockSender,
+			inTx(ctrl),
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
 			mockTracer,
 		)
 
 		require.NoError(t, err)
 		assert.NotNil(t, svc)
 
+		var (
+			created *operation.Operation
+			added   *outboxrepository.Message
+		)
+
 		gomock.InOrder(
-			mockSender.EXPECT().SendMessage(&sarama.ProducerMessage{
-				Topic:   topic,
-				Value:   sarama.ByteEncoder([]byte{}),
-				Headers: nil,
-			}).Return(nil).Times(1),
+			mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, op *operation.Operation) error {
+					created = op
+					return nil
+				},
+			).Times(1),
+			mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, msgs ...*outboxrepository.Message) error {
+					require.Len(t, msgs, 1)
+					added = msgs[0]
+					return nil
+				},
+			).Times(1),
 		)
 
 		resp, err := svc.Create(context.Background(), req)
 		require.NoError(t, err)
 		require.NotNil(t, resp)
+		require.NotEmpty(t, resp.Id)
+		require.NotEmpty(t, resp.OperationId)
+
+		require.NotNil(t, created)
+		assert.Equal(t, resp.OperationId, created.ID)
+		assert.Equal(t, resp.Id, created.TodoID)
+		assert.Equal(t, operation.StatusPending, created.Status)
+
+		require.NotNil(t, added)
+		assert.Equal(t, topic, added.Topic)
+		assert.Equal(t, resp.Id, added.Key)
+		assert.Empty(t, added.Headers)
+
+		var event todov1.CreateTodoEvent
+		require.NoError(t, proto.Unmarshal(added.Value, &event))
+		assert.Equal(t, resp.Id, event.Id)
+		assert.Equal(t, "hello", event.Message)
+		assert.Equal(t, resp.OperationId, event.OperationId)
+		assert.Equal(t, "someTitle", event.Title)
+		assert.True(t, req.DueAt.AsTime().Equal(event.DueAt.AsTime()))
+		assert.Equal(t, todov1.Priority_PRIORITY_HIGH, event.Priority)
+		assert.Equal(t, []string{"home"}, event.Tags)
+	})
+	t.Run("it should forward the idempotency key as a record header", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			ctx            = metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs(idempotency.MetadataKey, "someKey"),
+			)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			inTx(ctrl),
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, msgs ...*outboxrepository.Message) error {
+				require.Len(t, msgs, 1)
+				assert.Equal(t, map[string]string{idempotency.RecordHeaderKey: idempotency.Scope("someKey", "", "")}, msgs[0].Headers)
+				return nil
+			},
+		).Times(1)
+
+		resp, err := svc.Create(ctx, &todov1.CreateRequest{Message: "hello"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Id)
+	})
+	t.Run("it should return the same ids when retried with the same idempotency key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			ctx            = metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs(idempotency.MetadataKey, "someKey"),
+			)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			inTx(ctrl),
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		key := idempotency.Scope("someKey", "", "")
+
+		requestHash, err := idempotency.RequestHash(&todov1.CreateRequest{Message: "hello"})
+		require.NoError(t, err)
+
+		mockOperations.EXPECT().Create(gomock.Any(), &operation.Operation{
+			ID:          sharedtodo.OperationIDFromKey(key),
+			TodoID:      sharedtodo.IDFromKey(key),
+			Status:      operation.StatusPending,
+			RequestHash: requestHash,
+		}).Return(nil).Times(2)
+		mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		first, err := svc.Create(ctx, &todov1.CreateRequest{Message: "hello"})
+		require.NoError(t, err)
+
+		retried, err := svc.Create(ctx, &todov1.CreateRequest{Message: "hello"})
+		require.NoError(t, err)
+		assert.Equal(t, sharedtodo.IDFromKey(key), first.Id)
+		assert.Equal(t, first.Id, retried.Id)
+		assert.Equal(t, first.OperationId, retried.OperationId)
+	})
+	t.Run("it should return an error because the idempotency key was already used for a different request", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			ctx            = metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs(idempotency.MetadataKey, "someKey"),
+			)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			inTx(ctrl),
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(operationrepository.ErrRequestMismatch).Times(1)
+
+		resp, err := svc.Create(ctx, &todov1.CreateRequest{Message: "hello"})
+		require.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return different ids for the same idempotency key sent by different tenants", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			tracer         = recorder.New()
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			inTx(ctrl),
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracer,
+		)
+		require.NoError(t, err)
+
+		mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		var ids []string
+		for _, tenantID := range []string{"someTenant", "otherTenant"} {
+			span := tracer.StartSpan("someSpan")
+			span.SetBaggageItem(tracing.BaggageTenantID, tenantID)
+
+			ctx := metadata.NewIncomingContext(
+				opentracing.ContextWithSpan(context.Background(), span),
+				metadata.Pairs(idempotency.MetadataKey, "someKey"),
+			)
+
+			resp, err := svc.Create(ctx, &todov1.CreateRequest{Message: "hello"})
+			require.NoError(t, err)
+			ids = append(ids, resp.Id)
+		}
+
+		assert.NotEqual(t, ids[0], ids[1])
+	})
+}
+
+func TestService_BatchCreate(t *testing.T) {
+	t.Run("it should return an error because the batch is empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		resp, err := svc.BatchCreate(context.Background(), &todov1.BatchCreateRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return an error because the batch is too large", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		req := &todov1.BatchCreateRequest{}
+		for i := 0; i <= sharedtodo.MaxBatchSize; i++ {
+			req.Requests = append(req.Requests, &todov1.CreateRequest{Message: "hello"})
+		}
+
+		resp, err := svc.BatchCreate(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("it should add the events of the valid todos to the outbox at once and report the outcome of each of them", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			ctx            = metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs(idempotency.MetadataKey, "someKey"),
+			)
+			addedOperationID string
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			inTx(ctrl),
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2),
+			mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, msgs ...*outboxrepository.Message) error {
+					require.Len(t, msgs, 2)
+					assert.Equal(t, map[string]string{idempotency.RecordHeaderKey: idempotency.Scope("someKey/0", "", "")}, msgs[0].Headers)
+					assert.Equal(t, map[string]string{idempotency.RecordHeaderKey: idempotency.Scope("someKey/2", "", "")}, msgs[1].Headers)
+
+					var event todov1.CreateTodoEvent
+					require.NoError(t, proto.Unmarshal(msgs[1].Value, &event))
+					addedOperationID = event.OperationId
+
+					return nil
+				},
+			).Times(1),
+		)
+
+		resp, err := svc.BatchCreate(ctx, &todov1.BatchCreateRequest{
+			Requests: []*todov1.CreateRequest{
+				{Message: "hello"},
+				{Message: ""},
+				{Message: "world"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 3)
+
+		assert.NotEmpty(t, resp.Results[0].Id)
+		assert.NotEmpty(t, resp.Results[0].OperationId)
+		assert.Nil(t, resp.Results[0].Error)
+
+		require.NotNil(t, resp.Results[1].Error)
+		assert.Empty(t, resp.Results[1].Id)
+		assert.Equal(t, int32(codes.InvalidArgument), resp.Results[1].Error.Code)
+		require.Len(t, resp.Results[1].Error.Violations, 1)
+		assert.Equal(t, "message", resp.Results[1].Error.Violations[0].Field)
+
+		assert.Nil(t, resp.Results[2].Error)
+		assert.Equal(t, addedOperationID, resp.Results[2].OperationId)
+	})
+	t.Run("it should roll the operations of the valid todos back because adding their events to the outbox failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTransactor = executormock.NewMockTransactor(ctrl)
+			mockOutbox     = outboxrepositorymock.NewMockWriter(ctrl)
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+			outboxErr      = errors.New("someErr")
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			mockTransactor,
+			mockOutbox,
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockTransactor.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				err := fn(ctx)
+				assert.Equal(t, outboxErr, err)
+				return err
+			},
+		).Times(1)
+		gomock.InOrder(
+			mockOperations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2),
+			mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).Return(outboxErr).Times(1),
+		)
+
+		resp, err := svc.BatchCreate(context.Background(), &todov1.BatchCreateRequest{
+			Requests: []*todov1.CreateRequest{
+				{Message: "hello"},
+				{Message: "world"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 2)
+
+		for _, result := range resp.Results {
+			require.NotNil(t, result.Error)
+			assert.Empty(t, result.Id)
+			assert.Equal(t, int32(codes.Internal), result.Error.Code)
+			assert.Equal(t, "could not add event to outbox", result.Error.Message)
+		}
+	})
+}
+
+func TestService_GetTodo(t *testing.T) {
+	t.Run("it should return an error because the request is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		resp, err := svc.GetTodo(context.Background(), &todov1.GetTodoRequest{})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, "todo id must be not empty", st.Message())
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return an error because the todo does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().Get(ctx, "1").Return(nil, repository.ErrNotFound).Times(1)
+
+		resp, err := svc.GetTodo(ctx, &todov1.GetTodoRequest{Id: "1"})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Nil(t, resp)
 	})
+	t.Run("it should return a todo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			now      = time.Now().UTC()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().Get(ctx, "1").Return(&sharedtodo.Todo{
+			ID:        "1",
+			Message:   "hello",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}, nil).Times(1)
+
+		resp, err := svc.GetTodo(ctx, &todov1.GetTodoRequest{Id: "1"})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, "1", resp.Todo.Id)
+		assert.Equal(t, "hello", resp.Todo.Message)
+		assert.True(t, now.Equal(resp.Todo.CreatedAt.AsTime()))
+		assert.True(t, now.Equal(resp.Todo.UpdatedAt.AsTime()))
+	})
+}
+
+func TestService_ListTodos(t *testing.T) {
+	t.Run("it should return an error because the page size is negative", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		resp, err := svc.ListTodos(context.Background(), &todov1.ListTodosRequest{PageSize: -1})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return an error because the page token is malformed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		resp, err := svc.ListTodos(context.Background(), &todov1.ListTodosRequest{PageToken: "!!"})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return an error because listing todos failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().
+			List(ctx, repository.ListFilter{Limit: 50}).
+			Return(nil, int64(0), errors.New("someErr")).
+			Times(1)
+
+		resp, err := svc.ListTodos(ctx, &todov1.ListTodosRequest{})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "could not list todos", st.Message())
+		assert.Nil(t, resp)
+	})
+	t.Run("it should return the last page of todos", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().List(ctx, repository.ListFilter{Limit: 50}).Return([]*sharedtodo.Todo{
+			{ID: "1", Message: "hello"},
+			{ID: "2", Message: "there"},
+		}, int64(0), nil).Times(1)
+
+		resp, err := svc.ListTodos(ctx, &todov1.ListTodosRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Todos, 2)
+		assert.Equal(t, "1", resp.Todos[0].Id)
+		assert.Equal(t, "2", resp.Todos[1].Id)
+		assert.Empty(t, resp.NextPageToken)
+	})
+	t.Run("it should page through the todos with the same filters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			createdAfter = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			mockRepo     = todorepositorymock.NewMockRepository(ctrl)
+			req          = &todov1.ListTodosRequest{
+				PageSize:        1,
+				CreatedAfter:    timestamppb.New(createdAfter),
+				MessageContains: "hello",
+			}
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockRepo.EXPECT().List(ctx, repository.ListFilter{
+				Limit:           1,
+				CreatedAfter:    createdAfter,
+				MessageContains: "hello",
+			}).Return([]*sharedtodo.Todo{{ID: "1", Message: "hello"}}, int64(1), nil).Times(1),
+			mockRepo.EXPECT().List(ctx, repository.ListFilter{
+				AfterID:         1,
+				Limit:           1,
+				CreatedAfter:    createdAfter,
+				MessageContains: "hello",
+			}).Return([]*sharedtodo.Todo{{ID: "2", Message: "hello there"}}, int64(0), nil).Times(1),
+		)
+
+		resp, err := svc.ListTodos(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Todos, 1)
+		require.NotEmpty(t, resp.NextPageToken)
+
+		req.PageToken = resp.NextPageToken
+
+		resp, err = svc.ListTodos(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Todos, 1)
+		assert.Equal(t, "2", resp.Todos[0].Id)
+		assert.Empty(t, resp.NextPageToken)
+	})
+	t.Run("it should return an error because the page token was issued for different filters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().
+			List(ctx, repository.ListFilter{Limit: 1, MessageContains: "hello"}).
+			Return([]*sharedtodo.Todo{{ID: "1", Message: "hello"}}, int64(1), nil).
+			Times(1)
+
+		resp, err := svc.ListTodos(ctx, &todov1.ListTodosRequest{PageSize: 1, MessageContains: "hello"})
+		require.NoError(t, err)
+
+		resp, err = svc.ListTodos(ctx, &todov1.ListTodosRequest{
+			PageSize:        1,
+			PageToken:       resp.NextPageToken,
+			MessageContains: "bye",
+		})
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Nil(t, resp)
+	})
+}
+
+func TestService_UpdateTodo(t *testing.T) {
+	t.Run("it should return an error because the todo does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().
+			Update(ctx, &sharedtodo.Todo{ID: "1", Message: "hello"}, nil).
+			Return(nil, repository.ErrNotFound).
+			Times(1)
+
+		resp, err := svc.UpdateTodo(ctx, &todov1.UpdateTodoRequest{Id: "1", Message: "hello"})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Nil(t, resp)
+	})
+	t.Run("it should update a todo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().
+			Update(ctx, &sharedtodo.Todo{ID: "1", Message: "hello"}, nil).
+			Return(&sharedtodo.Todo{ID: "1", Message: "hello"}, nil).
+			Times(1)
+
+		resp, err := svc.UpdateTodo(ctx, &todov1.UpdateTodoRequest{Id: "1", Message: "hello"})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", resp.Todo.Message)
+	})
+	t.Run("it should return an error because the update mask lists an unknown field", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		resp, err := svc.UpdateTodo(context.Background(), &todov1.UpdateTodoRequest{
+			Id:         "1",
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"created_at"}},
+		})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Nil(t, resp)
+	})
+	t.Run("it should only update the fields listed in the update mask", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		// The message is empty, but it's not validated as it's not updated.
+		mockRepo.EXPECT().
+			Update(ctx, &sharedtodo.Todo{ID: "1", Completed: true}, []string{"completed"}).
+			Return(&sharedtodo.Todo{ID: "1", Message: "hello", Completed: true}, nil).
+			Times(1)
+
+		resp, err := svc.UpdateTodo(ctx, &todov1.UpdateTodoRequest{
+			Id:         "1",
+			Completed:  true,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"completed"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", resp.Todo.Message)
+		assert.True(t, resp.Todo.Completed)
+	})
+}
+
+func TestService_DeleteTodo(t *testing.T) {
+	t.Run("it should return an error because deleting the todo failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().Delete(ctx, "1").Return(errors.New("someErr")).Times(1)
+
+		resp, err := svc.DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: "1"})
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "could not delete todo", st.Message())
+		assert.Nil(t, resp)
+	})
+	t.Run("it should delete a todo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx      = context.Background()
+			mockRepo = todorepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockRepo.EXPECT().Delete(ctx, "1").Return(nil).Times(1)
+
+		resp, err := svc.DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+}
+
+func TestService_GetOperation(t *testing.T) {
+	t.Run("it should return an error because the operation id is empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		_, err = svc.GetOperation(context.Background(), &todov1.GetOperationRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+	t.Run("it should return an error because the operation does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx            = context.Background()
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockOperations.EXPECT().Get(ctx, "someID").Return(nil, operationrepository.ErrNotFound).Times(1)
+
+		_, err = svc.GetOperation(ctx, &todov1.GetOperationRequest{Id: "someID"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+	t.Run("it should return a failed operation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx            = context.Background()
+			now            = time.Now().UTC()
+			mockOperations = operationrepositorymock.NewMockRepository(ctrl)
+		)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			mockOperations,
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockOperations.EXPECT().Get(ctx, "someID").Return(&operation.Operation{
+			ID:        "someID",
+			TodoID:    "someTodoID",
+			Status:    operation.StatusFailed,
+			Error:     "someErr",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}, nil).Times(1)
+
+		resp, err := svc.GetOperation(ctx, &todov1.GetOperationRequest{Id: "someID"})
+		require.NoError(t, err)
+		assert.Equal(t, "someID", resp.Operation.Id)
+		assert.Equal(t, "someTodoID", resp.Operation.TodoId)
+		assert.Equal(t, todov1.OperationStatus_OPERATION_STATUS_FAILED, resp.Operation.Status)
+		assert.Equal(t, "someErr", resp.Operation.ErrorMessage)
+		assert.Equal(t, now, resp.Operation.UpdatedAt.AsTime())
+	})
+}
+
+func TestService_WatchTodos(t *testing.T) {
+	t.Run("it should return an error because the request is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			watchermock.NewMockSubscriber(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		mockStream := todoclientmock.NewMockTodoService_WatchTodosServer(ctrl)
+		mockStream.EXPECT().Context().Return(context.Background()).Times(1)
+
+		err = svc.WatchTodos(nil, mockStream)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+	t.Run("it should return an error because the changed todo could not be fetched", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx             = context.Background()
+			mockRepo        = todorepositorymock.NewMockRepository(ctrl)
+			mockWatcher     = watchermock.NewMockSubscriber(ctrl)
+			mockTracer      = tracingmock.NewMockTracer(ctrl)
+			mockSpan        = opentracingmock.NewMockSpan(ctrl)
+			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
+			mockStream      = todoclientmock.NewMockTodoService_WatchTodosServer(ctrl)
+			changes         = make(chan sharedtodo.Change, 1)
+			trace           = map[string]string{"uber-trace-id": "someTrace"}
+		)
+
+		changes <- sharedtodo.Change{Type: sharedtodo.ChangeUpdated, ID: "someID", Trace: trace}
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			mockWatcher,
+			mockTracer,
+		)
+		require.NoError(t, err)
+
+		var unsubscribed bool
+
+		gomock.InOrder(
+			mockWatcher.EXPECT().Subscribe().Return(changes, func() { unsubscribed = true }).Times(1),
+			mockStream.EXPECT().Context().Return(ctx).Times(1),
+			mockTracer.EXPECT().
+				Extract(opentracing.TextMap, opentracing.TextMapCarrier(trace)).
+				Return(mockSpanContext, nil).
+				Times(1),
+			mockTracer.EXPECT().StartSpan("watch_todos_change", gomock.Any()).Return(mockSpan).Times(1),
+			mockSpan.EXPECT().SetTag("todo.id", "someID").Times(1),
+			mockSpan.EXPECT().SetTag("todo.change", "updated").Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockRepo.EXPECT().Get(gomock.Any(), "someID").Return(nil, errors.New("someErr")).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+		)
+
+		err = svc.WatchTodos(&todov1.WatchTodosRequest{}, mockStream)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.True(t, unsubscribed)
+	})
+	t.Run("it should stream the changes until they are not watched anymore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx             = context.Background()
+			mockRepo        = todorepositorymock.NewMockRepository(ctrl)
+			mockWatcher     = watchermock.NewMockSubscriber(ctrl)
+			mockTracer      = tracingmock.NewMockTracer(ctrl)
+			mockSpan        = opentracingmock.NewMockSpan(ctrl)
+			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
+			mockStream      = todoclientmock.NewMockTodoService_WatchTodosServer(ctrl)
+			changes         = make(chan sharedtodo.Change, 3)
+			trace           = map[string]string{"uber-trace-id": "someTrace"}
+			createdAt       = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		)
+
+		changes <- sharedtodo.Change{Type: sharedtodo.ChangeCreated, ID: "someID", Trace: trace}
+		changes <- sharedtodo.Change{Type: sharedtodo.ChangeUpdated, ID: "goneID", Trace: trace}
+		changes <- sharedtodo.Change{Type: sharedtodo.ChangeDeleted, ID: "someID"}
+		close(changes)
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			mockRepo,
+			operationrepositorymock.NewMockRepository(ctrl),
+			mockWatcher,
+			mockTracer,
+		)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockWatcher.EXPECT().Subscribe().Return(changes, func() {}).Times(1),
+			mockStream.EXPECT().Context().Return(ctx).Times(1),
+			mockTracer.EXPECT().
+				Extract(opentracing.TextMap, opentracing.TextMapCarrier(trace)).
+				Return(mockSpanContext, nil).
+				Times(1),
+			mockTracer.EXPECT().StartSpan("watch_todos_change", gomock.Any()).Return(mockSpan).Times(1),
+			mockSpan.EXPECT().SetTag("todo.id", "someID").Times(1),
+			mockSpan.EXPECT().SetTag("todo.change", "created").Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockRepo.EXPECT().
+				Get(gomock.Any(), "someID").
+				Return(&sharedtodo.Todo{ID: "someID", Message: "hello", CreatedAt: createdAt, UpdatedAt: createdAt}, nil).
+				Times(1),
+			mockStream.EXPECT().Send(&todov1.WatchTodosResponse{
+				Type:   todov1.TodoEventType_TODO_EVENT_TYPE_CREATED,
+				TodoId: "someID",
+				Todo: &todov1.Todo{
+					Id:        "someID",
+					Message:   "hello",
+					CreatedAt: timestamppb.New(createdAt),
+					UpdatedAt: timestamppb.New(createdAt),
+				},
+			}).Return(nil).Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+			mockTracer.EXPECT().
+				Extract(opentracing.TextMap, opentracing.TextMapCarrier(trace)).
+				Return(mockSpanContext, nil).
+				Times(1),
+			mockTracer.EXPECT().StartSpan("watch_todos_change", gomock.Any()).Return(mockSpan).Times(1),
+			mockSpan.EXPECT().SetTag("todo.id", "goneID").Times(1),
+			mockSpan.EXPECT().SetTag("todo.change", "updated").Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockRepo.EXPECT().Get(gomock.Any(), "goneID").Return(nil, repository.ErrNotFound).Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+			mockTracer.EXPECT().
+				Extract(opentracing.TextMap, opentracing.TextMapCarrier(nil)).
+				Return(nil, opentracing.ErrSpanContextNotFound).
+				Times(1),
+			mockTracer.EXPECT().StartSpan("watch_todos_change").Return(mockSpan).Times(1),
+			mockSpan.EXPECT().SetTag("todo.id", "someID").Times(1),
+			mockSpan.EXPECT().SetTag("todo.change", "deleted").Times(1),
+			mockStream.EXPECT().Send(&todov1.WatchTodosResponse{
+				Type:   todov1.TodoEventType_TODO_EVENT_TYPE_DELETED,
+				TodoId: "someID",
+			}).Return(nil).Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+		)
+
+		err = svc.WatchTodos(&todov1.WatchTodosRequest{}, mockStream)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+	t.Run("it should stop streaming when the client goes away", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			mockWatcher = watchermock.NewMockSubscriber(ctrl)
+			mockStream  = todoclientmock.NewMockTodoService_WatchTodosServer(ctrl)
+		)
+
+		cancel()
+
+		svc, err := todo.NewService(
+			"someTopic",
+			executormock.NewMockTransactor(ctrl),
+			outboxrepositorymock.NewMockWriter(ctrl),
+			todorepositorymock.NewMockRepository(ctrl),
+			operationrepositorymock.NewMockRepository(ctrl),
+			mockWatcher,
+			tracingmock.NewMockTracer(ctrl),
+		)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockWatcher.EXPECT().Subscribe().Return(make(chan sharedtodo.Change), func() {}).Times(1),
+			mockStream.EXPECT().Context().Return(ctx).Times(1),
+		)
+
+		require.NoError(t, svc.WatchTodos(&todov1.WatchTodosRequest{}, mockStream))
+	})
+}
+
+// inTx returns a transactor running the functions it is passed, as within a transaction.
+func inTx(ctrl *gomock.Controller) *executormock.MockTransactor {
+	mockTransactor := executormock.NewMockTransactor(ctrl)
+	mockTransactor.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).AnyTimes()
+	return mockTransactor
 }
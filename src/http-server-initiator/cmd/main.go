@@ -12,6 +12,7 @@ import (
 
 	transporthttp "github.com/andream16/go-opentracing-example/src/http-server-initiator/transport/http"
 	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	sharedhttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
 )
 
 func main() {
@@ -19,15 +20,11 @@ func main() {
 
 	var (
 		httpServerHostname         string
-		jaegerAgentHost            string
-		jaegerAgentPort            string
 		httpServerReceiverHostname string
 	)
 
 	for k, v := range map[string]*string{
 		"HTTP_SERVER_HOSTNAME":          &httpServerHostname,
-		"JAEGER_AGENT_HOST":             &jaegerAgentHost,
-		"JAEGER_AGENT_PORT":             &jaegerAgentPort,
 		"HTTP_SERVER_RECEIVER_HOSTNAME": &httpServerReceiverHostname,
 	} {
 		var ok bool
@@ -44,16 +41,44 @@ func main() {
 
 	defer cancel()
 
-	tracer, err := tracing.NewJaegerTracer(serviceName, jaegerAgentHost, jaegerAgentPort)
+	samplerCfg, err := tracing.SamplerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("could not read sampler configuration: %v", err)
+	}
+
+	tracer, err := tracing.New(ctx, tracing.Config{
+		Backend:         os.Getenv("TRACING_BACKEND"),
+		ServiceName:     serviceName,
+		JaegerAgentHost: os.Getenv("JAEGER_AGENT_HOST"),
+		JaegerAgentPort: os.Getenv("JAEGER_AGENT_PORT"),
+		Sampler:         samplerCfg,
+		Propagation:     tracing.ParsePropagation(os.Getenv("TRACING_PROPAGATION")),
+	})
 	if err != nil {
 		log.Fatalf("could not create new tracer: %v", err)
 	}
 	defer tracer.Close()
 
+	baggageHeaders, err := tracing.ParseBaggageHeaders(os.Getenv("TRACING_BAGGAGE_HEADERS"))
+	if err != nil {
+		log.Fatalf("could not read baggage headers: %v", err)
+	}
+
+	retryPolicy, err := sharedhttp.RetryPolicyFromEnv()
+	if err != nil {
+		log.Fatalf("could not read retry policy: %v", err)
+	}
+
+	receiverDoer, err := sharedhttp.NewTracingDoer(httpClient, tracer, retryPolicy)
+	if err != nil {
+		log.Fatalf("could not create receiver doer: %v", err)
+	}
+
 	handler, err := transporthttp.NewHandler(
 		httpServerReceiverHostname,
-		httpClient,
+		receiverDoer,
 		tracer,
+		baggageHeaders,
 	)
 	if err != nil {
 		log.Fatalf("could not create handler: %v", err)
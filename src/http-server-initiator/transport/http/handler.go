@@ -6,57 +6,250 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
 	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	transporthttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc/codes"
 )
 
 func (h Handler) CreateTodo(w http.ResponseWriter, r *http.Request) {
 	receiverURL := h.receiverHostname + "/receiver/todo"
 
-	span := h.tracer.StartSpan("initiator_todo")
-	defer span.Finish()
+	span := opentracing.SpanFromContext(r.Context())
+
+	tracing.SetBaggageFromHeaders(span, r.Header, h.baggageHeaders)
+
+	idempotencyKey := r.Header.Get(idempotency.HeaderName)
+	if err := idempotency.Validate(idempotencyKey); err != nil {
+		log.Println(fmt.Sprintf("invalid idempotency key: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.InvalidArgument, err.Error())
+		return
+	}
 
 	var t todo.Todo
 	defer r.Body.Close()
 
-	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, todo.MaxBodySize)).Decode(&t); err != nil {
 		log.Println(fmt.Sprintf("could not deserialise request body: %s", err))
-		w.WriteHeader(http.StatusBadRequest)
+		transporthttp.WriteBodyError(w, span, err)
+		return
+	}
+
+	if err := todo.ValidateCreate(&t); err != nil {
+		log.Println(fmt.Sprintf("invalid todo: %s", err))
+		transporthttp.WriteStatusError(w, span, err)
 		return
 	}
 
 	b, err := json.Marshal(t)
 	if err != nil {
 		log.Println(fmt.Sprintf("could serialise todo: %s", err))
-		w.WriteHeader(http.StatusInternalServerError)
+		transporthttp.WriteProblem(w, span, err, codes.Internal, "could not serialise todo")
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, receiverURL, bytes.NewReader(b))
+	if err != nil {
+		log.Println(fmt.Sprintf("could not create a new http request: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Internal, "could not create receiver request")
+		return
+	}
+
+	if idempotencyKey != "" {
+		req.Header.Set(idempotency.HeaderName, idempotencyKey)
+	}
+
+	resp, err := h.doer.Do(req)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not perform receiver request: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Unavailable, "could not reach receiver")
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		writeReceiverError(w, span, resp)
+		return
+	}
+
+	var created todo.Created
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		log.Println(fmt.Sprintf("could not deserialise receiver response: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Unavailable, "malformed receiver response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !prefersAsync(r) {
+		if err := json.NewEncoder(w).Encode(created); err != nil {
+			log.Println(fmt.Sprintf("could not serialise todo: %s", err))
+		}
+		return
+	}
+
+	w.Header().Set("Location", "/initiator/operations/"+created.OperationID)
+	w.Header().Set("Preference-Applied", preferRespondAsync)
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(operation.Operation{
+		ID:     created.OperationID,
+		TodoID: created.ID,
+		Status: operation.StatusPending,
+	}); err != nil {
+		log.Println(fmt.Sprintf("could not serialise operation: %s", err))
+	}
+}
+
+func (h Handler) BatchCreateTodos(w http.ResponseWriter, r *http.Request) {
+	receiverURL := h.receiverHostname + "/receiver/todos:batch"
+
+	span := opentracing.SpanFromContext(r.Context())
+
+	tracing.SetBaggageFromHeaders(span, r.Header, h.baggageHeaders)
+
+	idempotencyKey := r.Header.Get(idempotency.HeaderName)
+	if err := idempotency.Validate(idempotencyKey); err != nil {
+		log.Println(fmt.Sprintf("invalid idempotency key: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	var batch todo.Batch
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, todo.MaxBatchBodySize)).Decode(&batch); err != nil {
+		log.Println(fmt.Sprintf("could not deserialise request body: %s", err))
+		transporthttp.WriteBodyError(w, span, err)
+		return
+	}
+
+	if err := batch.Validate(); err != nil {
+		log.Println(fmt.Sprintf("invalid batch: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	span.SetTag("batch.size", len(batch.Todos))
+
+	b, err := json.Marshal(batch)
+	if err != nil {
+		log.Println(fmt.Sprintf("could serialise batch: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Internal, "could not serialise batch")
 		return
 	}
 
 	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, receiverURL, bytes.NewReader(b))
 	if err != nil {
 		log.Println(fmt.Sprintf("could not create a new http request: %s", err))
-		w.WriteHeader(http.StatusInternalServerError)
+		transporthttp.WriteProblem(w, span, err, codes.Internal, "could not create receiver request")
+		return
+	}
+
+	if idempotencyKey != "" {
+		req.Header.Set(idempotency.HeaderName, idempotencyKey)
+	}
+
+	resp, err := h.doer.Do(req)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not perform receiver request: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Unavailable, "could not reach receiver")
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		writeReceiverError(w, span, resp)
+		return
+	}
+
+	var result todo.BatchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Println(fmt.Sprintf("could not deserialise receiver response: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Unavailable, "malformed receiver response")
 		return
 	}
 
-	ext.HTTPUrl.Set(span, receiverURL)
-	ext.HTTPMethod.Set(span, http.MethodPost)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Println(fmt.Sprintf("could not serialise batch result: %s", err))
+	}
+}
+
+func (h Handler) GetOperation(w http.ResponseWriter, r *http.Request) {
+	receiverURL := h.receiverHostname + "/receiver/operations/" + url.PathEscape(mux.Vars(r)["id"])
 
-	if err := h.tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header)); err != nil {
-		log.Println(fmt.Sprintf("could not inject tracing headers: %s", err))
+	span := opentracing.SpanFromContext(r.Context())
+
+	tracing.SetBaggageFromHeaders(span, r.Header, h.baggageHeaders)
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, receiverURL, nil)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not create a new http request: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Internal, "could not create receiver request")
+		return
 	}
 
 	resp, err := h.doer.Do(req)
 	if err != nil {
 		log.Println(fmt.Sprintf("could not perform receiver request: %s", err))
-		w.WriteHeader(http.StatusInternalServerError)
+		transporthttp.WriteProblem(w, span, err, codes.Unavailable, "could not reach receiver")
 		return
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
-		w.WriteHeader(http.StatusServiceUnavailable)
+		writeReceiverError(w, span, resp)
+		return
+	}
+
+	var op operation.Operation
+	if err := json.NewDecoder(resp.Body).Decode(&op); err != nil {
+		log.Println(fmt.Sprintf("could not deserialise receiver response: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Unavailable, "malformed receiver response")
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(op); err != nil {
+		log.Println(fmt.Sprintf("could not serialise operation: %s", err))
+	}
+}
+
+// writeReceiverError propagates the problem returned by the receiver and marks span as failed because of it.
+// Responses that don't carry a problem are reported as the receiver being unavailable.
+func writeReceiverError(w http.ResponseWriter, span opentracing.Span, resp *http.Response) {
+	problem, err := transporthttp.ReadProblem(resp)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not read receiver problem for status %d: %s", resp.StatusCode, err))
+		transporthttp.WriteProblem(w, span, err, codes.Unavailable, "unexpected receiver response")
+		return
+	}
+
+	problem.Trace(span, nil)
+	problem.Write(w)
+}
+
+const preferRespondAsync = "respond-async"
+
+// prefersAsync reports whether the client asked not to wait for the todo to be persisted
+// by sending the respond-async preference defined in RFC 7240.
+func prefersAsync(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), preferRespondAsync) {
+				return true
+			}
+		}
+	}
+	return false
 }
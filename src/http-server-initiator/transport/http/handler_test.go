@@ -2,16 +2,26 @@ package http_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	transporthttp "github.com/andream16/go-opentracing-example/src/http-server-initiator/transport/http"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	sharedhttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
 	opentracingmock "github.com/andream16/go-opentracing-example/src/test/mock/opentracing"
 	tracingmock "github.com/andream16/go-opentracing-example/src/test/mock/tracing"
 	transporthttpmock "github.com/andream16/go-opentracing-example/src/test/mock/transport/http"
@@ -31,18 +41,182 @@ func TestHandler_CreateTodo(t *testing.T) {
 			recorder   = httptest.NewRecorder()
 		)
 
-		handler, err := transporthttp.NewHandler(someHostname, &http.Client{}, mockTracer)
+		handler, err := transporthttp.NewHandler(someHostname, &http.Client{}, mockTracer, nil)
 		require.NoError(t, err)
 
 		gomock.InOrder(
-			mockTracer.EXPECT().StartSpan("initiator_todo").Return(mockSpan).Times(1),
-			mockSpan.EXPECT().Finish().Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
 		)
 
-		handler.CreateTodo(recorder, req)
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
 
 		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
 	})
+	t.Run("it should return http.StatusRequestEntityTooLarge because the request body is too large", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			body       = `{"message":"` + strings.Repeat("a", int(todo.MaxBodySize)) + `"}`
+			req        = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			recorder   = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, &http.Client{}, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusRequestEntityTooLarge)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Result().StatusCode)
+	})
+	t.Run("it should return http.StatusBadRequest because the priority is unknown", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"message" : "hello", "priority": "urgent"}`),
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, &http.Client{}, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+	t.Run("it should return http.StatusBadRequest because the idempotency key is too long", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"message" : "hello"}`))
+			recorder   = httptest.NewRecorder()
+		)
+
+		req.Header.Set(idempotency.HeaderName, strings.Repeat("k", idempotency.MaxKeyLength+1))
+
+		handler, err := transporthttp.NewHandler(someHostname, &http.Client{}, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+	t.Run("it should return http.StatusBadRequest with the field violations because the message is empty", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"message" : ""}`))
+			recorder   = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, transporthttpmock.NewMockDoer(ctrl), mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+
+		var problem sharedhttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+		assert.Equal(t, "INVALID_ARGUMENT", problem.Code)
+		assert.Equal(t, []validation.FieldViolation{
+			{Field: "message", Description: "must be at least 1 characters long"},
+		}, problem.Violations)
+	})
+	t.Run("it should return http.StatusBadRequest with the field violations because the todo is completed", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"message" : "hello", "completed": true}`))
+			recorder   = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, transporthttpmock.NewMockDoer(ctrl), mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+
+		var problem sharedhttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+		assert.Equal(t, "INVALID_ARGUMENT", problem.Code)
+		assert.Equal(t, []validation.FieldViolation{
+			{Field: "completed", Description: "must be false when creating a todo"},
+		}, problem.Violations)
+	})
 	t.Run("it should return http.StatusServiceUnavailable because the request failed", func(t *testing.T) {
 		const someHostname = "http://hello:8080"
 
@@ -50,11 +224,10 @@ func TestHandler_CreateTodo(t *testing.T) {
 		defer ctrl.Finish()
 
 		var (
-			mockDoer        = transporthttpmock.NewMockDoer(ctrl)
-			mockTracer      = tracingmock.NewMockTracer(ctrl)
-			mockSpan        = opentracingmock.NewMockSpan(ctrl)
-			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
-			req             = httptest.NewRequest(
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(
 				http.MethodPost,
 				"/",
 				bytes.NewBufferString(`{"message" : "hello"}`),
@@ -62,31 +235,77 @@ func TestHandler_CreateTodo(t *testing.T) {
 			recorder = httptest.NewRecorder()
 			resp     = &http.Response{
 				StatusCode: http.StatusTeapot,
+				Body:       io.NopCloser(bytes.NewBufferString(``)),
 			}
 		)
 
-		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer)
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
 		require.NoError(t, err)
 
 		gomock.InOrder(
-			mockTracer.EXPECT().StartSpan("initiator_todo").Return(mockSpan).Times(1),
-			mockSpan.EXPECT().SetTag("http.url", someHostname+"/receiver/todo").Times(1),
-			mockSpan.EXPECT().SetTag("http.method", http.MethodPost).Times(1),
-			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
-			mockTracer.EXPECT().
-				Inject(
-					mockSpanContext,
-					opentracing.HTTPHeaders,
-					opentracing.HTTPHeadersCarrier(req.Header),
-				).Return(nil).Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
 			mockDoer.EXPECT().Do(gomock.Any()).Return(resp, nil),
-			mockSpan.EXPECT().Finish().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusServiceUnavailable)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
 		)
 
-		handler.CreateTodo(recorder, req)
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
 
 		assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
 	})
+	t.Run("it should propagate the problem returned by the receiver", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"message" : "hey there"}`),
+			)
+			recorder = httptest.NewRecorder()
+			resp     = &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Header:     http.Header{"Content-Type": []string{sharedhttp.ProblemContentType}},
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"title":"Bad Request","status":400,"code":"INVALID_ARGUMENT","message":"someErr","trace_id":"someTraceID"}`,
+				)),
+			}
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).Return(resp, nil),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+		assert.Equal(t, sharedhttp.ProblemContentType, recorder.Result().Header.Get("Content-Type"))
+
+		var problem sharedhttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+		assert.Equal(t, sharedhttp.Problem{
+			Title:   "Bad Request",
+			Status:  http.StatusBadRequest,
+			Code:    "INVALID_ARGUMENT",
+			Message: "someErr",
+			TraceID: "someTraceID",
+		}, problem)
+	})
 	t.Run("it should return http.StatusOK because a todo has been created", func(t *testing.T) {
 		const someHostname = "http://hello:8080"
 
@@ -94,11 +313,10 @@ func TestHandler_CreateTodo(t *testing.T) {
 		defer ctrl.Finish()
 
 		var (
-			mockDoer        = transporthttpmock.NewMockDoer(ctrl)
-			mockTracer      = tracingmock.NewMockTracer(ctrl)
-			mockSpan        = opentracingmock.NewMockSpan(ctrl)
-			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
-			req             = httptest.NewRequest(
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(
 				http.MethodPost,
 				"/",
 				bytes.NewBufferString(`{"message" : "hello"}`),
@@ -106,29 +324,290 @@ func TestHandler_CreateTodo(t *testing.T) {
 			recorder = httptest.NewRecorder()
 			resp     = &http.Response{
 				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"id":"someID","message":"hello"}`)),
 			}
 		)
 
-		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer)
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
 		require.NoError(t, err)
 
 		gomock.InOrder(
-			mockTracer.EXPECT().StartSpan("initiator_todo").Return(mockSpan).Times(1),
-			mockSpan.EXPECT().SetTag("http.url", someHostname+"/receiver/todo").Times(1),
-			mockSpan.EXPECT().SetTag("http.method", http.MethodPost).Times(1),
-			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
-			mockTracer.EXPECT().
-				Inject(
-					mockSpanContext,
-					opentracing.HTTPHeaders,
-					opentracing.HTTPHeadersCarrier(req.Header),
-				).Return(nil).Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
 			mockDoer.EXPECT().Do(gomock.Any()).Return(resp, nil),
-			mockSpan.EXPECT().Finish().Times(1),
 		)
 
-		handler.CreateTodo(recorder, req)
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var created todo.Todo
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&created))
+		assert.Equal(t, "someID", created.ID)
+		assert.Equal(t, "hello", created.Message)
+	})
+	t.Run("it should forward the idempotency key to the receiver", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"message" : "hello"}`),
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		req.Header.Set(idempotency.HeaderName, "someKey")
+
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+				assert.Equal(t, "someKey", r.Header.Get(idempotency.HeaderName))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"id":"someID","message":"hello"}`)),
+				}, nil
+			}),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
 
 		assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
 	})
+	t.Run("it should return http.StatusAccepted with the operation because the client prefers to respond async", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"message" : "hello"}`),
+			)
+			recorder = httptest.NewRecorder()
+			resp     = &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"id":"someID","message":"hello","operation_id":"someOperationID"}`,
+				)),
+			}
+		)
+
+		req.Header.Set("Prefer", "wait=10, respond-async")
+
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).Return(resp, nil),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		result := recorder.Result()
+		require.Equal(t, http.StatusAccepted, result.StatusCode)
+		assert.Equal(t, "/initiator/operations/someOperationID", result.Header.Get("Location"))
+		assert.Equal(t, "respond-async", result.Header.Get("Preference-Applied"))
+
+		var op operation.Operation
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&op))
+		assert.Equal(t, "someOperationID", op.ID)
+		assert.Equal(t, "someID", op.TodoID)
+		assert.Equal(t, operation.StatusPending, op.Status)
+	})
+}
+
+func TestHandler_BatchCreateTodos(t *testing.T) {
+	t.Run("it should return http.StatusBadRequest because the batch is too large", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			todos      = strings.Repeat(`{"message":"hello"},`, todo.MaxBatchSize)
+			req        = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"todos":[`+todos+`{"message":"hello"}]}`),
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, transporthttpmock.NewMockDoer(ctrl), mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.BatchCreateTodos(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+	t.Run("it should return the outcome of each todo of the batch", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"todos":[{"message":"hello"},{"message":""}]}`),
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		req.Header.Set(idempotency.HeaderName, "someKey")
+
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().SetTag("batch.size", 2).Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+				assert.Equal(t, someHostname+"/receiver/todos:batch", r.URL.String())
+				assert.Equal(t, "someKey", r.Header.Get(idempotency.HeaderName))
+
+				var batch todo.Batch
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+				assert.Len(t, batch.Todos, 2)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: io.NopCloser(bytes.NewBufferString(`{"results":[` +
+						`{"id":"someID","operation_id":"someOperationID"},` +
+						`{"error":{"status":400,"code":"INVALID_ARGUMENT","message":"invalid message"}}]}`)),
+				}, nil
+			}),
+		)
+
+		handler.BatchCreateTodos(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var result todo.BatchResult
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		assert.Equal(t, todo.BatchResult{
+			Results: []todo.BatchItemResult{
+				{ID: "someID", OperationID: "someOperationID"},
+				{Error: &todo.BatchItemError{
+					Status:  http.StatusBadRequest,
+					Code:    "INVALID_ARGUMENT",
+					Message: "invalid message",
+				}},
+			},
+		}, result)
+	})
+}
+
+func TestHandler_GetOperation(t *testing.T) {
+	t.Run("it should return http.StatusNotFound because the receiver could not find the operation", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(http.MethodGet, "/initiator/operations/someID", nil)
+			recorder   = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{"Content-Type": []string{sharedhttp.ProblemContentType}},
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"title":"Not Found","status":404,"code":"NOT_FOUND","message":"operation not found","trace_id":"someTraceID"}`,
+				)),
+			}, nil),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusNotFound)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.GetOperation(recorder, mux.SetURLVars(withSpan(req, mockSpan), map[string]string{"id": "someID"}))
+
+		require.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+
+		var problem sharedhttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+		assert.Equal(t, "NOT_FOUND", problem.Code)
+		assert.Equal(t, "someTraceID", problem.TraceID)
+	})
+	t.Run("it should return the operation", func(t *testing.T) {
+		const someHostname = "http://hello:8080"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockDoer   = transporthttpmock.NewMockDoer(ctrl)
+			mockTracer = tracingmock.NewMockTracer(ctrl)
+			mockSpan   = opentracingmock.NewMockSpan(ctrl)
+			req        = httptest.NewRequest(http.MethodGet, "/initiator/operations/someID", nil)
+			recorder   = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(someHostname, mockDoer, mockTracer, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"id":"someID","todo_id":"someTodoID","status":"failed","error":"someErr"}`,
+				)),
+			}, nil),
+		)
+
+		handler.GetOperation(recorder, mux.SetURLVars(withSpan(req, mockSpan), map[string]string{"id": "someID"}))
+
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var op operation.Operation
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&op))
+		assert.Equal(t, operation.StatusFailed, op.Status)
+		assert.Equal(t, "someErr", op.Error)
+	})
+}
+
+// withSpan returns a copy of r carrying span, as the tracing middleware does.
+func withSpan(r *http.Request, span opentracing.Span) *http.Request {
+	return r.WithContext(opentracing.ContextWithSpan(r.Context(), span))
 }
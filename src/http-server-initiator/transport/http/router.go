@@ -15,7 +15,7 @@ type Handler struct {
 	receiverHostname string
 	doer             transporthttp.Doer
 	router           *mux.Router
-	tracer           tracing.Tracer
+	baggageHeaders   map[string]string
 }
 
 // InvalidHandlerParameterError is used when an invalid parameter is passed to NewHandler.
@@ -28,11 +28,15 @@ func (i InvalidHandlerParameterError) Error() string {
 	return fmt.Sprintf("invalid parameter %s: %s", i.parameter, i.reason)
 }
 
-// NewHandler returns a new http handler.
+// NewHandler returns a new http handler calling the receiver through doer, which is expected to trace
+// the receiver requests, e.g. a transporthttp.TracingDoer.
+// Requests are traced by transporthttp.NewTracingMiddleware, handlers get their span from the request context.
+// baggageHeaders maps the request headers lifted into the baggage of the request span to their baggage items.
 func NewHandler(
 	receiverHostname string,
 	doer transporthttp.Doer,
 	tracer tracing.Tracer,
+	baggageHeaders map[string]string,
 ) (Handler, error) {
 	handler := Handler{}
 
@@ -45,12 +49,20 @@ func NewHandler(
 		return handler, InvalidHandlerParameterError{parameter: "tracer", reason: "cannot be nil"}
 	}
 
+	tracingMiddleware, err := transporthttp.NewTracingMiddleware(tracer)
+	if err != nil {
+		return handler, err
+	}
+
 	handler.doer = doer
 	handler.receiverHostname = receiverHostname
 	handler.router = mux.NewRouter()
-	handler.tracer = tracer
+	handler.router.Use(tracingMiddleware)
+	handler.baggageHeaders = baggageHeaders
 
 	handler.Router().HandleFunc("/initiator/todo", handler.CreateTodo).Methods(http.MethodPost)
+	handler.Router().HandleFunc("/initiator/todos:batch", handler.BatchCreateTodos).Methods(http.MethodPost)
+	handler.Router().HandleFunc("/initiator/operations/{id}", handler.GetOperation).Methods(http.MethodGet)
 
 	return handler, nil
 }
@@ -14,7 +14,7 @@ import (
 
 func TestNewHandler(t *testing.T) {
 	t.Run("it should return an error because the receiver host is invalid", func(t *testing.T) {
-		handler, err := transporthttp.NewHandler("", nil, nil)
+		handler, err := transporthttp.NewHandler("", nil, nil, nil)
 
 		require.Error(t, err)
 		var e transporthttp.InvalidHandlerParameterError
@@ -23,7 +23,7 @@ func TestNewHandler(t *testing.T) {
 		assert.Empty(t, handler)
 	})
 	t.Run("it should return an error because the http client is invalid", func(t *testing.T) {
-		handler, err := transporthttp.NewHandler("someHostName", nil, nil)
+		handler, err := transporthttp.NewHandler("someHostName", nil, nil, nil)
 
 		require.Error(t, err)
 		var e transporthttp.InvalidHandlerParameterError
@@ -32,7 +32,7 @@ func TestNewHandler(t *testing.T) {
 		assert.Empty(t, handler)
 	})
 	t.Run("it should return an error because the tracer is invalid", func(t *testing.T) {
-		handler, err := transporthttp.NewHandler("someHostName", &http.Client{}, nil)
+		handler, err := transporthttp.NewHandler("someHostName", &http.Client{}, nil, nil)
 
 		require.Error(t, err)
 		var e transporthttp.InvalidHandlerParameterError
@@ -45,6 +45,7 @@ func TestNewHandler(t *testing.T) {
 			"someHostName",
 			&http.Client{},
 			tracing.JaegerTracer{},
+			tracing.DefaultBaggageHeaders,
 		)
 
 		require.NoError(t, err)
@@ -58,6 +59,7 @@ func TestHandler_Router(t *testing.T) {
 			"someHostName",
 			&http.Client{},
 			tracing.JaegerTracer{},
+			tracing.DefaultBaggageHeaders,
 		)
 
 		require.NoError(t, err)
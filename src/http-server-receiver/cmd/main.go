@@ -23,15 +23,11 @@ func main() {
 	var (
 		httpServerHostname string
 		grpcServerHostname string
-		jaegerAgentHost    string
-		jaegerAgentPort    string
 	)
 
 	for k, v := range map[string]*string{
 		"HTTP_SERVER_HOSTNAME": &httpServerHostname,
 		"GRPC_SERVER_HOSTNAME": &grpcServerHostname,
-		"JAEGER_AGENT_HOST":    &jaegerAgentHost,
-		"JAEGER_AGENT_PORT":    &jaegerAgentPort,
 	} {
 		var ok bool
 		*v, ok = os.LookupEnv(k)
@@ -43,7 +39,19 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	tracer, err := tracing.NewJaegerTracer(serviceName, jaegerAgentHost, jaegerAgentPort)
+	samplerCfg, err := tracing.SamplerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("could not read sampler configuration: %v", err)
+	}
+
+	tracer, err := tracing.New(ctx, tracing.Config{
+		Backend:         os.Getenv("TRACING_BACKEND"),
+		ServiceName:     serviceName,
+		JaegerAgentHost: os.Getenv("JAEGER_AGENT_HOST"),
+		JaegerAgentPort: os.Getenv("JAEGER_AGENT_PORT"),
+		Sampler:         samplerCfg,
+		Propagation:     tracing.ParsePropagation(os.Getenv("TRACING_PROPAGATION")),
+	})
 	if err != nil {
 		log.Fatalf("could not create new tracer: %v", err)
 	}
@@ -2,41 +2,257 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
 	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	transporthttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
 )
 
 func (h Handler) CreateTodo(w http.ResponseWriter, r *http.Request) {
-	spanCtx, err := h.tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
-	if err != nil {
-		log.Println(fmt.Sprintf("could not extract tracing headers: %s", err))
-	}
+	span := opentracing.SpanFromContext(r.Context())
 
-	span := h.tracer.StartSpan("receiver_todo", ext.RPCServerOption(spanCtx))
-	defer span.Finish()
+	idempotencyKey := r.Header.Get(idempotency.HeaderName)
+	if err := idempotency.Validate(idempotencyKey); err != nil {
+		log.Println(fmt.Sprintf("invalid idempotency key: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.InvalidArgument, err.Error())
+		return
+	}
 
 	var t todo.Todo
-	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, todo.MaxBodySize)).Decode(&t); err != nil {
 		log.Println(fmt.Sprintf("could not deserialise request body: %s", err))
-		w.WriteHeader(http.StatusBadRequest)
+		transporthttp.WriteBodyError(w, span, err)
 		return
 	}
 
 	defer r.Body.Close()
 
-	if _, err := h.todoSvcClient.Create(
-		opentracing.ContextWithSpan(r.Context(), span),
-		&todov1.CreateRequest{Message: t.Message},
-	); err != nil {
+	if err := todo.ValidateCreate(&t); err != nil {
+		log.Println(fmt.Sprintf("invalid todo: %s", err))
+		transporthttp.WriteStatusError(w, span, err)
+		return
+	}
+
+	req := todo.ToCreateRequest(&t)
+
+	resp, err := h.todoSvcClient.Create(
+		idempotency.OutgoingContext(r.Context(), idempotencyKey),
+		req,
+	)
+	if err != nil {
 		log.Println(fmt.Sprintf("could not create todo: %s", err))
-		w.WriteHeader(http.StatusServiceUnavailable)
+		transporthttp.WriteStatusError(w, span, err)
+		return
+	}
+
+	t.ID = resp.Id
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(todo.Created{Todo: t, OperationID: resp.OperationId}); err != nil {
+		log.Println(fmt.Sprintf("could not serialise todo: %s", err))
+	}
+}
+
+func (h Handler) BatchCreateTodos(w http.ResponseWriter, r *http.Request) {
+	span := opentracing.SpanFromContext(r.Context())
+
+	idempotencyKey := r.Header.Get(idempotency.HeaderName)
+	if err := idempotency.Validate(idempotencyKey); err != nil {
+		log.Println(fmt.Sprintf("invalid idempotency key: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	var batch todo.Batch
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, todo.MaxBatchBodySize)).Decode(&batch); err != nil {
+		log.Println(fmt.Sprintf("could not deserialise request body: %s", err))
+		transporthttp.WriteBodyError(w, span, err)
+		return
+	}
+
+	defer r.Body.Close()
+
+	if err := batch.Validate(); err != nil {
+		log.Println(fmt.Sprintf("invalid batch: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	span.SetTag("batch.size", len(batch.Todos))
+
+	// Todos are validated one by one by the service, which reports the violations of each of them.
+	req := &todov1.BatchCreateRequest{Requests: make([]*todov1.CreateRequest, 0, len(batch.Todos))}
+	for i := range batch.Todos {
+		req.Requests = append(req.Requests, todo.ToCreateRequest(&batch.Todos[i]))
+	}
+
+	resp, err := h.todoSvcClient.BatchCreate(
+		idempotency.OutgoingContext(r.Context(), idempotencyKey),
+		req,
+	)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not create todos: %s", err))
+		transporthttp.WriteStatusError(w, span, err)
+		return
+	}
+
+	result := todo.BatchResult{Results: make([]todo.BatchItemResult, 0, len(resp.Results))}
+	for _, res := range resp.Results {
+		result.Results = append(result.Results, fromProtoBatchResult(res))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Println(fmt.Sprintf("could not serialise batch result: %s", err))
+	}
+}
+
+func (h Handler) ListTodos(w http.ResponseWriter, r *http.Request) {
+	span := opentracing.SpanFromContext(r.Context())
+
+	req, err := listTodosRequest(r)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not parse query parameters: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	resp, err := h.todoSvcClient.ListTodos(r.Context(), req)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not list todos: %s", err))
+		transporthttp.WriteStatusError(w, span, err)
 		return
 	}
+
+	page := todo.Page{
+		Todos:         make([]*todo.Todo, 0, len(resp.Todos)),
+		NextPageToken: resp.NextPageToken,
+	}
+	for _, t := range resp.Todos {
+		page.Todos = append(page.Todos, todo.FromProto(t))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Println(fmt.Sprintf("could not serialise todos: %s", err))
+	}
+}
+
+func (h Handler) GetOperation(w http.ResponseWriter, r *http.Request) {
+	span := opentracing.SpanFromContext(r.Context())
+
+	resp, err := h.todoSvcClient.GetOperation(
+		r.Context(),
+		&todov1.GetOperationRequest{Id: mux.Vars(r)["id"]},
+	)
+	if err != nil {
+		log.Println(fmt.Sprintf("could not get operation: %s", err))
+		transporthttp.WriteStatusError(w, span, err)
+		return
+	}
+
+	if resp.Operation == nil {
+		err := errors.New("missing operation in response")
+		log.Println(fmt.Sprintf("could not get operation: %s", err))
+		transporthttp.WriteProblem(w, span, err, codes.Internal, "could not get operation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toOperation(resp.Operation)); err != nil {
+		log.Println(fmt.Sprintf("could not serialise operation: %s", err))
+	}
+}
+
+func toOperation(op *todov1.Operation) operation.Operation {
+	var st operation.Status
+	switch op.Status {
+	case todov1.OperationStatus_OPERATION_STATUS_PENDING:
+		st = operation.StatusPending
+	case todov1.OperationStatus_OPERATION_STATUS_SUCCEEDED:
+		st = operation.StatusSucceeded
+	case todov1.OperationStatus_OPERATION_STATUS_FAILED:
+		st = operation.StatusFailed
+	}
+
+	return operation.Operation{
+		ID:        op.Id,
+		TodoID:    op.TodoId,
+		Status:    st,
+		Error:     op.ErrorMessage,
+		CreatedAt: op.CreatedAt.AsTime(),
+		UpdatedAt: op.UpdatedAt.AsTime(),
+	}
+}
+
+// listTodosRequest builds a ListTodosRequest from the page_size, page_token, created_after,
+// created_before and message_contains query parameters. Times are expected in RFC3339 format.
+func listTodosRequest(r *http.Request) (*todov1.ListTodosRequest, error) {
+	var (
+		query = r.URL.Query()
+		req   = &todov1.ListTodosRequest{
+			PageToken:       query.Get("page_token"),
+			MessageContains: query.Get("message_contains"),
+		}
+	)
+
+	if v := query.Get("page_size"); v != "" {
+		pageSize, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page_size: %w", err)
+		}
+		req.PageSize = int32(pageSize)
+	}
+
+	for param, dst := range map[string]**timestamppb.Timestamp{
+		"created_after":  &req.CreatedAfter,
+		"created_before": &req.CreatedBefore,
+	} {
+		v := query.Get(param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", param, err)
+		}
+		*dst = timestamppb.New(t)
+	}
+
+	return req, nil
+}
+
+func fromProtoBatchResult(res *todov1.BatchCreateResult) todo.BatchItemResult {
+	if res.Error == nil {
+		return todo.BatchItemResult{ID: res.Id, OperationID: res.OperationId}
+	}
+
+	code := codes.Code(res.Error.Code)
+	itemErr := &todo.BatchItemError{
+		Status:  transporthttp.StatusFromCode(code),
+		Code:    transporthttp.CodeName(code),
+		Message: res.Error.Message,
+	}
+	for _, v := range res.Error.Violations {
+		itemErr.Violations = append(itemErr.Violations, validation.FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	return todo.BatchItemResult{Error: itemErr}
 }
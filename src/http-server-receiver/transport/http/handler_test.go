@@ -2,19 +2,33 @@ package http_test
 
 import (
 	"bytes"
-	"errors"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
 	transporthttp "github.com/andream16/go-opentracing-example/src/http-server-receiver/transport/http"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	sharedhttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
 	opentracingmock "github.com/andream16/go-opentracing-example/src/test/mock/opentracing"
 	todoclientmock "github.com/andream16/go-opentracing-example/src/test/mock/todoclient"
 	tracingmock "github.com/andream16/go-opentracing-example/src/test/mock/tracing"
@@ -38,15 +52,46 @@ func TestHandler_CreateTodo(t *testing.T) {
 		require.NoError(t, err)
 
 		gomock.InOrder(
-			mockTracer.EXPECT().Extract(opentracing.HTTPHeaders, gomock.Any()).Return(mockSpanContext, nil).Times(1),
-			mockTracer.EXPECT().StartSpan("receiver_todo", ext.RPCServerOption(mockSpanContext)).Return(mockSpan).Times(1),
-			mockSpan.EXPECT().Finish().Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
 		)
 
-		handler.CreateTodo(recorder, req)
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
 
 		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
 	})
+	t.Run("it should return http.StatusRequestEntityTooLarge because the payload is too large", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient  = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer      = tracingmock.NewMockTracer(ctrl)
+			mockSpan        = opentracingmock.NewMockSpan(ctrl)
+			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
+			body            = `{"message":"` + strings.Repeat("a", int(todo.MaxBodySize)) + `"}`
+			req             = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			recorder        = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusRequestEntityTooLarge)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Result().StatusCode)
+	})
 	t.Run("it should return http.StatusServiceUnavailable because the request failed", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -68,18 +113,28 @@ func TestHandler_CreateTodo(t *testing.T) {
 		require.NoError(t, err)
 
 		gomock.InOrder(
-			mockTracer.EXPECT().Extract(opentracing.HTTPHeaders, gomock.Any()).Return(mockSpanContext, nil).Times(1),
-			mockTracer.EXPECT().StartSpan("receiver_todo", ext.RPCServerOption(mockSpanContext)).Return(mockSpan).Times(1),
 			mockSpan.EXPECT().Tracer().Times(1),
-			mockTodoClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("someErr")).Times(1),
-			mockSpan.EXPECT().Finish().Times(1),
+			mockTodoClient.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				Return(nil, status.Error(codes.Unavailable, "someErr")).
+				Times(1),
+			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusServiceUnavailable)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
 		)
 
-		handler.CreateTodo(recorder, req)
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
 
-		assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
+		require.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
+		assert.Equal(t, sharedhttp.ProblemContentType, recorder.Result().Header.Get("Content-Type"))
+
+		var problem sharedhttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+		assert.Equal(t, "UNAVAILABLE", problem.Code)
+		assert.Equal(t, "someErr", problem.Message)
 	})
-	t.Run("it should return http.StatusOK because the request was successful", func(t *testing.T) {
+	t.Run("it should return http.StatusBadRequest because the grpc server rejected the request", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -100,15 +155,437 @@ func TestHandler_CreateTodo(t *testing.T) {
 		require.NoError(t, err)
 
 		gomock.InOrder(
-			mockTracer.EXPECT().Extract(opentracing.HTTPHeaders, gomock.Any()).Return(mockSpanContext, nil).Times(1),
-			mockTracer.EXPECT().StartSpan("receiver_todo", ext.RPCServerOption(mockSpanContext)).Return(mockSpan).Times(1),
 			mockSpan.EXPECT().Tracer().Times(1),
-			mockTodoClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&todov1.CreateResponse{}, nil).Times(1),
-			mockSpan.EXPECT().Finish().Times(1),
+			mockTodoClient.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				Return(nil, status.Error(codes.InvalidArgument, "message must be not empty")).
+				Times(1),
+			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+
+		var problem sharedhttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "INVALID_ARGUMENT", problem.Code)
+		assert.Equal(t, "message must be not empty", problem.Message)
+	})
+	t.Run("it should return http.StatusOK because the request was successful", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			mockSpan       = opentracingmock.NewMockSpan(ctrl)
+			req            = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"message" : "hey there", "title": "someTitle", "priority": "medium", "tags": ["home"]}`),
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockTodoClient.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, req *todov1.CreateRequest, _ ...grpc.CallOption) (*todov1.CreateResponse, error) {
+					assert.Equal(t, "hey there", req.Message)
+					assert.Equal(t, "someTitle", req.Title)
+					assert.Equal(t, todov1.Priority_PRIORITY_MEDIUM, req.Priority)
+					assert.Equal(t, []string{"home"}, req.Tags)
+					return &todov1.CreateResponse{Id: "someID", OperationId: "someOperationID"}, nil
+				}).
+				Times(1),
+		)
+
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var created todo.Created
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&created))
+		assert.Equal(t, "someID", created.ID)
+		assert.Equal(t, "hey there", created.Message)
+		assert.Equal(t, "someOperationID", created.OperationID)
+		assert.Equal(t, todo.PriorityMedium, created.Priority)
+	})
+	t.Run("it should forward the idempotency key as grpc metadata", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			mockSpan       = opentracingmock.NewMockSpan(ctrl)
+			req            = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"message" : "hey there"}`),
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		req.Header.Set(idempotency.HeaderName, "someKey")
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockTodoClient.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ *todov1.CreateRequest, _ ...grpc.CallOption) (*todov1.CreateResponse, error) {
+					md, ok := metadata.FromOutgoingContext(ctx)
+					require.True(t, ok)
+					assert.Equal(t, []string{"someKey"}, md.Get(idempotency.MetadataKey))
+					return &todov1.CreateResponse{Id: "someID"}, nil
+				}).
+				Times(1),
 		)
 
-		handler.CreateTodo(recorder, req)
+		handler.CreateTodo(recorder, withSpan(req, mockSpan))
 
 		assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
 	})
 }
+
+func TestHandler_BatchCreateTodos(t *testing.T) {
+	t.Run("it should return http.StatusBadRequest because the batch is empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient  = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer      = tracingmock.NewMockTracer(ctrl)
+			mockSpan        = opentracingmock.NewMockSpan(ctrl)
+			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
+			req             = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"todos": []}`))
+			recorder        = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.BatchCreateTodos(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+	t.Run("it should return the outcome of each todo of the batch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			mockSpan       = opentracingmock.NewMockSpan(ctrl)
+			req            = httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(`{"todos": [{"message": "hello", "priority": "high"}, {"message": ""}]}`),
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		req.Header.Set(idempotency.HeaderName, "someKey")
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().SetTag("batch.size", 2).Times(1),
+			mockTodoClient.EXPECT().
+				BatchCreate(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, req *todov1.BatchCreateRequest, _ ...grpc.CallOption) (*todov1.BatchCreateResponse, error) {
+					md, ok := metadata.FromOutgoingContext(ctx)
+					require.True(t, ok)
+					assert.Equal(t, []string{"someKey"}, md.Get(idempotency.MetadataKey))
+
+					require.Len(t, req.Requests, 2)
+					assert.Equal(t, "hello", req.Requests[0].Message)
+					assert.Equal(t, todov1.Priority_PRIORITY_HIGH, req.Requests[0].Priority)
+
+					return &todov1.BatchCreateResponse{
+						Results: []*todov1.BatchCreateResult{
+							{Id: "someID", OperationId: "someOperationID"},
+							{Error: &todov1.BatchCreateError{
+								Code:    int32(codes.InvalidArgument),
+								Message: "invalid message",
+								Violations: []*todov1.FieldViolation{
+									{Field: "message", Description: "must be at least 1 characters long"},
+								},
+							}},
+						},
+					}, nil
+				}).
+				Times(1),
+		)
+
+		handler.BatchCreateTodos(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var result todo.BatchResult
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		assert.Equal(t, todo.BatchResult{
+			Results: []todo.BatchItemResult{
+				{ID: "someID", OperationID: "someOperationID"},
+				{Error: &todo.BatchItemError{
+					Status:  http.StatusBadRequest,
+					Code:    "INVALID_ARGUMENT",
+					Message: "invalid message",
+					Violations: []validation.FieldViolation{
+						{Field: "message", Description: "must be at least 1 characters long"},
+					},
+				}},
+			},
+		}, result)
+	})
+}
+
+func TestHandler_ListTodos(t *testing.T) {
+	t.Run("it should return http.StatusBadRequest because the query parameters are malformed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient  = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer      = tracingmock.NewMockTracer(ctrl)
+			mockSpan        = opentracingmock.NewMockSpan(ctrl)
+			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
+			req             = httptest.NewRequest(http.MethodGet, "/receiver/todos?created_after=yesterday", nil)
+			recorder        = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.ListTodos(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+	t.Run("it should return http.StatusBadRequest because the page token is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient  = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer      = tracingmock.NewMockTracer(ctrl)
+			mockSpan        = opentracingmock.NewMockSpan(ctrl)
+			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
+			req             = httptest.NewRequest(http.MethodGet, "/receiver/todos?page_token=nope", nil)
+			recorder        = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockTodoClient.EXPECT().
+				ListTodos(gomock.Any(), &todov1.ListTodosRequest{PageToken: "nope"}).
+				Return(nil, status.Error(codes.InvalidArgument, "invalid page token")).
+				Times(1),
+			mockSpan.EXPECT().Context().Return(mockSpanContext).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusBadRequest)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.ListTodos(recorder, withSpan(req, mockSpan))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+	t.Run("it should return a page of todos", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			mockSpan       = opentracingmock.NewMockSpan(ctrl)
+			createdAt      = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			req            = httptest.NewRequest(
+				http.MethodGet,
+				"/receiver/todos?page_size=1&created_after=2021-01-01T00:00:00Z&message_contains=hey",
+				nil,
+			)
+			recorder = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockTodoClient.EXPECT().
+				ListTodos(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, req *todov1.ListTodosRequest, _ ...grpc.CallOption) (*todov1.ListTodosResponse, error) {
+					assert.Equal(t, int32(1), req.PageSize)
+					assert.Equal(t, "hey", req.MessageContains)
+					assert.True(t, createdAt.Equal(req.CreatedAfter.AsTime()))
+					assert.Nil(t, req.CreatedBefore)
+					return &todov1.ListTodosResponse{
+						Todos: []*todov1.Todo{
+							{
+								Id:        "1",
+								Message:   "hey there",
+								CreatedAt: timestamppb.New(createdAt),
+								UpdatedAt: timestamppb.New(createdAt),
+							},
+						},
+						NextPageToken: "someToken",
+					}, nil
+				}).
+				Times(1),
+		)
+
+		handler.ListTodos(recorder, withSpan(req, mockSpan))
+
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var page todo.Page
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&page))
+		require.Len(t, page.Todos, 1)
+		assert.Equal(t, "1", page.Todos[0].ID)
+		assert.Equal(t, "hey there", page.Todos[0].Message)
+		assert.True(t, createdAt.Equal(page.Todos[0].CreatedAt))
+		assert.Equal(t, "someToken", page.NextPageToken)
+	})
+}
+
+func TestHandler_GetOperation(t *testing.T) {
+	t.Run("it should return http.StatusNotFound because the operation does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			mockSpan       = opentracingmock.NewMockSpan(ctrl)
+			req            = httptest.NewRequest(http.MethodGet, "/receiver/operations/someID", nil)
+			recorder       = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockTodoClient.EXPECT().
+				GetOperation(gomock.Any(), &todov1.GetOperationRequest{Id: "someID"}).
+				Return(nil, status.Error(codes.NotFound, "operation not found")).
+				Times(1),
+			mockSpan.EXPECT().Context().Return(nil).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusNotFound)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.GetOperation(recorder, mux.SetURLVars(withSpan(req, mockSpan), map[string]string{"id": "someID"}))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+	})
+	t.Run("it should return http.StatusInternalServerError because the response carries no operation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			mockSpan       = opentracingmock.NewMockSpan(ctrl)
+			req            = httptest.NewRequest(http.MethodGet, "/receiver/operations/someID", nil)
+			recorder       = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockTodoClient.EXPECT().
+				GetOperation(gomock.Any(), &todov1.GetOperationRequest{Id: "someID"}).
+				Return(&todov1.GetOperationResponse{}, nil).
+				Times(1),
+			mockSpan.EXPECT().Context().Return(nil).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.HTTPStatusCode), uint16(http.StatusInternalServerError)).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+		)
+
+		handler.GetOperation(recorder, mux.SetURLVars(withSpan(req, mockSpan), map[string]string{"id": "someID"}))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Result().StatusCode)
+	})
+	t.Run("it should return the operation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockTodoClient = todoclientmock.NewMockTodoServiceClient(ctrl)
+			mockTracer     = tracingmock.NewMockTracer(ctrl)
+			mockSpan       = opentracingmock.NewMockSpan(ctrl)
+			req            = httptest.NewRequest(http.MethodGet, "/receiver/operations/someID", nil)
+			recorder       = httptest.NewRecorder()
+		)
+
+		handler, err := transporthttp.NewHandler(mockTodoClient, mockTracer)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockTodoClient.EXPECT().
+				GetOperation(gomock.Any(), &todov1.GetOperationRequest{Id: "someID"}).
+				Return(&todov1.GetOperationResponse{
+					Operation: &todov1.Operation{
+						Id:     "someID",
+						TodoId: "someTodoID",
+						Status: todov1.OperationStatus_OPERATION_STATUS_SUCCEEDED,
+					},
+				}, nil).
+				Times(1),
+		)
+
+		handler.GetOperation(recorder, mux.SetURLVars(withSpan(req, mockSpan), map[string]string{"id": "someID"}))
+
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var op operation.Operation
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&op))
+		assert.Equal(t, "someID", op.ID)
+		assert.Equal(t, "someTodoID", op.TodoID)
+		assert.Equal(t, operation.StatusSucceeded, op.Status)
+	})
+}
+
+// withSpan returns a copy of r carrying span, as the tracing middleware does.
+func withSpan(r *http.Request, span opentracing.Span) *http.Request {
+	return r.WithContext(opentracing.ContextWithSpan(r.Context(), span))
+}
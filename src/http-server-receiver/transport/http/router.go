@@ -8,13 +8,13 @@ import (
 	"github.com/opentracing/opentracing-go"
 
 	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	transporthttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
 )
 
 // Handler wraps a mux router.
 type Handler struct {
 	todoSvcClient todov1.TodoServiceClient
 	router        *mux.Router
-	tracer        opentracing.Tracer
 }
 
 // InvalidHandlerParameterError is used when an invalid parameter is passed to NewHandler.
@@ -28,6 +28,7 @@ func (i InvalidHandlerParameterError) Error() string {
 }
 
 // NewHandler returns a new http handler.
+// Requests are traced by transporthttp.NewTracingMiddleware, handlers get their span from the request context.
 func NewHandler(todoSvcClient todov1.TodoServiceClient, tracer opentracing.Tracer) (Handler, error) {
 	handler := Handler{}
 
@@ -38,11 +39,19 @@ func NewHandler(todoSvcClient todov1.TodoServiceClient, tracer opentracing.Trace
 		return handler, InvalidHandlerParameterError{parameter: "tracer", reason: "cannot be nil"}
 	}
 
+	tracingMiddleware, err := transporthttp.NewTracingMiddleware(tracer)
+	if err != nil {
+		return handler, err
+	}
+
 	handler.todoSvcClient = todoSvcClient
-	handler.tracer = tracer
 	handler.router = mux.NewRouter()
+	handler.router.Use(tracingMiddleware)
 
 	handler.Router().HandleFunc("/receiver/todo", handler.CreateTodo).Methods(http.MethodPost)
+	handler.Router().HandleFunc("/receiver/todos", handler.ListTodos).Methods(http.MethodGet)
+	handler.Router().HandleFunc("/receiver/todos:batch", handler.BatchCreateTodos).Methods(http.MethodPost)
+	handler.Router().HandleFunc("/receiver/operations/{id}", handler.GetOperation).Methods(http.MethodGet)
 
 	return handler, nil
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/Shopify/sarama"
 	"golang.org/x/sync/errgroup"
 
+	offsetrepository "github.com/andream16/go-opentracing-example/src/kafka-consumer/offset/repository"
+	operationrepository "github.com/andream16/go-opentracing-example/src/kafka-consumer/operation/repository"
 	"github.com/andream16/go-opentracing-example/src/kafka-consumer/todo/repository"
 	transportkafka "github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
 	"github.com/andream16/go-opentracing-example/src/shared/database/postgres/pgxwrapper"
@@ -26,19 +28,17 @@ func main() {
 	)
 
 	var (
-		kafkaTodoTopic     string
-		kafkaBrokerAddress string
-		databaseDSN        string
-		jaegerAgentHost    string
-		jaegerAgentPort    string
+		kafkaTodoTopic           string
+		kafkaTodoDeadLetterTopic string
+		kafkaBrokerAddress       string
+		databaseDSN              string
 	)
 
 	for k, v := range map[string]*string{
-		"KAFKA_TODO_TOPIC":     &kafkaTodoTopic,
-		"KAFKA_BROKER_ADDRESS": &kafkaBrokerAddress,
-		"DATABASE_DSN":         &databaseDSN,
-		"JAEGER_AGENT_HOST":    &jaegerAgentHost,
-		"JAEGER_AGENT_PORT":    &jaegerAgentPort,
+		"KAFKA_TODO_TOPIC":             &kafkaTodoTopic,
+		"KAFKA_TODO_DEAD_LETTER_TOPIC": &kafkaTodoDeadLetterTopic,
+		"KAFKA_BROKER_ADDRESS":         &kafkaBrokerAddress,
+		"DATABASE_DSN":                 &databaseDSN,
 	} {
 		var ok bool
 		*v, ok = os.LookupEnv(k)
@@ -50,13 +50,25 @@ func main() {
 	var ctx, cancel = context.WithCancel(context.Background())
 	defer cancel()
 
-	tracer, err := tracing.NewJaegerTracer(serviceName, jaegerAgentHost, jaegerAgentPort)
+	samplerCfg, err := tracing.SamplerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("could not read sampler configuration: %v", err)
+	}
+
+	tracer, err := tracing.New(ctx, tracing.Config{
+		Backend:         os.Getenv("TRACING_BACKEND"),
+		ServiceName:     serviceName,
+		JaegerAgentHost: os.Getenv("JAEGER_AGENT_HOST"),
+		JaegerAgentPort: os.Getenv("JAEGER_AGENT_PORT"),
+		Sampler:         samplerCfg,
+		Propagation:     tracing.ParsePropagation(os.Getenv("TRACING_PROPAGATION")),
+	})
 	if err != nil {
 		log.Fatalf("could not create new tracer: %v", err)
 	}
 	defer tracer.Close()
 
-	executor, err := pgxwrapper.New(ctx, databaseDSN, 10*time.Second, tracer)
+	executor, err := pgxwrapper.New(ctx, databaseDSN, 10*time.Second, tracer, os.Getenv("DATABASE_TRACE_ARGS") == "true")
 	if err != nil {
 		log.Fatalf("could not initialise a new executor: %v", err)
 	}
@@ -82,6 +94,84 @@ func main() {
 		"DROP TABLE todos;",
 	)
 
+	m.AppendMigration(
+		"add_todo_timestamps",
+		`ALTER TABLE todos
+			ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();`,
+		"ALTER TABLE todos DROP COLUMN created_at, DROP COLUMN updated_at;",
+	)
+
+	m.AppendMigration(
+		"create_todo_created_at_index",
+		"CREATE INDEX todos_created_at_idx ON todos (created_at);",
+		"DROP INDEX todos_created_at_idx;",
+	)
+
+	// Todos created before ids were introduced keep their row id as uid.
+	m.AppendMigration(
+		"add_todo_uid",
+		`ALTER TABLE todos ADD COLUMN uid TEXT;
+		UPDATE todos SET uid = id::text;
+		ALTER TABLE todos ALTER COLUMN uid SET NOT NULL, ADD CONSTRAINT todos_uid_key UNIQUE (uid);`,
+		"ALTER TABLE todos DROP COLUMN uid;",
+	)
+
+	m.AppendMigration(
+		"add_todo_idempotency_key",
+		`ALTER TABLE todos
+			ADD COLUMN idempotency_key TEXT,
+			ADD CONSTRAINT todos_idempotency_key_key UNIQUE (idempotency_key);`,
+		"ALTER TABLE todos DROP COLUMN idempotency_key;",
+	)
+
+	// The operations are created by the grpc-server, which migrates them. Moved migrations are kept as no-ops, so that
+	// the versions of the following ones don't change.
+	m.AppendMigration("create_operations_table", "SELECT 1;", "SELECT 1;")
+
+	m.AppendMigration(
+		"add_todo_details",
+		`ALTER TABLE todos
+			ADD COLUMN title TEXT NOT NULL DEFAULT '',
+			ADD COLUMN description TEXT NOT NULL DEFAULT '',
+			ADD COLUMN due_at TIMESTAMPTZ,
+			ADD COLUMN priority TEXT NOT NULL DEFAULT '',
+			ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+			ADD COLUMN completed BOOLEAN NOT NULL DEFAULT false;`,
+		`ALTER TABLE todos
+			DROP COLUMN title,
+			DROP COLUMN description,
+			DROP COLUMN due_at,
+			DROP COLUMN priority,
+			DROP COLUMN tags,
+			DROP COLUMN completed;`,
+	)
+
+	// Moved to the grpc-server, which owns the outbox.
+	m.AppendMigration("create_outbox_table", "SELECT 1;", "SELECT 1;")
+
+	// In exactly-once mode, the offsets are stored along with the todos created by the same batch.
+	m.AppendMigration(
+		"create_consumer_offsets_table",
+		`CREATE TABLE consumer_offsets (
+			group_id TEXT NOT NULL,
+			topic TEXT NOT NULL,
+			partition INT NOT NULL,
+			next_offset BIGINT NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (group_id, topic, partition)
+		);`,
+		"DROP TABLE consumer_offsets;",
+	)
+
+	m.AppendMigration(
+		"add_todo_owner",
+		`ALTER TABLE todos
+			ADD COLUMN tenant_id TEXT,
+			ADD COLUMN user_id TEXT;`,
+		"ALTER TABLE todos DROP COLUMN tenant_id, DROP COLUMN user_id;",
+	)
+
 	if err := m.Migrate(ctx); err != nil {
 		log.Fatalf("could not run migration: %v", err)
 	}
@@ -91,7 +181,32 @@ func main() {
 		log.Fatalf("could not initialise a new repository: %v", err)
 	}
 
+	recorder, err := operationrepository.New(executor)
+	if err != nil {
+		log.Fatalf("could not initialise a new operation recorder: %v", err)
+	}
+
+	commitPolicy, err := transportkafka.CommitPolicyFromEnv(kafkaGroupName)
+	if err != nil {
+		log.Fatalf("could not read commit policy: %v", err)
+	}
+
+	offsets, err := offsetrepository.New(executor, executor)
+	if err != nil {
+		log.Fatalf("could not initialise a new offset store: %v", err)
+	}
+
 	kafkaCfg := sarama.NewConfig()
+	commitPolicy.Configure(kafkaCfg)
+
+	// Retried and dead-lettered records are sent synchronously, so that they're marked only once they're safe.
+	kafkaCfg.Producer.RequiredAcks = sarama.WaitForAll
+	kafkaCfg.Producer.Retry.Max = 10
+	kafkaCfg.Producer.Return.Successes = true
+
+	if commitPolicy.Mode == transportkafka.CommitModeExactlyOnce {
+		kafka.EnableIdempotence(kafkaCfg)
+	}
 
 	kafkaClient, err := kafka.NewClient([]string{kafkaBrokerAddress}, kafkaCfg, 10*time.Second)
 	if err != nil {
@@ -103,18 +218,49 @@ func main() {
 		log.Fatalf("could not create new kafka consumer group: %v", err)
 	}
 
-	consumer, err := transportkafka.NewConsumer(repo, tracer)
+	kafkaProducer, err := kafka.NewSyncProducer(kafkaClient)
+	if err != nil {
+		log.Fatalf("could not create new kafka producer: %v", err)
+	}
+
+	deadLetterer, err := transportkafka.NewDeadLetterPublisher(kafkaTodoDeadLetterTopic, kafkaProducer, tracer)
+	if err != nil {
+		log.Fatalf("could not create new dead-letter publisher: %v", err)
+	}
+
+	// Transient failures are retried after 5s and 1m by default, then dead-lettered.
+	retryDelays, ok := os.LookupEnv("KAFKA_TODO_RETRY_DELAYS")
+	if !ok {
+		retryDelays = "5s,1m"
+	}
+
+	retryTiers, err := transportkafka.ParseRetryTiers(kafkaTodoTopic, retryDelays)
+	if err != nil {
+		log.Fatalf("could not read retry tiers: %v", err)
+	}
+
+	retrier, err := transportkafka.NewRetryPublisher(retryTiers, kafkaProducer, tracer)
+	if err != nil {
+		log.Fatalf("could not create new retry publisher: %v", err)
+	}
+
+	consumer, err := transportkafka.NewConsumer(repo, recorder, retrier, deadLetterer, tracer, commitPolicy, offsets)
 	if err != nil {
 		log.Fatalf("could not create new kafka consumer: %v", err)
 	}
 
+	topics := []string{kafkaTodoTopic}
+	for _, tier := range retryTiers {
+		topics = append(topics, tier.Topic)
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
 		for {
 			if err := kafkaConsumerGroup.Consume(
 				ctx,
-				[]string{kafkaTodoTopic},
+				topics,
 				consumer,
 			); err != nil {
 				cancel()
@@ -0,0 +1,48 @@
+package offset
+
+import (
+	"context"
+	"fmt"
+)
+
+// Position is the offset a consumer group consumes a partition from next.
+type Position struct {
+	Group     string
+	Topic     string
+	Partition int32
+	// Offset is the offset of the next record to consume, i.e. the offset of the last processed record plus one.
+	Offset int64
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying p, for the repositories to store it along with what they write.
+func NewContext(ctx context.Context, p Position) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the position carried by ctx, if any.
+func FromContext(ctx context.Context) (Position, bool) {
+	p, ok := ctx.Value(contextKey{}).(Position)
+	return p, ok
+}
+
+// UpsertCTE returns a common table expression named stored_offset storing a position, whose group, topic,
+// partition and offset are the query arguments from $n on, as returned by Args.
+// A position never moves an offset backwards, so that storing an older one again is a no-op.
+func UpsertCTE(n int) string {
+	return fmt.Sprintf(
+		`stored_offset AS (
+			INSERT INTO consumer_offsets(group_id, topic, partition, next_offset)
+			VALUES($%d::text, $%d::text, $%d::int, $%d::bigint)
+			ON CONFLICT (group_id, topic, partition) DO UPDATE
+			SET next_offset = GREATEST(consumer_offsets.next_offset, EXCLUDED.next_offset), updated_at = now()
+		)`,
+		n, n+1, n+2, n+3,
+	)
+}
+
+// Args returns the query arguments of the position for UpsertCTE.
+func (p Position) Args() []interface{} {
+	return []interface{}{p.Group, p.Topic, p.Partition, p.Offset}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/offset"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+)
+
+// Store describes the offset store interface.
+type Store interface {
+	// Offsets returns the offset to consume next of each partition of topic stored for group.
+	// Partitions without a stored offset are left out.
+	Offsets(ctx context.Context, group, topic string) (map[int32]int64, error)
+	// Save stores a position, unless a later one is stored already.
+	Save(ctx context.Context, p offset.Position) error
+}
+
+// OffsetStore stores consumer offsets in postgres, next to the todos.
+type OffsetStore struct {
+	executor postgres.Executor
+	querier  postgres.Querier
+}
+
+// New returns a new OffsetStore.
+func New(executor postgres.Executor, querier postgres.Querier) (OffsetStore, error) {
+	switch {
+	case executor == nil:
+		return OffsetStore{}, errors.New("executor cannot be nil")
+	case querier == nil:
+		return OffsetStore{}, errors.New("querier cannot be nil")
+	}
+	return OffsetStore{
+		executor: executor,
+		querier:  querier,
+	}, nil
+}
+
+// Offsets selects the offsets of the partitions of topic from the consumer_offsets table.
+func (s OffsetStore) Offsets(ctx context.Context, group, topic string) (map[int32]int64, error) {
+	const getConsumerOffsetsQueryName = "get_consumer_offsets"
+
+	rows, err := s.querier.Query(
+		ctx,
+		getConsumerOffsetsQueryName,
+		`SELECT partition, next_offset FROM consumer_offsets WHERE group_id = $1::text AND topic = $2::text`,
+		group,
+		topic,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not select consumer offsets: %w", err)
+	}
+	defer rows.Close()
+
+	offsets := make(map[int32]int64)
+	for rows.Next() {
+		var (
+			partition int32
+			next      int64
+		)
+		if err := rows.Scan(&partition, &next); err != nil {
+			return nil, fmt.Errorf("could not scan consumer offset: %w", err)
+		}
+		offsets[partition] = next
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not select consumer offsets: %w", err)
+	}
+
+	return offsets, nil
+}
+
+// Save upserts a position in the consumer_offsets table.
+func (s OffsetStore) Save(ctx context.Context, p offset.Position) error {
+	const saveConsumerOffsetQueryName = "save_consumer_offset"
+
+	if err := s.executor.Exec(
+		ctx,
+		saveConsumerOffsetQueryName,
+		`WITH `+offset.UpsertCTE(1)+` SELECT 1`,
+		p.Args()...,
+	); err != nil {
+		return fmt.Errorf("could not save consumer offset: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/offset"
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/offset/repository"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("it should return an error because the executor is not valid", func(t *testing.T) {
+		store, err := repository.New(nil, executormock.NewMockQuerier(ctrl))
+		require.Error(t, err)
+		assert.Empty(t, store)
+	})
+	t.Run("it should return an error because the querier is not valid", func(t *testing.T) {
+		store, err := repository.New(executormock.NewMockExecutor(ctrl), nil)
+		require.Error(t, err)
+		assert.Empty(t, store)
+	})
+	t.Run("it should return a new store", func(t *testing.T) {
+		store, err := repository.New(executormock.NewMockExecutor(ctrl), executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+		assert.NotEmpty(t, store)
+	})
+}
+
+func TestOffsetStore_Offsets(t *testing.T) {
+	t.Run("it should return the stored offsets by partition", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx         = context.Background()
+			mockQuerier = executormock.NewMockQuerier(ctrl)
+			mockRows    = executormock.NewMockRows(ctrl)
+		)
+
+		store, err := repository.New(executormock.NewMockExecutor(ctrl), mockQuerier)
+		require.NoError(t, err)
+
+		scan := func(partition int32, next int64) func(dest ...interface{}) error {
+			return func(dest ...interface{}) error {
+				*dest[0].(*int32) = partition
+				*dest[1].(*int64) = next
+				return nil
+			}
+		}
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().Query(ctx, "get_consumer_offsets", gomock.Any(), "someGroup", "someTopic").Return(mockRows, nil).Times(1),
+			mockRows.EXPECT().Next().Return(true).Times(1),
+			mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(scan(0, 42)).Times(1),
+			mockRows.EXPECT().Next().Return(true).Times(1),
+			mockRows.EXPECT().Scan(gomock.Any()).DoAndReturn(scan(2, 7)).Times(1),
+			mockRows.EXPECT().Next().Return(false).Times(1),
+			mockRows.EXPECT().Err().Return(nil).Times(1),
+			mockRows.EXPECT().Close().Times(1),
+		)
+
+		offsets, err := store.Offsets(ctx, "someGroup", "someTopic")
+		require.NoError(t, err)
+		assert.Equal(t, map[int32]int64{0: 42, 2: 7}, offsets)
+	})
+	t.Run("it should return an error because the offsets could not be selected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockQuerier := executormock.NewMockQuerier(ctrl)
+
+		store, err := repository.New(executormock.NewMockExecutor(ctrl), mockQuerier)
+		require.NoError(t, err)
+
+		mockQuerier.EXPECT().Query(gomock.Any(), "get_consumer_offsets", gomock.Any(), "someGroup", "someTopic").Return(nil, errors.New("someErr")).Times(1)
+
+		_, err = store.Offsets(context.Background(), "someGroup", "someTopic")
+		assert.Error(t, err)
+	})
+}
+
+func TestOffsetStore_Save(t *testing.T) {
+	t.Run("it should upsert the position", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			mockExecutor = executormock.NewMockExecutor(ctrl)
+		)
+
+		store, err := repository.New(mockExecutor, executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+
+		mockExecutor.EXPECT().
+			Exec(ctx, "save_consumer_offset", gomock.Any(), "someGroup", "someTopic", int32(1), int64(42)).
+			Return(nil).
+			Times(1)
+
+		require.NoError(t, store.Save(ctx, offset.Position{
+			Group:     "someGroup",
+			Topic:     "someTopic",
+			Partition: 1,
+			Offset:    42,
+		}))
+	})
+	t.Run("it should return an error because the position could not be upserted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockExecutor := executormock.NewMockExecutor(ctrl)
+
+		store, err := repository.New(mockExecutor, executormock.NewMockQuerier(ctrl))
+		require.NoError(t, err)
+
+		mockExecutor.EXPECT().
+			Exec(gomock.Any(), "save_consumer_offset", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(errors.New("someErr")).
+			Times(1)
+
+		assert.Error(t, store.Save(context.Background(), offset.Position{Group: "someGroup", Topic: "someTopic"}))
+	})
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+)
+
+// Recorder describes the recorder interface.
+type Recorder interface {
+	// Succeed records that the operation succeeded.
+	Succeed(ctx context.Context, id string) error
+	// SucceedAll records that the operations succeeded in a single round trip.
+	SucceedAll(ctx context.Context, ids []string) error
+	// Fail records that the operation failed because of reason.
+	Fail(ctx context.Context, id, reason string) error
+}
+
+// OperationRecorder records the outcome of operations.
+type OperationRecorder struct {
+	executor postgres.Executor
+}
+
+// New returns a new OperationRecorder.
+func New(executor postgres.Executor) (OperationRecorder, error) {
+	if executor == nil {
+		return OperationRecorder{}, errors.New("executor cannot be nil")
+	}
+	return OperationRecorder{
+		executor: executor,
+	}, nil
+}
+
+// Succeed marks an operation as succeeded in the operations table.
+func (or OperationRecorder) Succeed(ctx context.Context, id string) error {
+	const succeedOperationQueryName = "succeed_operation"
+
+	if err := or.executor.Exec(
+		ctx,
+		succeedOperationQueryName,
+		`UPDATE operations SET status = $2::text, error = NULL, updated_at = now() WHERE id = $1::text`,
+		id,
+		string(operation.StatusSucceeded),
+	); err != nil {
+		return fmt.Errorf("could not succeed operation: %w", err)
+	}
+
+	return nil
+}
+
+// SucceedAll marks operations as succeeded in the operations table.
+func (or OperationRecorder) SucceedAll(ctx context.Context, ids []string) error {
+	const succeedOperationsQueryName = "succeed_operations"
+
+	if err := or.executor.Exec(
+		ctx,
+		succeedOperationsQueryName,
+		`UPDATE operations SET status = $2::text, error = NULL, updated_at = now() WHERE id = ANY($1::text[])`,
+		ids,
+		string(operation.StatusSucceeded),
+	); err != nil {
+		return fmt.Errorf("could not succeed operations: %w", err)
+	}
+
+	return nil
+}
+
+// Fail marks an operation as failed in the operations table.
+// Operations that already succeeded are left untouched, as a redelivered record must not undo a persisted todo.
+func (or OperationRecorder) Fail(ctx context.Context, id, reason string) error {
+	const failOperationQueryName = "fail_operation"
+
+	if err := or.executor.Exec(
+		ctx,
+		failOperationQueryName,
+		`UPDATE operations SET status = $2::text, error = $3::text, updated_at = now() WHERE id = $1::text AND status <> $4::text`,
+		id,
+		string(operation.StatusFailed),
+		reason,
+		string(operation.StatusSucceeded),
+	); err != nil {
+		return fmt.Errorf("could not fail operation: %w", err)
+	}
+
+	return nil
+}
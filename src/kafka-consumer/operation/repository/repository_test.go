@@ -0,0 +1,143 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/operation/repository"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("it should return an error because the executor is not valid", func(t *testing.T) {
+		recorder, err := repository.New(nil)
+		require.Error(t, err)
+		assert.Empty(t, recorder)
+	})
+	t.Run("it should return a new recorder", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		recorder, err := repository.New(executormock.NewMockExecutor(ctrl))
+		require.NoError(t, err)
+		assert.NotEmpty(t, recorder)
+	})
+}
+
+func TestOperationRecorder_Succeed(t *testing.T) {
+	t.Run("it should return an error because the execution of the query failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			executorMock = executormock.NewMockExecutor(ctrl)
+		)
+
+		recorder, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(ctx, "succeed_operation", gomock.Any(), "someID", string(operation.StatusSucceeded)).
+			Return(errors.New("someErr")).
+			Times(1)
+
+		require.Error(t, recorder.Succeed(ctx, "someID"))
+	})
+	t.Run("it should mark the operation as succeeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			executorMock = executormock.NewMockExecutor(ctrl)
+		)
+
+		recorder, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(ctx, "succeed_operation", gomock.Any(), "someID", string(operation.StatusSucceeded)).
+			Return(nil).
+			Times(1)
+
+		require.NoError(t, recorder.Succeed(ctx, "someID"))
+	})
+}
+
+func TestOperationRecorder_SucceedAll(t *testing.T) {
+	t.Run("it should return an error because the execution of the query failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			executorMock = executormock.NewMockExecutor(ctrl)
+		)
+
+		recorder, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(ctx, "succeed_operations", gomock.Any(), []string{"someID", "otherID"}, string(operation.StatusSucceeded)).
+			Return(errors.New("someErr")).
+			Times(1)
+
+		require.Error(t, recorder.SucceedAll(ctx, []string{"someID", "otherID"}))
+	})
+	t.Run("it should mark the operations as succeeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			executorMock = executormock.NewMockExecutor(ctrl)
+		)
+
+		recorder, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(ctx, "succeed_operations", gomock.Any(), []string{"someID", "otherID"}, string(operation.StatusSucceeded)).
+			Return(nil).
+			Times(1)
+
+		require.NoError(t, recorder.SucceedAll(ctx, []string{"someID", "otherID"}))
+	})
+}
+
+func TestOperationRecorder_Fail(t *testing.T) {
+	t.Run("it should mark the operation as failed unless it already succeeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			executorMock = executormock.NewMockExecutor(ctrl)
+		)
+
+		recorder, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(
+				ctx,
+				"fail_operation",
+				gomock.Any(),
+				"someID",
+				string(operation.StatusFailed),
+				"someReason",
+				string(operation.StatusSucceeded),
+			).
+			Return(nil).
+			Times(1)
+
+		require.NoError(t, recorder.Fail(ctx, "someID", "someReason"))
+	})
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/offset"
 	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
 	"github.com/andream16/go-opentracing-example/src/shared/todo"
 )
@@ -13,6 +16,8 @@ import (
 type Creator interface {
 	// Create creates a new todo.
 	Create(ctx context.Context, todo *todo.Todo) error
+	// CreateBatch creates new todos in a single round trip.
+	CreateBatch(ctx context.Context, todos []*todo.Todo) error
 }
 
 // TodoCreator is the todos repository.
@@ -30,18 +35,150 @@ func New(executor postgres.Executor) (TodoCreator, error) {
 	}, nil
 }
 
-// Create inserts a new todo in the todos table.
-func (tc TodoCreator) Create(ctx context.Context, todo *todo.Todo) error {
+// Create inserts a new todo in the todos table, stamped with its tenant and user when they are known.
+// Inserting a todo with an already stored id or idempotency key is a no-op, so that redelivered
+// records and retried requests collapse into a single todo.
+// A created change is notified on todo.ChangeChannel only when the todo is actually inserted.
+// The offset.Position carried by ctx, if any, is stored by the same statement.
+func (tc TodoCreator) Create(ctx context.Context, t *todo.Todo) error {
 	const createTodosQueryName = "create_todos"
 
-	if err := tc.executor.Exec(
+	var dueAt *time.Time
+	if !t.DueAt.IsZero() {
+		dueAt = &t.DueAt
+	}
+
+	trace, err := todo.ChangeTrace(ctx)
+	if err != nil {
+		return err
+	}
+
+	queryName, sql, args := withOffset(
 		ctx,
 		createTodosQueryName,
-		`INSERT INTO todos(message) VALUES($1::text)`,
-		todo.Message,
-	); err != nil {
+		`WITH inserted AS (
+			INSERT INTO todos(uid, message, idempotency_key, title, description, due_at, priority, tags, tenant_id, user_id)
+			VALUES(
+				$1::text, $2::text, NULLIF($3::text, ''), $4::text, $5::text, $6::timestamptz, $7::text,
+				COALESCE($8::text[], '{}'), NULLIF($9::text, ''), NULLIF($10::text, '')
+			)
+			ON CONFLICT DO NOTHING
+			RETURNING uid
+		)`,
+		`SELECT pg_notify($11::text, json_build_object('type', $12::text, 'id', uid, 'trace', $13::json)::text) FROM inserted`,
+		t.ID,
+		t.Message,
+		t.IdempotencyKey,
+		t.Title,
+		t.Description,
+		dueAt,
+		string(t.Priority),
+		t.Tags,
+		t.TenantID,
+		t.UserID,
+		todo.ChangeChannel,
+		string(todo.ChangeCreated),
+		trace,
+	)
+
+	if err := tc.executor.Exec(ctx, queryName, sql, args...); err != nil {
 		return fmt.Errorf("could not insert todo: %w", err)
 	}
 
 	return nil
 }
+
+// batchRow is the json representation of a todo inserted by CreateBatch.
+type batchRow struct {
+	UID            string     `json:"uid"`
+	Message        string     `json:"message"`
+	IdempotencyKey string     `json:"idempotency_key"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	DueAt          *time.Time `json:"due_at"`
+	Priority       string     `json:"priority"`
+	Tags           []string   `json:"tags"`
+	TenantID       string     `json:"tenant_id"`
+	UserID         string     `json:"user_id"`
+}
+
+// CreateBatch inserts new todos in the todos table with a single statement, passing them as a json array.
+// As in Create, todos with an already stored id or idempotency key are skipped and a created change is
+// notified for each inserted todo only. Changes carry the span in ctx, covering the whole batch.
+// The offset.Position carried by ctx, if any, is stored by the same statement.
+func (tc TodoCreator) CreateBatch(ctx context.Context, todos []*todo.Todo) error {
+	const createTodosBatchQueryName = "create_todos_batch"
+
+	rows := make([]batchRow, 0, len(todos))
+	for _, t := range todos {
+		row := batchRow{
+			UID:            t.ID,
+			Message:        t.Message,
+			IdempotencyKey: t.IdempotencyKey,
+			Title:          t.Title,
+			Description:    t.Description,
+			Priority:       string(t.Priority),
+			Tags:           t.Tags,
+			TenantID:       t.TenantID,
+			UserID:         t.UserID,
+		}
+		if !t.DueAt.IsZero() {
+			dueAt := t.DueAt
+			row.DueAt = &dueAt
+		}
+		rows = append(rows, row)
+	}
+
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("could not serialise todos: %w", err)
+	}
+
+	trace, err := todo.ChangeTrace(ctx)
+	if err != nil {
+		return err
+	}
+
+	queryName, sql, args := withOffset(
+		ctx,
+		createTodosBatchQueryName,
+		`WITH inserted AS (
+			INSERT INTO todos(uid, message, idempotency_key, title, description, due_at, priority, tags, tenant_id, user_id)
+			SELECT
+				uid, message, NULLIF(idempotency_key, ''), title, description, due_at, priority, COALESCE(tags, '{}'),
+				NULLIF(tenant_id, ''), NULLIF(user_id, '')
+			FROM json_to_recordset($1::json) AS t(
+				uid text, message text, idempotency_key text, title text, description text,
+				due_at timestamptz, priority text, tags text[], tenant_id text, user_id text
+			)
+			ON CONFLICT DO NOTHING
+			RETURNING uid
+		)`,
+		`SELECT pg_notify($2::text, json_build_object('type', $3::text, 'id', uid, 'trace', $4::json)::text) FROM inserted`,
+		string(b),
+		todo.ChangeChannel,
+		string(todo.ChangeCreated),
+		trace,
+	)
+
+	if err := tc.executor.Exec(ctx, queryName, sql, args...); err != nil {
+		return fmt.Errorf("could not insert todos: %w", err)
+	}
+
+	return nil
+}
+
+// withOffset returns the name, sql and arguments of the query made of with and sel, storing the offset.Position
+// carried by ctx in between when there's one. A single statement being atomic, the position is stored if and
+// only if the todos are, and redelivered records are skipped once the consumer seeks to it.
+// Queries storing a position are named after queryName with a _with_offset suffix.
+func withOffset(ctx context.Context, queryName, with, sel string, args ...interface{}) (string, string, []interface{}) {
+	p, ok := offset.FromContext(ctx)
+	if !ok {
+		return queryName, with + "\n\t\t" + sel, args
+	}
+
+	return queryName + "_with_offset",
+		with + ",\n\t\t" + offset.UpsertCTE(len(args)+1) + "\n\t\t" + sel,
+		append(args, p.Args()...)
+}
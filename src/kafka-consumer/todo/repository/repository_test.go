@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/offset"
 	"github.com/andream16/go-opentracing-example/src/kafka-consumer/todo/repository"
 	"github.com/andream16/go-opentracing-example/src/shared/todo"
 	executormock "github.com/andream16/go-opentracing-example/src/test/mock/database/postgres"
@@ -36,8 +38,10 @@ func TestTodoCreator_Create(t *testing.T) {
 		defer ctrl.Finish()
 
 		const (
-			queryName   = "create_todos"
-			todoMessage = "hello"
+			queryName      = "create_todos"
+			todoID         = "someID"
+			todoMessage    = "hello"
+			idempotencyKey = "someKey"
 		)
 
 		var (
@@ -52,24 +56,52 @@ func TestTodoCreator_Create(t *testing.T) {
 		executorMock.EXPECT().Exec(
 			ctx,
 			queryName,
-			`INSERT INTO todos(message) VALUES($1::text)`,
+			`WITH inserted AS (
+			INSERT INTO todos(uid, message, idempotency_key, title, description, due_at, priority, tags, tenant_id, user_id)
+			VALUES(
+				$1::text, $2::text, NULLIF($3::text, ''), $4::text, $5::text, $6::timestamptz, $7::text,
+				COALESCE($8::text[], '{}'), NULLIF($9::text, ''), NULLIF($10::text, '')
+			)
+			ON CONFLICT DO NOTHING
+			RETURNING uid
+		)
+		SELECT pg_notify($11::text, json_build_object('type', $12::text, 'id', uid, 'trace', $13::json)::text) FROM inserted`,
+			todoID,
 			todoMessage,
+			idempotencyKey,
+			"",
+			"",
+			(*time.Time)(nil),
+			"",
+			[]string(nil),
+			"",
+			"",
+			todo.ChangeChannel,
+			"created",
+			"{}",
 		).Return(errors.New("someErr")).Times(1)
 
-		require.Error(t, creator.Create(ctx, &todo.Todo{Message: todoMessage}))
+		require.Error(t, creator.Create(ctx, &todo.Todo{
+			ID:             todoID,
+			Message:        todoMessage,
+			IdempotencyKey: idempotencyKey,
+		}))
 	})
-	t.Run("it should create a todo", func(t *testing.T) {
+	t.Run("it should create a todo stamped with its tenant and user", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		const (
-			queryName   = "create_todos"
-			todoMessage = "hello"
+			queryName      = "create_todos"
+			todoID         = "someID"
+			todoMessage    = "hello"
+			idempotencyKey = "someKey"
 		)
 
 		var (
 			ctx          = context.Background()
 			executorMock = executormock.NewMockExecutor(ctrl)
+			dueAt        = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
 		)
 
 		creator, err := repository.New(executorMock)
@@ -79,10 +111,151 @@ func TestTodoCreator_Create(t *testing.T) {
 		executorMock.EXPECT().Exec(
 			ctx,
 			queryName,
-			`INSERT INTO todos(message) VALUES($1::text)`,
+			`WITH inserted AS (
+			INSERT INTO todos(uid, message, idempotency_key, title, description, due_at, priority, tags, tenant_id, user_id)
+			VALUES(
+				$1::text, $2::text, NULLIF($3::text, ''), $4::text, $5::text, $6::timestamptz, $7::text,
+				COALESCE($8::text[], '{}'), NULLIF($9::text, ''), NULLIF($10::text, '')
+			)
+			ON CONFLICT DO NOTHING
+			RETURNING uid
+		)
+		SELECT pg_notify($11::text, json_build_object('type', $12::text, 'id', uid, 'trace', $13::json)::text) FROM inserted`,
+			todoID,
 			todoMessage,
+			idempotencyKey,
+			"someTitle",
+			"someDescription",
+			&dueAt,
+			"low",
+			[]string{"home", "chores"},
+			"someTenantID",
+			"someUserID",
+			todo.ChangeChannel,
+			"created",
+			"{}",
 		).Return(nil).Times(1)
 
-		require.NoError(t, creator.Create(ctx, &todo.Todo{Message: todoMessage}))
+		require.NoError(t, creator.Create(ctx, &todo.Todo{
+			ID:             todoID,
+			Message:        todoMessage,
+			Title:          "someTitle",
+			Description:    "someDescription",
+			DueAt:          dueAt,
+			Priority:       todo.PriorityLow,
+			Tags:           []string{"home", "chores"},
+			IdempotencyKey: idempotencyKey,
+			TenantID:       "someTenantID",
+			UserID:         "someUserID",
+		}))
+	})
+}
+
+func TestTodoCreator_CreateBatch(t *testing.T) {
+	t.Run("it should return an error because the execution of the query failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			executorMock = executormock.NewMockExecutor(ctrl)
+		)
+
+		creator, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(ctx, "create_todos_batch", gomock.Any(), gomock.Any(), todo.ChangeChannel, "created", "{}").
+			Return(errors.New("someErr")).
+			Times(1)
+
+		require.Error(t, creator.CreateBatch(ctx, []*todo.Todo{{ID: "someID", Message: "hello"}}))
+	})
+	t.Run("it should create the todos in a single statement", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			ctx          = context.Background()
+			executorMock = executormock.NewMockExecutor(ctrl)
+			dueAt        = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		)
+
+		creator, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(
+				ctx,
+				"create_todos_batch",
+				gomock.Any(),
+				`[{"uid":"someID","message":"hello","idempotency_key":"someKey/0","title":"someTitle","description":"",`+
+					`"due_at":"2021-01-02T00:00:00Z","priority":"low","tags":["home"],"tenant_id":"someTenantID","user_id":"someUserID"},`+
+					`{"uid":"otherID","message":"world","idempotency_key":"","title":"","description":"",`+
+					`"due_at":null,"priority":"","tags":null,"tenant_id":"","user_id":""}]`,
+				todo.ChangeChannel,
+				"created",
+				"{}",
+			).
+			Return(nil).
+			Times(1)
+
+		require.NoError(t, creator.CreateBatch(ctx, []*todo.Todo{
+			{
+				ID:             "someID",
+				Message:        "hello",
+				Title:          "someTitle",
+				DueAt:          dueAt,
+				Priority:       todo.PriorityLow,
+				Tags:           []string{"home"},
+				IdempotencyKey: "someKey/0",
+				TenantID:       "someTenantID",
+				UserID:         "someUserID",
+			},
+			{
+				ID:      "otherID",
+				Message: "world",
+			},
+		}))
+	})
+	t.Run("it should store the position carried by the context with the todos", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			executorMock = executormock.NewMockExecutor(ctrl)
+			ctx          = offset.NewContext(context.Background(), offset.Position{
+				Group:     "someGroup",
+				Topic:     "someTopic",
+				Partition: 2,
+				Offset:    42,
+			})
+		)
+
+		creator, err := repository.New(executorMock)
+		require.NoError(t, err)
+
+		executorMock.EXPECT().
+			Exec(
+				ctx,
+				"create_todos_batch_with_offset",
+				gomock.Any(),
+				gomock.Any(),
+				todo.ChangeChannel,
+				"created",
+				"{}",
+				"someGroup",
+				"someTopic",
+				int32(2),
+				int64(42),
+			).
+			DoAndReturn(func(_ context.Context, _, sql string, _ ...interface{}) error {
+				assert.Contains(t, sql, "INSERT INTO consumer_offsets(group_id, topic, partition, next_offset)")
+				assert.Contains(t, sql, "VALUES($5::text, $6::text, $7::int, $8::bigint)")
+				return nil
+			}).
+			Times(1)
+
+		require.NoError(t, creator.CreateBatch(ctx, []*todo.Todo{{ID: "someID", Message: "hello"}}))
 	})
 }
@@ -0,0 +1,125 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// CommitMode tells when the offsets of the marked messages are committed.
+type CommitMode string
+
+const (
+	// CommitModeAuto leaves committing the marked offsets to sarama, which does it at intervals.
+	CommitModeAuto CommitMode = "auto"
+	// CommitModeSync commits the marked offsets synchronously after every batch.
+	CommitModeSync CommitMode = "sync"
+	// CommitModeExactlyOnce stores the offsets in postgres, along with the todos created by the same batch,
+	// and consumes the claimed partitions from there. They're still committed to kafka after every batch.
+	// It makes the todos persisted exactly once, without kafka transactions: the records it produces may be duplicated.
+	CommitModeExactlyOnce CommitMode = "exactly-once"
+)
+
+// CommitPolicy describes how the consumer commits offsets and what it does with the batches it fails to process.
+// Either way, messages are marked only once they're created, retried or dead-lettered, and the marked offsets
+// are committed synchronously when the partitions are rebalanced.
+type CommitPolicy struct {
+	// Mode tells when the marked offsets are committed.
+	Mode CommitMode
+	// Group is the consumer group whose offsets are stored in exactly-once mode.
+	Group string
+	// MaxFailures is the number of consecutive failures to process a batch after which its partition is paused.
+	MaxFailures int
+	// Backoff is the delay between the attempts to process a batch that failed.
+	Backoff time.Duration
+	// PauseFor is how long a partition is paused for before its batch is processed again.
+	PauseFor time.Duration
+}
+
+// DefaultCommitPolicy leaves commits to sarama and pauses partitions for 30s after 3 failures 1s apart.
+var DefaultCommitPolicy = CommitPolicy{
+	Mode:        CommitModeAuto,
+	MaxFailures: 3,
+	Backoff:     time.Second,
+	PauseFor:    30 * time.Second,
+}
+
+// CommitPolicyFromEnv reads the commit policy of group from the KAFKA_CONSUMER_COMMIT_MODE,
+// KAFKA_CONSUMER_MAX_FAILURES, KAFKA_CONSUMER_FAILURE_BACKOFF and KAFKA_CONSUMER_PAUSE environment variables.
+// They are all optional and default to DefaultCommitPolicy.
+func CommitPolicyFromEnv(group string) (CommitPolicy, error) {
+	policy := DefaultCommitPolicy
+	policy.Group = group
+
+	if v, ok := os.LookupEnv("KAFKA_CONSUMER_COMMIT_MODE"); ok {
+		policy.Mode = CommitMode(v)
+	}
+
+	if v, ok := os.LookupEnv("KAFKA_CONSUMER_MAX_FAILURES"); ok {
+		maxFailures, err := strconv.Atoi(v)
+		if err != nil {
+			return CommitPolicy{}, fmt.Errorf("could not parse KAFKA_CONSUMER_MAX_FAILURES: %w", err)
+		}
+		policy.MaxFailures = maxFailures
+	}
+
+	for k, dst := range map[string]*time.Duration{
+		"KAFKA_CONSUMER_FAILURE_BACKOFF": &policy.Backoff,
+		"KAFKA_CONSUMER_PAUSE":           &policy.PauseFor,
+	} {
+		v, ok := os.LookupEnv(k)
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return CommitPolicy{}, fmt.Errorf("could not parse %s: %w", k, err)
+		}
+		*dst = d
+	}
+
+	return policy, policy.validate()
+}
+
+// Configure sets up cfg for the consumer group to commit as described by the policy.
+// Partitions without a committed offset are consumed from the oldest message, so that the messages consumed
+// before the first commit of a group aren't skipped after a crash.
+func (p CommitPolicy) Configure(cfg *sarama.Config) {
+	cfg.Consumer.Offsets.AutoCommit.Enable = p.Mode == CommitModeAuto
+	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+}
+
+func (p CommitPolicy) validate() error {
+	switch {
+	case p.Mode != CommitModeAuto && p.Mode != CommitModeSync && p.Mode != CommitModeExactlyOnce:
+		return fmt.Errorf(
+			"commit mode must be %s, %s or %s, got %q",
+			CommitModeAuto, CommitModeSync, CommitModeExactlyOnce, p.Mode,
+		)
+	case p.Mode == CommitModeExactlyOnce && p.Group == "":
+		return fmt.Errorf("group must be not empty in %s mode", CommitModeExactlyOnce)
+	case p.MaxFailures < 1:
+		return fmt.Errorf("max failures must be at least 1, got %d", p.MaxFailures)
+	case p.Backoff < 0:
+		return fmt.Errorf("backoff cannot be negative, got %s", p.Backoff)
+	case p.PauseFor < 0:
+		return fmt.Errorf("pause cannot be negative, got %s", p.PauseFor)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
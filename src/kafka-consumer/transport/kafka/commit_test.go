@@ -0,0 +1,94 @@
+package kafka_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+)
+
+func TestCommitPolicyFromEnv(t *testing.T) {
+	t.Run("it should return the default policy because no variable is set", func(t *testing.T) {
+		policy, err := kafka.CommitPolicyFromEnv("someGroup")
+		require.NoError(t, err)
+		expected := kafka.DefaultCommitPolicy
+		expected.Group = "someGroup"
+		assert.Equal(t, expected, policy)
+	})
+	t.Run("it should return the policy described by the environment", func(t *testing.T) {
+		t.Setenv("KAFKA_CONSUMER_COMMIT_MODE", "sync")
+		t.Setenv("KAFKA_CONSUMER_MAX_FAILURES", "5")
+		t.Setenv("KAFKA_CONSUMER_FAILURE_BACKOFF", "100ms")
+		t.Setenv("KAFKA_CONSUMER_PAUSE", "1m")
+
+		policy, err := kafka.CommitPolicyFromEnv("someGroup")
+		require.NoError(t, err)
+		assert.Equal(t, kafka.CommitPolicy{
+			Mode:        kafka.CommitModeSync,
+			MaxFailures: 5,
+			Backoff:     100 * time.Millisecond,
+			PauseFor:    time.Minute,
+			Group:       "someGroup",
+		}, policy)
+	})
+	t.Run("it should return the exactly-once policy of the group", func(t *testing.T) {
+		t.Setenv("KAFKA_CONSUMER_COMMIT_MODE", "exactly-once")
+
+		policy, err := kafka.CommitPolicyFromEnv("someGroup")
+		require.NoError(t, err)
+		assert.Equal(t, kafka.CommitModeExactlyOnce, policy.Mode)
+		assert.Equal(t, "someGroup", policy.Group)
+	})
+	t.Run("it should return an error because the exactly-once mode has no group", func(t *testing.T) {
+		t.Setenv("KAFKA_CONSUMER_COMMIT_MODE", "exactly-once")
+
+		_, err := kafka.CommitPolicyFromEnv("")
+		assert.Error(t, err)
+	})
+	t.Run("it should return an error because a variable is not valid", func(t *testing.T) {
+		for k, v := range map[string]string{
+			"KAFKA_CONSUMER_COMMIT_MODE":     "never",
+			"KAFKA_CONSUMER_MAX_FAILURES":    "0",
+			"KAFKA_CONSUMER_FAILURE_BACKOFF": "soon",
+			"KAFKA_CONSUMER_PAUSE":           "-1s",
+		} {
+			t.Run(k, func(t *testing.T) {
+				t.Setenv(k, v)
+
+				_, err := kafka.CommitPolicyFromEnv("someGroup")
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestCommitPolicy_Configure(t *testing.T) {
+	t.Run("it should leave committing to sarama in auto mode", func(t *testing.T) {
+		cfg := sarama.NewConfig()
+		cfg.Consumer.Offsets.Initial = sarama.OffsetNewest
+
+		kafka.CommitPolicy{Mode: kafka.CommitModeAuto}.Configure(cfg)
+
+		assert.True(t, cfg.Consumer.Offsets.AutoCommit.Enable)
+		assert.Equal(t, sarama.OffsetOldest, cfg.Consumer.Offsets.Initial)
+	})
+	t.Run("it should disable auto commits in sync mode", func(t *testing.T) {
+		cfg := sarama.NewConfig()
+
+		kafka.CommitPolicy{Mode: kafka.CommitModeSync}.Configure(cfg)
+
+		assert.False(t, cfg.Consumer.Offsets.AutoCommit.Enable)
+		assert.Equal(t, sarama.OffsetOldest, cfg.Consumer.Offsets.Initial)
+	})
+	t.Run("it should disable auto commits in exactly-once mode", func(t *testing.T) {
+		cfg := sarama.NewConfig()
+
+		kafka.CommitPolicy{Mode: kafka.CommitModeExactlyOnce, Group: "someGroup"}.Configure(cfg)
+
+		assert.False(t, cfg.Consumer.Offsets.AutoCommit.Enable)
+	})
+}
@@ -11,84 +11,520 @@ import (
 	"github.com/opentracing/opentracing-go"
 
 	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/offset"
+	offsetrepository "github.com/andream16/go-opentracing-example/src/kafka-consumer/offset/repository"
+	operationrepository "github.com/andream16/go-opentracing-example/src/kafka-consumer/operation/repository"
 	"github.com/andream16/go-opentracing-example/src/kafka-consumer/todo/repository"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
 	"github.com/andream16/go-opentracing-example/src/shared/todo"
 	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
 )
 
-const spanName = "todo_consumer"
+const (
+	spanName      = "todo_consumer"
+	batchSpanName = "todo_consumer_batch"
+	// maxBatchSize is the maximum number of records whose todos are created in a single round trip.
+	maxBatchSize = 100
+)
 
 // Consumer represent a kafka transport consumer.
 type Consumer struct {
-	creator repository.Creator
-	tracer  tracing.Tracer
+	creator      repository.Creator
+	recorder     operationrepository.Recorder
+	retrier      Retrier
+	deadLetterer DeadLetterer
+	tracer       tracing.Tracer
+	policy       CommitPolicy
+	offsets      offsetrepository.Store
 }
 
-// NewConsumer returns a new consumer.
-func NewConsumer(creator repository.Creator, tracer tracing.Tracer) (Consumer, error) {
+// NewConsumer returns a new consumer. offsets is only used, and required, in exactly-once mode.
+func NewConsumer(
+	creator repository.Creator,
+	recorder operationrepository.Recorder,
+	retrier Retrier,
+	deadLetterer DeadLetterer,
+	tracer tracing.Tracer,
+	policy CommitPolicy,
+	offsets offsetrepository.Store,
+) (Consumer, error) {
 	switch {
 	case creator == nil:
 		return Consumer{}, errors.New("repo must be not nil")
+	case recorder == nil:
+		return Consumer{}, errors.New("recorder must be not nil")
+	case retrier == nil:
+		return Consumer{}, errors.New("retrier must be not nil")
+	case deadLetterer == nil:
+		return Consumer{}, errors.New("dead letterer must be not nil")
 	case tracer == nil:
 		return Consumer{}, errors.New("tracer must be not nil")
 	}
+
+	if err := policy.validate(); err != nil {
+		return Consumer{}, fmt.Errorf("invalid commit policy: %w", err)
+	}
+
+	if policy.Mode == CommitModeExactlyOnce && offsets == nil {
+		return Consumer{}, errors.New("offset store must be not nil in exactly-once mode")
+	}
+
 	return Consumer{
-		creator: creator,
-		tracer:  tracer,
+		creator:      creator,
+		recorder:     recorder,
+		retrier:      retrier,
+		deadLetterer: deadLetterer,
+		tracer:       tracer,
+		policy:       policy,
+		offsets:      offsets,
 	}, nil
 }
 
-func (c Consumer) Setup(sarama.ConsumerGroupSession) error {
+// Setup seeks the claimed partitions to the offsets stored in postgres in exactly-once mode. They're stored
+// along with the todos, so they can be ahead of the ones committed to kafka as well as behind them.
+func (c Consumer) Setup(session sarama.ConsumerGroupSession) error {
+	if c.policy.Mode != CommitModeExactlyOnce {
+		return nil
+	}
+
+	ctx := session.Context()
+
+	for topic, partitions := range session.Claims() {
+		var offsets map[int32]int64
+		if err := c.retry(ctx, "get stored offsets", func() (err error) {
+			offsets, err = c.offsets.Offsets(ctx, c.policy.Group, topic)
+			return err
+		}); err != nil {
+			return fmt.Errorf("could not get stored offsets of %s: %w", topic, err)
+		}
+
+		for _, partition := range partitions {
+			next, ok := offsets[partition]
+			if !ok {
+				continue
+			}
+			// The former only moves offsets backwards, the latter forwards.
+			session.ResetOffset(topic, partition, next, "")
+			session.MarkOffset(topic, partition, next, "")
+		}
+	}
+
 	return nil
 }
 
-func (c Consumer) Cleanup(sarama.ConsumerGroupSession) error {
+// Cleanup commits the marked offsets synchronously before the partitions are rebalanced,
+// so that the next owner of a partition doesn't consume its processed messages again.
+func (c Consumer) Cleanup(session sarama.ConsumerGroupSession) error {
+	session.Commit()
 	return nil
 }
 
+// ConsumeClaim creates the todos of the claimed messages, marking them once they have been either created,
+// retried or dead-lettered, and committing them as told by the commit policy.
+// Retried messages are processed once their not-before time has come.
 func (c Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for message := range claim.Messages() {
-		if err := c.ReceivedMessage(message); err != nil {
-			log.Printf("could not create todo, skipping message: %v", err)
+		batch := nextBatch(message, claim.Messages())
+
+		if err := c.process(session.Context(), claim, batch); err != nil {
+			// The session is over, the batch is consumed again by the next one.
+			return nil
+		}
+
+		if c.policy.Mode == CommitModeExactlyOnce {
+			// The todos store the position of their batch already, the records set aside don't: they're produced to
+			// kafka, which can't share a transaction with postgres, before the position is stored. A crash in between
+			// produces them again, so the retry and dead-letter topics can hold duplicates. Retried todos are created
+			// idempotently, dead-lettered records carry their origin to be told apart.
+			last := batch[len(batch)-1]
+			if err := c.retry(session.Context(), "store offset", func() error {
+				return c.offsets.Save(session.Context(), c.position(last))
+			}); err != nil {
+				return nil
+			}
+		}
+
+		for _, m := range batch {
+			session.MarkMessage(m, "")
+		}
+
+		if c.policy.Mode != CommitModeAuto {
+			session.Commit()
 		}
-		session.MarkMessage(message, "")
 	}
 
 	return nil
 }
 
+// process processes batch until each of its messages has been either created, retried or dead-lettered.
+// A batch that fails is processed again after a backoff and, after too many consecutive failures, after a pause.
+// No other message of the partition is consumed in the meantime, so that none is marked before the batch.
+// Todos are created idempotently, so processing a batch again doesn't duplicate the ones created already.
+// The messages retried or dead-lettered already aren't processed again, so that they aren't set aside twice.
+// It returns an error only when ctx is done before that.
+func (c Consumer) process(ctx context.Context, claim sarama.ConsumerGroupClaim, batch []*sarama.ConsumerMessage) error {
+	if err := waitUntilDue(ctx, batch); err != nil {
+		return err
+	}
+
+	aside := setAside{}
+
+	for failures := 1; ; failures++ {
+		pending := aside.pending(batch)
+
+		var err error
+		switch len(pending) {
+		case 0:
+			return nil
+		case 1:
+			err = c.receivedMessage(pending[0], aside)
+		default:
+			err = c.receivedMessages(pending, aside)
+		}
+
+		if !errors.Is(err, ErrDeadLetter) {
+			if err != nil {
+				log.Printf("retried or dead-lettered messages: %v", err)
+			}
+			return nil
+		}
+
+		wait := c.policy.Backoff
+		if failures%c.policy.MaxFailures == 0 {
+			wait = c.policy.PauseFor
+			log.Printf(
+				"pausing %s/%d at offset %d for %s after %d failures: %v",
+				claim.Topic(), claim.Partition(), batch[0].Offset, wait, failures, err,
+			)
+		} else {
+			log.Printf("could not process batch, processing it again in %s: %v", wait, err)
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// nextBatch returns first along with the messages that are already available, up to maxBatchSize.
+// It never waits for more messages to come in, so that a quiet topic doesn't delay todos.
+func nextBatch(first *sarama.ConsumerMessage, messages <-chan *sarama.ConsumerMessage) []*sarama.ConsumerMessage {
+	batch := []*sarama.ConsumerMessage{first}
+	for len(batch) < maxBatchSize {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, message)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// setAside holds the offsets of the messages of a batch that have been retried or dead-lettered.
+type setAside map[int64]bool
+
+// pending returns the messages of batch that haven't been set aside.
+func (s setAside) pending(batch []*sarama.ConsumerMessage) []*sarama.ConsumerMessage {
+	pending := make([]*sarama.ConsumerMessage, 0, len(batch))
+	for _, message := range batch {
+		if !s[message.Offset] {
+			pending = append(pending, message)
+		}
+	}
+	return pending
+}
+
 // ReceivedMessage contains logic for creating a todo.
+// Messages whose todo can't be created are retried or dead-lettered and the reason is returned.
 func (c Consumer) ReceivedMessage(message *sarama.ConsumerMessage) error {
+	return c.receivedMessage(message, setAside{})
+}
+
+func (c Consumer) receivedMessage(message *sarama.ConsumerMessage, aside setAside) error {
+	headers := recordHeaders(message)
+
+	span := c.startSpan(headers)
+	defer span.Finish()
+
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+	t, operationID, err := decodeTodo(message, headers)
+	if err != nil {
+		return c.fail(ctx, message, "", err, aside)
+	}
+
+	// The producer validates the message already, this guards the table from records produced by other clients.
+	if err := validation.Validate(todo.ToCreateRequest(t)); err != nil {
+		return fmt.Errorf("invalid todo: %w", c.fail(ctx, message, operationID, fatalError{err: err}, aside))
+	}
+
+	stampOwner(ctx, t)
+
+	if err := c.creator.Create(c.withPosition(ctx, message), t); err != nil {
+		return fmt.Errorf("could not create todo: %w", c.fail(ctx, message, operationID, err, aside))
+	}
+
+	c.recordOutcome(ctx, operationID, nil)
+
+	return nil
+}
+
+// ReceivedMessages contains logic for creating the todos of a batch of messages in a single round trip.
+// Each todo gets its own span, child of a span covering the whole batch.
+// Messages that can't be deserialised or carry an invalid todo are dead-lettered, while all the messages
+// whose todos could not be created are retried or dead-lettered depending on the failure.
+// When any of them could be neither, an ErrDeadLetter is returned.
+func (c Consumer) ReceivedMessages(messages []*sarama.ConsumerMessage) error {
+	return c.receivedMessages(messages, setAside{})
+}
+
+func (c Consumer) receivedMessages(messages []*sarama.ConsumerMessage, aside setAside) error {
+	batchSpan := c.tracer.StartSpan(batchSpanName)
+	defer batchSpan.Finish()
+
+	batchSpan.SetTag("batch.size", len(messages))
+
+	type item struct {
+		ctx         context.Context
+		message     *sarama.ConsumerMessage
+		todo        *todo.Todo
+		operationID string
+	}
+
+	var (
+		items         []item
+		deadLetterErr error
+	)
+
+	fail := func(ctx context.Context, message *sarama.ConsumerMessage, operationID string, reason error) {
+		if err := c.fail(ctx, message, operationID, reason, aside); errors.Is(err, ErrDeadLetter) {
+			deadLetterErr = err
+		}
+	}
+
+	for _, message := range messages {
+		headers := recordHeaders(message)
+
+		// The todo spans are finished along with the batch span, once the todos have been created.
+		span := c.startSpan(headers, opentracing.ChildOf(batchSpan.Context()))
+		defer span.Finish()
+
+		ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+		t, operationID, err := decodeTodo(message, headers)
+		if err != nil {
+			log.Printf("dead-lettering message: %v", err)
+			fail(ctx, message, "", err)
+			continue
+		}
+
+		if err := validation.Validate(todo.ToCreateRequest(t)); err != nil {
+			log.Printf("invalid todo, dead-lettering message: %v", err)
+			fail(ctx, message, operationID, fatalError{err: err})
+			continue
+		}
+
+		stampOwner(ctx, t)
+
+		items = append(items, item{ctx: ctx, message: message, todo: t, operationID: operationID})
+	}
+
+	if len(items) == 0 {
+		return deadLetterErr
+	}
+
+	var (
+		ctx          = opentracing.ContextWithSpan(context.Background(), batchSpan)
+		todos        = make([]*todo.Todo, 0, len(items))
+		operationIDs = make([]string, 0, len(items))
+	)
+
+	for _, it := range items {
+		todos = append(todos, it.todo)
+		// Records produced before operations were introduced don't carry an operation id.
+		if it.operationID != "" {
+			operationIDs = append(operationIDs, it.operationID)
+		}
+	}
+
+	// The position of the batch is stored with its todos only when the other records have been set aside,
+	// otherwise it's stored once they are.
+	createCtx := ctx
+	if deadLetterErr == nil {
+		createCtx = c.withPosition(ctx, messages[len(messages)-1])
+	}
+
+	if err := c.creator.CreateBatch(createCtx, todos); err != nil {
+		for _, it := range items {
+			fail(it.ctx, it.message, it.operationID, err)
+		}
+		if deadLetterErr != nil {
+			tracing.SetError(batchSpan, err)
+			return deadLetterErr
+		}
+		return tracing.Fail(ctx, fmt.Errorf("could not create todos: %v", err))
+	}
+
+	if len(operationIDs) > 0 {
+		if err := c.recorder.SucceedAll(ctx, operationIDs); err != nil {
+			log.Printf("could not record operations success: %v", err)
+			tracing.SetError(batchSpan, err)
+		}
+	}
+
+	return deadLetterErr
+}
+
+// position returns the position following message.
+func (c Consumer) position(message *sarama.ConsumerMessage) offset.Position {
+	return offset.Position{
+		Group:     c.policy.Group,
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset + 1,
+	}
+}
+
+// withPosition returns ctx carrying the position following message in exactly-once mode, for it to be stored
+// along with the todos.
+func (c Consumer) withPosition(ctx context.Context, message *sarama.ConsumerMessage) context.Context {
+	if c.policy.Mode != CommitModeExactlyOnce {
+		return ctx
+	}
+	return offset.NewContext(ctx, c.position(message))
+}
+
+// retry calls fn until it succeeds, waiting for the backoff of the policy in between, or ctx is done.
+func (c Consumer) retry(ctx context.Context, what string, fn func() error) error {
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		log.Printf("could not %s, trying again in %s: %v", what, c.policy.Backoff, err)
+
+		if err := sleep(ctx, c.policy.Backoff); err != nil {
+			return err
+		}
+	}
+}
+
+// fail reports reason on the span in ctx and sets message aside, adding it to aside. Messages that failed
+// transiently are retried, the others, like the ones that can't be retried anymore, are dead-lettered and their
+// operation is recorded as failed once they are. It returns reason, or an ErrDeadLetter when message could be
+// neither retried nor dead-lettered.
+// Messages are set aside before their position is stored, even in exactly-once mode, so a crash in between sets them
+// aside twice.
+func (c Consumer) fail(
+	ctx context.Context,
+	message *sarama.ConsumerMessage,
+	operationID string,
+	reason error,
+	aside setAside,
+) error {
+	tracing.SetError(opentracing.SpanFromContext(ctx), reason)
+
+	if isRetriable(reason) {
+		err := c.retrier.Retry(ctx, message, reason)
+		if err == nil {
+			aside[message.Offset] = true
+			return reason
+		}
+		if !errors.Is(err, ErrRetriesExhausted) {
+			log.Printf("could not retry message, dead-lettering it: %v", err)
+		}
+	}
+
+	if err := c.deadLetterer.DeadLetter(ctx, message, reason); err != nil {
+		log.Printf("could not dead-letter message: %v", err)
+		return fmt.Errorf("%w: %v: %v", ErrDeadLetter, reason, err)
+	}
+
+	aside[message.Offset] = true
+	c.recordOutcome(ctx, operationID, reason)
+
+	return reason
+}
+
+// startSpan starts the span of a record, following from the span that produced it.
+// The span keeps the baggage of the producer span even when it's the child of a batch span,
+// and is tagged with it.
+func (c Consumer) startSpan(headers map[string]string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	spanCtx, err := c.tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(headers))
+	if err != nil {
+		log.Printf("could not create span: %v", err)
+		return c.tracer.StartSpan(spanName, opts...)
+	}
+
+	span := c.tracer.StartSpan(spanName, append(opts, opentracing.FollowsFrom(spanCtx))...)
+	tracing.SetBaggage(span, spanCtx)
+	tracing.TagBaggage(span)
+
+	return span
+}
+
+// stampOwner sets the tenant and the user of t to the baggage items carried by the span of its record.
+func stampOwner(ctx context.Context, t *todo.Todo) {
+	t.TenantID = tracing.BaggageItem(ctx, tracing.BaggageTenantID)
+	t.UserID = tracing.BaggageItem(ctx, tracing.BaggageUserID)
+}
+
+func recordHeaders(message *sarama.ConsumerMessage) map[string]string {
 	headers := make(map[string]string, len(message.Headers))
 	for _, header := range message.Headers {
 		headers[string(header.Key)] = string(header.Value)
 	}
+	return headers
+}
 
-	var span opentracing.Span
+// decodeTodo returns the todo carried by message and the id of the operation creating it.
+func decodeTodo(message *sarama.ConsumerMessage, headers map[string]string) (*todo.Todo, string, error) {
+	var event todov1.CreateTodoEvent
+	if err := proto.Unmarshal(message.Value, &event); err != nil {
+		return nil, "", fatalError{err: fmt.Errorf("could not deserialise todo: %v", err)}
+	}
 
-	spanCtx, err := c.tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(headers))
-	if err == nil {
-		span = c.tracer.StartSpan(spanName, opentracing.FollowsFrom(spanCtx))
-	} else {
-		span = c.tracer.StartSpan(spanName)
-		log.Printf("could not create span: %v", err)
+	// Records produced before todos had an id don't carry one. Their id is derived from where they were originally
+	// produced, so that redeliveries and retries create the same todo.
+	if event.Id == "" {
+		event.Id = todo.IDFromRecord(origin(headers, message))
 	}
 
-	defer span.Finish()
+	return &todo.Todo{
+		ID:             event.Id,
+		Message:        event.Message,
+		Title:          event.Title,
+		Description:    event.Description,
+		DueAt:          todo.TimeFromProto(event.DueAt),
+		Priority:       todo.PriorityFromProto(event.Priority),
+		Tags:           event.Tags,
+		IdempotencyKey: headers[idempotency.RecordHeaderKey],
+	}, event.OperationId, nil
+}
 
-	var t todov1.CreateRequest
-	if err := proto.Unmarshal(message.Value, &t); err != nil {
-		return fmt.Errorf("could not deserialise todo: %v", err)
+// recordOutcome records the terminal outcome of the operation that produced the record.
+// Records produced before operations were introduced don't carry an operation id.
+func (c Consumer) recordOutcome(ctx context.Context, operationID string, createErr error) {
+	if operationID == "" {
+		return
 	}
 
-	if err := c.creator.Create(
-		opentracing.ContextWithSpan(context.Background(), span),
-		&todo.Todo{
-			Message: t.Message,
-		},
-	); err != nil {
-		return fmt.Errorf("could not create todo, skipping message: %v", err)
+	if createErr != nil {
+		if err := c.recorder.Fail(ctx, operationID, createErr.Error()); err != nil {
+			log.Printf("could not record operation failure: %v", err)
+			tracing.SetError(opentracing.SpanFromContext(ctx), err)
+		}
+		return
 	}
 
-	return nil
+	if err := c.recorder.Succeed(ctx, operationID); err != nil {
+		log.Printf("could not record operation success: %v", err)
+		tracing.SetError(opentracing.SpanFromContext(ctx), err)
+	}
 }
@@ -1,61 +1,365 @@
 package kafka_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/jackc/pgconn"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/offset"
 	"github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
 	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	offsetrepositorymock "github.com/andream16/go-opentracing-example/src/test/mock/kafka-consumer/offset/repository"
+	operationrecordermock "github.com/andream16/go-opentracing-example/src/test/mock/kafka-consumer/operation/repository"
 	todocreatormock "github.com/andream16/go-opentracing-example/src/test/mock/kafka-consumer/todo/repository"
+	transportkafkamock "github.com/andream16/go-opentracing-example/src/test/mock/kafka-consumer/transport/kafka"
 	opentracingmock "github.com/andream16/go-opentracing-example/src/test/mock/opentracing"
 	tracingmock "github.com/andream16/go-opentracing-example/src/test/mock/tracing"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
 )
 
 func TestNewConsumer(t *testing.T) {
 	t.Run("it should return an error because the creator is invalid", func(t *testing.T) {
-		consumer, err := kafka.NewConsumer(nil, nil)
+		consumer, err := kafka.NewConsumer(nil, nil, nil, nil, nil, kafka.DefaultCommitPolicy, nil)
 		require.Error(t, err)
 		assert.Equal(t, "repo must be not nil", err.Error())
 		assert.Empty(t, consumer)
 	})
+	t.Run("it should return an error because the recorder is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		consumer, err := kafka.NewConsumer(todocreatormock.NewMockCreator(ctrl), nil, nil, nil, nil, kafka.DefaultCommitPolicy, nil)
+		require.Error(t, err)
+		assert.Equal(t, "recorder must be not nil", err.Error())
+		assert.Empty(t, consumer)
+	})
+	t.Run("it should return an error because the retrier is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			nil,
+			nil,
+			nil,
+			kafka.DefaultCommitPolicy,
+			nil,
+		)
+		require.Error(t, err)
+		assert.Equal(t, "retrier must be not nil", err.Error())
+		assert.Empty(t, consumer)
+	})
+	t.Run("it should return an error because the dead letterer is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			transportkafkamock.NewMockRetrier(ctrl),
+			nil,
+			nil,
+			kafka.DefaultCommitPolicy,
+			nil,
+		)
+		require.Error(t, err)
+		assert.Equal(t, "dead letterer must be not nil", err.Error())
+		assert.Empty(t, consumer)
+	})
 	t.Run("it should return an error because the tracer is invalid", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		consumer, err := kafka.NewConsumer(todocreatormock.NewMockCreator(ctrl), nil)
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			transportkafkamock.NewMockRetrier(ctrl),
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			nil,
+			kafka.DefaultCommitPolicy,
+			nil,
+		)
 		require.Error(t, err)
 		assert.Equal(t, "tracer must be not nil", err.Error())
 		assert.Empty(t, consumer)
 	})
+	t.Run("it should return an error because the commit policy is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			transportkafkamock.NewMockRetrier(ctrl),
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+			kafka.CommitPolicy{Mode: "never"},
+			nil,
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid commit policy")
+		assert.Empty(t, consumer)
+	})
+	t.Run("it should return an error because the offset store is missing in exactly-once mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		policy := kafka.DefaultCommitPolicy
+		policy.Mode = kafka.CommitModeExactlyOnce
+		policy.Group = "someGroup"
+
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			transportkafkamock.NewMockRetrier(ctrl),
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+			policy,
+			nil,
+		)
+		require.Error(t, err)
+		assert.Equal(t, "offset store must be not nil in exactly-once mode", err.Error())
+		assert.Empty(t, consumer)
+	})
 	t.Run("it should return a new consumer", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		consumer, err := kafka.NewConsumer(todocreatormock.NewMockCreator(ctrl), tracingmock.NewMockTracer(ctrl))
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			transportkafkamock.NewMockRetrier(ctrl),
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+			kafka.DefaultCommitPolicy,
+			nil,
+		)
 		require.NoError(t, err)
 		assert.NotEmpty(t, consumer)
 	})
 }
 
+// session is a sarama.ConsumerGroupSession claiming partitions and recording where they're seeked to.
+type session struct {
+	sarama.ConsumerGroupSession
+	claims map[string][]int32
+	reset  map[int32]int64
+	marked map[int32]int64
+}
+
+func (s *session) Claims() map[string][]int32 { return s.claims }
+func (s *session) Context() context.Context   { return context.Background() }
+
+func (s *session) ResetOffset(_ string, partition int32, offset int64, _ string) {
+	s.reset[partition] = offset
+}
+
+func (s *session) MarkOffset(_ string, partition int32, offset int64, _ string) {
+	s.marked[partition] = offset
+}
+
+func (s *session) MarkMessage(message *sarama.ConsumerMessage, _ string) {
+	s.marked[message.Partition] = message.Offset + 1
+}
+
+type claim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c claim) Topic() string                            { return "someTopic" }
+func (c claim) Partition() int32                         { return 0 }
+func (c claim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func TestConsumer_Setup(t *testing.T) {
+	policy := kafka.DefaultCommitPolicy
+	policy.Mode = kafka.CommitModeExactlyOnce
+	policy.Group = "someGroup"
+	policy.Backoff = time.Millisecond
+
+	t.Run("it should seek the claimed partitions to their stored offsets", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockOffsets = offsetrepositorymock.NewMockStore(ctrl)
+			sess        = &session{
+				claims: map[string][]int32{"someTopic": {0, 1}},
+				reset:  make(map[int32]int64),
+				marked: make(map[int32]int64),
+			}
+		)
+
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			transportkafkamock.NewMockRetrier(ctrl),
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+			policy,
+			mockOffsets,
+		)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockOffsets.EXPECT().Offsets(gomock.Any(), "someGroup", "someTopic").Return(nil, errors.New("someErr")).Times(1),
+			mockOffsets.EXPECT().Offsets(gomock.Any(), "someGroup", "someTopic").Return(map[int32]int64{1: 42, 2: 7}, nil).Times(1),
+		)
+
+		require.NoError(t, consumer.Setup(sess))
+		assert.Equal(t, map[int32]int64{1: 42}, sess.reset)
+		assert.Equal(t, map[int32]int64{1: 42}, sess.marked)
+	})
+	t.Run("it should not seek the claimed partitions outside of exactly-once mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		consumer, err := kafka.NewConsumer(
+			todocreatormock.NewMockCreator(ctrl),
+			operationrecordermock.NewMockRecorder(ctrl),
+			transportkafkamock.NewMockRetrier(ctrl),
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			tracingmock.NewMockTracer(ctrl),
+			kafka.DefaultCommitPolicy,
+			offsetrepositorymock.NewMockStore(ctrl),
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, consumer.Setup(&session{claims: map[string][]int32{"someTopic": {0}}}))
+	})
+}
+
+func TestConsumer_ConsumeClaim(t *testing.T) {
+	t.Run("it should process a batch again without setting aside twice the messages set aside already", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRetrier      = transportkafkamock.NewMockRetrier(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			policy           = kafka.DefaultCommitPolicy
+			sess             = &session{marked: make(map[int32]int64)}
+		)
+
+		policy.Backoff = time.Millisecond
+
+		consumer, err := kafka.NewConsumer(
+			mockCreator,
+			operationrecordermock.NewMockRecorder(ctrl),
+			mockRetrier,
+			mockDeadLetterer,
+			recorder.New(),
+			policy,
+			nil,
+		)
+		require.NoError(t, err)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{Id: "someID", Message: "hello"})
+		require.NoError(t, err)
+
+		var (
+			invalid = &sarama.ConsumerMessage{Value: []byte("not a todo"), Offset: 0}
+			valid   = &sarama.ConsumerMessage{Value: value, Offset: 1}
+			c       = claim{messages: make(chan *sarama.ConsumerMessage, 2)}
+		)
+
+		c.messages <- invalid
+		c.messages <- valid
+		close(c.messages)
+
+		gomock.InOrder(
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), invalid, gomock.Any()).Return(errors.New("someErr")).Times(1),
+			mockCreator.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), valid, gomock.Any()).Return(nil).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), invalid, gomock.Any()).Return(nil).Times(1),
+		)
+
+		require.NoError(t, consumer.ConsumeClaim(sess, c))
+		assert.Equal(t, map[int32]int64{0: 2}, sess.marked)
+	})
+}
+
 func TestConsumer_ReceivedMessage(t *testing.T) {
-	t.Run("it should return an error because creating a todo failed", func(t *testing.T) {
+	t.Run("it should create the todo along with the position following the message in exactly-once mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator  = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder = operationrecordermock.NewMockRecorder(ctrl)
+			policy       = kafka.DefaultCommitPolicy
+		)
+
+		policy.Mode = kafka.CommitModeExactlyOnce
+		policy.Group = "someGroup"
+
+		consumer, err := kafka.NewConsumer(
+			mockCreator,
+			mockRecorder,
+			transportkafkamock.NewMockRetrier(ctrl),
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			recorder.New(),
+			policy,
+			offsetrepositorymock.NewMockStore(ctrl),
+		)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockCreator.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *todo.Todo) error {
+				p, ok := offset.FromContext(ctx)
+				require.True(t, ok)
+				assert.Equal(t, offset.Position{Group: "someGroup", Topic: "todos", Partition: 3, Offset: 42}, p)
+				return nil
+			}).Times(1),
+			mockRecorder.EXPECT().Succeed(gomock.Any(), "someOperationID").Return(nil).Times(1),
+		)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{
+			Id:          "someID",
+			Message:     "hello",
+			OperationId: "someOperationID",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{
+			Topic:     "todos",
+			Partition: 3,
+			Offset:    41,
+			Value:     value,
+		}))
+	})
+	t.Run("it should retry the message because creating a todo failed transiently", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		const spanName = "todo_consumer"
 
 		var (
-			mockCreator     = todocreatormock.NewMockCreator(ctrl)
-			mockTracer      = tracingmock.NewMockTracer(ctrl)
-			mockSpanContext = opentracingmock.NewMockSpanContext(ctrl)
-			mockSpan        = opentracingmock.NewMockSpan(ctrl)
-			kafkaHeaders    = []*sarama.RecordHeader{
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRetrier      = transportkafkamock.NewMockRetrier(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			mockTracer       = tracingmock.NewMockTracer(ctrl)
+			mockSpanContext  = opentracingmock.NewMockSpanContext(ctrl)
+			mockSpan         = opentracingmock.NewMockSpan(ctrl)
+			kafkaHeaders     = []*sarama.RecordHeader{
 				{
 					Key:   []byte(`key1`),
 					Value: []byte(`value1`),
@@ -66,7 +370,7 @@ func TestConsumer_ReceivedMessage(t *testing.T) {
 			}
 		)
 
-		consumer, err := kafka.NewConsumer(mockCreator, mockTracer)
+		consumer, err := kafka.NewConsumer(mockCreator, operationrecordermock.NewMockRecorder(ctrl), mockRetrier, mockDeadLetterer, mockTracer, kafka.DefaultCommitPolicy, nil)
 		require.NoError(t, err)
 		assert.NotEmpty(t, consumer)
 
@@ -81,23 +385,45 @@ func TestConsumer_ReceivedMessage(t *testing.T) {
 				StartSpan(spanName, gomock.Any()).
 				Return(mockSpan).
 				Times(1),
+			mockSpanContext.
+				EXPECT().
+				ForeachBaggageItem(gomock.Any()).
+				Times(1),
+			mockSpan.
+				EXPECT().
+				Context().
+				Return(mockSpanContext).
+				Times(1),
+			mockSpanContext.
+				EXPECT().
+				ForeachBaggageItem(gomock.Any()).
+				Times(1),
 			mockSpan.
 				EXPECT().
 				Tracer().
 				Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageTenantID).Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageUserID).Times(1),
 			mockCreator.
 				EXPECT().
-				Create(gomock.Any(), &todo.Todo{}).
+				Create(gomock.Any(), gomock.Any()).
 				Return(errors.New("someErr")).
 				Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1),
 			mockSpan.EXPECT().Finish().Times(1),
 		)
 
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{Message: "hello"})
+		require.NoError(t, err)
+
 		require.Error(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{
 			Headers: kafkaHeaders,
+			Value:   value,
 		}))
 	})
-	t.Run("it should create a new todo", func(t *testing.T) {
+	t.Run("it should create a new todo stamped with the tenant and the user carried by the baggage", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -119,7 +445,7 @@ func TestConsumer_ReceivedMessage(t *testing.T) {
 			}
 		)
 
-		consumer, err := kafka.NewConsumer(mockCreator, mockTracer)
+		consumer, err := kafka.NewConsumer(mockCreator, operationrecordermock.NewMockRecorder(ctrl), transportkafkamock.NewMockRetrier(ctrl), transportkafkamock.NewMockDeadLetterer(ctrl), mockTracer, kafka.DefaultCommitPolicy, nil)
 		require.NoError(t, err)
 		assert.NotEmpty(t, consumer)
 
@@ -134,20 +460,592 @@ func TestConsumer_ReceivedMessage(t *testing.T) {
 				StartSpan(spanName, gomock.Any()).
 				Return(mockSpan).
 				Times(1),
+			mockSpanContext.
+				EXPECT().
+				ForeachBaggageItem(gomock.Any()).
+				Times(1),
+			mockSpan.
+				EXPECT().
+				Context().
+				Return(mockSpanContext).
+				Times(1),
+			mockSpanContext.
+				EXPECT().
+				ForeachBaggageItem(gomock.Any()).
+				Times(1),
+			mockSpan.
+				EXPECT().
+				Tracer().
+				Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageTenantID).Return("someTenantID").Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageUserID).Return("someUserID").Times(1),
+			mockCreator.
+				EXPECT().
+				Create(gomock.Any(), &todo.Todo{ID: "someID", Message: "hello", TenantID: "someTenantID", UserID: "someUserID"}).
+				Return(nil).
+				Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+		)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{Id: "someID", Message: "hello"})
+		require.NoError(t, err)
+
+		require.NoError(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{
+			Headers: kafkaHeaders,
+			Value:   value,
+		}))
+	})
+	t.Run("it should create a new todo with the idempotency key carried in the headers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator  = todocreatormock.NewMockCreator(ctrl)
+			mockTracer   = tracingmock.NewMockTracer(ctrl)
+			mockSpan     = opentracingmock.NewMockSpan(ctrl)
+			kafkaHeaders = []*sarama.RecordHeader{
+				{
+					Key:   []byte(idempotency.RecordHeaderKey),
+					Value: []byte(`someKey`),
+				},
+			}
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, operationrecordermock.NewMockRecorder(ctrl), transportkafkamock.NewMockRetrier(ctrl), transportkafkamock.NewMockDeadLetterer(ctrl), mockTracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockTracer.
+				EXPECT().
+				Extract(opentracing.TextMap, gomock.Any()).
+				Return(nil, opentracing.ErrSpanContextNotFound).
+				Times(1),
+			mockTracer.
+				EXPECT().
+				StartSpan("todo_consumer").
+				Return(mockSpan).
+				Times(1),
 			mockSpan.
 				EXPECT().
 				Tracer().
 				Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageTenantID).Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageUserID).Times(1),
 			mockCreator.
 				EXPECT().
-				Create(gomock.Any(), &todo.Todo{}).
+				Create(gomock.Any(), &todo.Todo{
+					ID:             "someID",
+					Message:        "hello",
+					IdempotencyKey: "someKey",
+				}).
 				Return(nil).
 				Times(1),
 			mockSpan.EXPECT().Finish().Times(1),
 		)
 
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{Id: "someID", Message: "hello"})
+		require.NoError(t, err)
+
 		require.NoError(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{
 			Headers: kafkaHeaders,
+			Value:   value,
+		}))
+	})
+	t.Run("it should create the todo with an id derived from the original record because the record was produced without one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator = todocreatormock.NewMockCreator(ctrl)
+			mockTracer  = tracingmock.NewMockTracer(ctrl)
+			mockSpan    = opentracingmock.NewMockSpan(ctrl)
+			createdIDs  []string
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, operationrecordermock.NewMockRecorder(ctrl), transportkafkamock.NewMockRetrier(ctrl), transportkafkamock.NewMockDeadLetterer(ctrl), mockTracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		mockTracer.EXPECT().Extract(opentracing.TextMap, gomock.Any()).Return(nil, opentracing.ErrSpanContextNotFound).Times(2)
+		mockTracer.EXPECT().StartSpan("todo_consumer").Return(mockSpan).Times(2)
+		mockSpan.EXPECT().Tracer().Times(2)
+		mockSpan.EXPECT().BaggageItem(gomock.Any()).Times(4)
+		mockSpan.EXPECT().Finish().Times(2)
+		mockCreator.
+			EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, created *todo.Todo) error {
+				assert.Equal(t, "hello", created.Message)
+				createdIDs = append(createdIDs, created.ID)
+				return nil
+			}).
+			Times(2)
+
+		value, err := proto.Marshal(&todov1.CreateRequest{Message: "hello"})
+		require.NoError(t, err)
+
+		require.NoError(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{
+			Topic:     "todos",
+			Partition: 0,
+			Offset:    42,
+			Value:     value,
 		}))
+		// The same record, delivered again from a retry topic.
+		require.NoError(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{
+			Topic:  "todos.retry.5s",
+			Offset: 7,
+			Value:  value,
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte(kafka.OriginTopicHeaderKey), Value: []byte("todos")},
+				{Key: []byte(kafka.OriginPartitionHeaderKey), Value: []byte("0")},
+				{Key: []byte(kafka.OriginOffsetHeaderKey), Value: []byte("42")},
+			},
+		}))
+
+		assert.Equal(t, []string{todo.IDFromRecord("todos/0/42"), todo.IDFromRecord("todos/0/42")}, createdIDs)
+	})
+	t.Run("it should record the operation as succeeded once the todo has been created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator  = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder = operationrecordermock.NewMockRecorder(ctrl)
+			mockTracer   = tracingmock.NewMockTracer(ctrl)
+			mockSpan     = opentracingmock.NewMockSpan(ctrl)
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, mockRecorder, transportkafkamock.NewMockRetrier(ctrl), transportkafkamock.NewMockDeadLetterer(ctrl), mockTracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockTracer.EXPECT().Extract(opentracing.TextMap, gomock.Any()).Return(nil, opentracing.ErrSpanContextNotFound).Times(1),
+			mockTracer.EXPECT().StartSpan("todo_consumer").Return(mockSpan).Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageTenantID).Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageUserID).Times(1),
+			mockCreator.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Succeed(gomock.Any(), "someOperationID").Return(nil).Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+		)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{
+			Id:          "someID",
+			Message:     "hello",
+			OperationId: "someOperationID",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{Value: value}))
+	})
+	t.Run("it should record the operation as failed because creating the todo failed and it cannot be retried anymore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder     = operationrecordermock.NewMockRecorder(ctrl)
+			mockRetrier      = transportkafkamock.NewMockRetrier(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			mockTracer       = tracingmock.NewMockTracer(ctrl)
+			mockSpan         = opentracingmock.NewMockSpan(ctrl)
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, mockRecorder, mockRetrier, mockDeadLetterer, mockTracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockTracer.EXPECT().Extract(opentracing.TextMap, gomock.Any()).Return(nil, opentracing.ErrSpanContextNotFound).Times(1),
+			mockTracer.EXPECT().StartSpan("todo_consumer").Return(mockSpan).Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageTenantID).Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageUserID).Times(1),
+			mockCreator.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), gomock.Any(), gomock.Any()).Return(kafka.ErrRetriesExhausted).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Fail(gomock.Any(), "someOperationID", "someErr").Return(nil).Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+		)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{
+			Id:          "someID",
+			Message:     "hello",
+			OperationId: "someOperationID",
+		})
+		require.NoError(t, err)
+
+		require.Error(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{Value: value}))
+	})
+	t.Run("it should not record the operation as failed because the message could not be dead-lettered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRetrier      = transportkafkamock.NewMockRetrier(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+		)
+
+		consumer, err := kafka.NewConsumer(
+			mockCreator,
+			operationrecordermock.NewMockRecorder(ctrl),
+			mockRetrier,
+			mockDeadLetterer,
+			recorder.New(),
+			kafka.DefaultCommitPolicy,
+			nil,
+		)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockCreator.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), gomock.Any(), gomock.Any()).Return(kafka.ErrRetriesExhausted).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1),
+		)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{
+			Id:          "someID",
+			Message:     "hello",
+			OperationId: "someOperationID",
+		})
+		require.NoError(t, err)
+
+		err = consumer.ReceivedMessage(&sarama.ConsumerMessage{Value: value})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, kafka.ErrDeadLetter))
+	})
+	t.Run("it should record the operation as failed without creating the todo because the message is not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder     = operationrecordermock.NewMockRecorder(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			mockTracer       = tracingmock.NewMockTracer(ctrl)
+			mockSpan         = opentracingmock.NewMockSpan(ctrl)
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, mockRecorder, transportkafkamock.NewMockRetrier(ctrl), mockDeadLetterer, mockTracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockTracer.EXPECT().Extract(opentracing.TextMap, gomock.Any()).Return(nil, opentracing.ErrSpanContextNotFound).Times(1),
+			mockTracer.EXPECT().StartSpan("todo_consumer").Return(mockSpan).Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().SetTag(string(ext.Error), true).Times(1),
+			mockSpan.EXPECT().LogFields(gomock.Any()).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Fail(gomock.Any(), "someOperationID", gomock.Any()).Return(nil).Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+		)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{
+			Id:          "someID",
+			Message:     "",
+			OperationId: "someOperationID",
+		})
+		require.NoError(t, err)
+
+		require.Error(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{Value: value}))
+	})
+	t.Run("it should create a new todo with the details carried by the event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator = todocreatormock.NewMockCreator(ctrl)
+			mockTracer  = tracingmock.NewMockTracer(ctrl)
+			mockSpan    = opentracingmock.NewMockSpan(ctrl)
+			dueAt       = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, operationrecordermock.NewMockRecorder(ctrl), transportkafkamock.NewMockRetrier(ctrl), transportkafkamock.NewMockDeadLetterer(ctrl), mockTracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockTracer.EXPECT().Extract(opentracing.TextMap, gomock.Any()).Return(nil, opentracing.ErrSpanContextNotFound).Times(1),
+			mockTracer.EXPECT().StartSpan("todo_consumer").Return(mockSpan).Times(1),
+			mockSpan.EXPECT().Tracer().Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageTenantID).Times(1),
+			mockSpan.EXPECT().BaggageItem(tracing.BaggageUserID).Times(1),
+			mockCreator.EXPECT().
+				Create(gomock.Any(), &todo.Todo{
+					ID:          "someID",
+					Message:     "hello",
+					Title:       "someTitle",
+					Description: "someDescription",
+					DueAt:       dueAt,
+					Priority:    todo.PriorityLow,
+					Tags:        []string{"home"},
+				}).
+				Return(nil).
+				Times(1),
+			mockSpan.EXPECT().Finish().Times(1),
+		)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{
+			Id:          "someID",
+			Message:     "hello",
+			Title:       "someTitle",
+			Description: "someDescription",
+			DueAt:       timestamppb.New(dueAt),
+			Priority:    todov1.Priority_PRIORITY_LOW,
+			Tags:        []string{"home"},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, consumer.ReceivedMessage(&sarama.ConsumerMessage{Value: value}))
+	})
+}
+
+func TestConsumer_ReceivedMessages(t *testing.T) {
+	t.Run("it should create the valid todos of the batch at once, each under its own span", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder     = operationrecordermock.NewMockRecorder(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			tracer           = recorder.New()
+			producer         = opentracing.TextMapCarrier{}
+		)
+
+		producerSpan := tracer.StartSpan("producer")
+		producerSpan.SetBaggageItem(tracing.BaggageTenantID, "someTenantID")
+		require.NoError(t, tracer.Inject(producerSpan.Context(), opentracing.TextMap, producer))
+		producerSpan.Finish()
+
+		consumer, err := kafka.NewConsumer(mockCreator, mockRecorder, transportkafkamock.NewMockRetrier(ctrl), mockDeadLetterer, tracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Fail(gomock.Any(), "invalidOperationID", gomock.Any()).Return(nil).Times(1),
+			// Each todo is stamped with the baggage of its own record.
+			mockCreator.EXPECT().
+				CreateBatch(gomock.Any(), []*todo.Todo{
+					{ID: "someID", Message: "hello", TenantID: "someTenantID"},
+					{ID: "otherID", Message: "world"},
+				}).
+				Return(nil).
+				Times(1),
+			mockRecorder.EXPECT().SucceedAll(gomock.Any(), []string{"someOperationID", "otherOperationID"}).Return(nil).Times(1),
+		)
+
+		var messages []*sarama.ConsumerMessage
+		for i, event := range []*todov1.CreateTodoEvent{
+			{Id: "someID", Message: "hello", OperationId: "someOperationID"},
+			{Id: "invalidID", Message: "", OperationId: "invalidOperationID"},
+			{Id: "otherID", Message: "world", OperationId: "otherOperationID"},
+		} {
+			value, err := proto.Marshal(event)
+			require.NoError(t, err)
+
+			message := &sarama.ConsumerMessage{Value: value}
+			// only the first record was produced by a traced producer.
+			if i == 0 {
+				for k, v := range producer {
+					message.Headers = append(message.Headers, &sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+				}
+			}
+			messages = append(messages, message)
+		}
+
+		require.NoError(t, consumer.ReceivedMessages(messages))
+
+		var (
+			spans     = tracer.FinishedSpans()
+			batchSpan = traceassert.Span(t, spans, "todo_consumer_batch")
+			todoSpans = traceassert.Spans(spans, "todo_consumer")
+		)
+
+		require.Len(t, todoSpans, 3)
+
+		traceassert.Root(t, batchSpan)
+		traceassert.HasTag(t, batchSpan, "batch.size", 3)
+		traceassert.TraceLen(t, spans, batchSpan, 4)
+
+		var (
+			producerRecord = traceassert.Span(t, spans, "producer")
+			followers      int
+		)
+		for _, span := range todoSpans {
+			traceassert.ChildOf(t, span, batchSpan)
+			traceassert.SameTrace(t, span, batchSpan)
+			if len(span.References) > 1 {
+				traceassert.FollowsFrom(t, span, producerRecord)
+				followers++
+			}
+		}
+		assert.Equal(t, 1, followers)
+	})
+	t.Run("it should record the operations as failed because creating the todos failed and they cannot be retried anymore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder     = operationrecordermock.NewMockRecorder(ctrl)
+			mockRetrier      = transportkafkamock.NewMockRetrier(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			tracer           = recorder.New()
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, mockRecorder, mockRetrier, mockDeadLetterer, tracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		gomock.InOrder(
+			mockCreator.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), gomock.Any(), gomock.Any()).Return(kafka.ErrRetriesExhausted).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Fail(gomock.Any(), "someOperationID", "someErr").Return(nil).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), gomock.Any(), gomock.Any()).Return(kafka.ErrRetriesExhausted).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Fail(gomock.Any(), "otherOperationID", "someErr").Return(nil).Times(1),
+		)
+
+		var messages []*sarama.ConsumerMessage
+		for _, event := range []*todov1.CreateTodoEvent{
+			{Id: "someID", Message: "hello", OperationId: "someOperationID"},
+			{Id: "otherID", Message: "world", OperationId: "otherOperationID"},
+		} {
+			value, err := proto.Marshal(event)
+			require.NoError(t, err)
+			messages = append(messages, &sarama.ConsumerMessage{Value: value})
+		}
+
+		require.Error(t, consumer.ReceivedMessages(messages))
+
+		var (
+			spans     = tracer.FinishedSpans()
+			batchSpan = traceassert.Span(t, spans, "todo_consumer_batch")
+		)
+
+		traceassert.HasTag(t, batchSpan, "batch.size", 2)
+		traceassert.TraceLen(t, spans, batchSpan, 3)
+		traceassert.HasTag(t, batchSpan, string(ext.Error), true)
+		for _, span := range traceassert.Spans(spans, "todo_consumer") {
+			traceassert.ChildOf(t, span, batchSpan)
+			traceassert.HasTag(t, span, string(ext.Error), true)
+			traceassert.HasLogField(t, span, "message", "someErr")
+		}
+	})
+	t.Run("it should retry the todos without recording the operations as failed because creating them failed transiently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator  = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder = operationrecordermock.NewMockRecorder(ctrl)
+			mockRetrier  = transportkafkamock.NewMockRetrier(ctrl)
+			tracer       = recorder.New()
+		)
+
+		consumer, err := kafka.NewConsumer(
+			mockCreator,
+			mockRecorder,
+			mockRetrier,
+			transportkafkamock.NewMockDeadLetterer(ctrl),
+			tracer,
+			kafka.DefaultCommitPolicy,
+			nil,
+		)
+		require.NoError(t, err)
+
+		var messages []*sarama.ConsumerMessage
+		for _, event := range []*todov1.CreateTodoEvent{
+			{Id: "someID", Message: "hello", OperationId: "someOperationID"},
+			{Id: "otherID", Message: "world", OperationId: "otherOperationID"},
+		} {
+			value, err := proto.Marshal(event)
+			require.NoError(t, err)
+			messages = append(messages, &sarama.ConsumerMessage{Value: value})
+		}
+
+		gomock.InOrder(
+			mockCreator.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(errors.New("someErr")).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), messages[0], gomock.Any()).Return(nil).Times(1),
+			mockRetrier.EXPECT().Retry(gomock.Any(), messages[1], gomock.Any()).Return(nil).Times(1),
+		)
+
+		err = consumer.ReceivedMessages(messages)
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, kafka.ErrDeadLetter))
+	})
+	t.Run("it should dead-letter the todos without retrying them because postgres rejected them", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder     = operationrecordermock.NewMockRecorder(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			pgErr            = &pgconn.PgError{Code: "22001", Message: "value too long"}
+		)
+
+		consumer, err := kafka.NewConsumer(
+			mockCreator,
+			mockRecorder,
+			transportkafkamock.NewMockRetrier(ctrl),
+			mockDeadLetterer,
+			recorder.New(),
+			kafka.DefaultCommitPolicy,
+			nil,
+		)
+		require.NoError(t, err)
+
+		var messages []*sarama.ConsumerMessage
+		for _, event := range []*todov1.CreateTodoEvent{
+			{Id: "someID", Message: "hello", OperationId: "someOperationID"},
+			{Id: "otherID", Message: "world", OperationId: "otherOperationID"},
+		} {
+			value, err := proto.Marshal(event)
+			require.NoError(t, err)
+			messages = append(messages, &sarama.ConsumerMessage{Value: value})
+		}
+
+		gomock.InOrder(
+			mockCreator.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(fmt.Errorf("could not insert todos: %w", pgErr)).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), messages[0], gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Fail(gomock.Any(), "someOperationID", gomock.Any()).Return(nil).Times(1),
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), messages[1], gomock.Any()).Return(nil).Times(1),
+			mockRecorder.EXPECT().Fail(gomock.Any(), "otherOperationID", gomock.Any()).Return(nil).Times(1),
+		)
+
+		require.Error(t, consumer.ReceivedMessages(messages))
+	})
+	t.Run("it should return an ErrDeadLetter because a message could not be dead-lettered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockCreator      = todocreatormock.NewMockCreator(ctrl)
+			mockRecorder     = operationrecordermock.NewMockRecorder(ctrl)
+			mockDeadLetterer = transportkafkamock.NewMockDeadLetterer(ctrl)
+			tracer           = recorder.New()
+		)
+
+		consumer, err := kafka.NewConsumer(mockCreator, mockRecorder, transportkafkamock.NewMockRetrier(ctrl), mockDeadLetterer, tracer, kafka.DefaultCommitPolicy, nil)
+		require.NoError(t, err)
+
+		value, err := proto.Marshal(&todov1.CreateTodoEvent{Id: "someID", Message: "hello"})
+		require.NoError(t, err)
+
+		var (
+			invalid = &sarama.ConsumerMessage{Value: []byte("not a todo")}
+			valid   = &sarama.ConsumerMessage{Value: value}
+		)
+
+		gomock.InOrder(
+			mockDeadLetterer.EXPECT().DeadLetter(gomock.Any(), invalid, gomock.Any()).Return(errors.New("someErr")).Times(1),
+			mockCreator.EXPECT().CreateBatch(gomock.Any(), []*todo.Todo{{ID: "someID", Message: "hello"}}).Return(nil).Times(1),
+		)
+
+		err = consumer.ReceivedMessages([]*sarama.ConsumerMessage{invalid, valid})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, kafka.ErrDeadLetter))
 	})
 }
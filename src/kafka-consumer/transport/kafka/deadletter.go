@@ -0,0 +1,139 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/andream16/go-opentracing-example/src/shared/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const (
+	deadLetterSpanName = "todo_dead_letter"
+
+	// AttemptsHeaderKey is the header counting the times a record has been processed.
+	AttemptsHeaderKey = "attempts"
+	// DeadLetterErrorHeaderKey is the header carrying the reason why a record has been dead-lettered.
+	DeadLetterErrorHeaderKey = "dead-letter-error"
+	// OriginTopicHeaderKey, OriginPartitionHeaderKey and OriginOffsetHeaderKey are the headers locating
+	// the original record of a retried or dead-lettered one.
+	OriginTopicHeaderKey     = "origin-topic"
+	OriginPartitionHeaderKey = "origin-partition"
+	OriginOffsetHeaderKey    = "origin-offset"
+
+	sourcePartitionTag = "kafka.source_partition"
+	sourceOffsetTag    = "kafka.source_offset"
+)
+
+// ErrDeadLetter is returned when a record that could not be processed could not be dead-lettered either.
+var ErrDeadLetter = errors.New("could not dead-letter message")
+
+// DeadLetterer describes the dead-letter contract.
+type DeadLetterer interface {
+	// DeadLetter sets aside message, which could not be processed because of reason.
+	// ctx carries the span of the consumer that failed to process it.
+	DeadLetter(ctx context.Context, message *sarama.ConsumerMessage, reason error) error
+}
+
+// DeadLetterPublisher re-produces the records that could not be processed to a dead-letter topic.
+type DeadLetterPublisher struct {
+	topic  string
+	sender kafka.Sender
+	tracer tracing.Tracer
+}
+
+// NewDeadLetterPublisher returns a new dead-letter publisher producing to topic.
+func NewDeadLetterPublisher(topic string, sender kafka.Sender, tracer tracing.Tracer) (DeadLetterPublisher, error) {
+	switch {
+	case topic == "":
+		return DeadLetterPublisher{}, errors.New("topic must be not empty")
+	case sender == nil:
+		return DeadLetterPublisher{}, errors.New("sender must be not nil")
+	case tracer == nil:
+		return DeadLetterPublisher{}, errors.New("tracer must be not nil")
+	}
+	return DeadLetterPublisher{
+		topic:  topic,
+		sender: sender,
+		tracer: tracer,
+	}, nil
+}
+
+// DeadLetter re-produces message with its key, value and headers, along with headers carrying reason,
+// the number of attempts made to process it and where it originally comes from.
+// The publish is traced in a span following from the consumer span in ctx, whose context replaces the one
+// carried by message, so that the trace goes on from the dead-letter topic.
+func (p DeadLetterPublisher) DeadLetter(ctx context.Context, message *sarama.ConsumerMessage, reason error) error {
+	opts := []opentracing.StartSpanOption{ext.SpanKindProducer}
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		opts = append(opts, opentracing.FollowsFrom(span.Context()))
+	}
+
+	span := p.tracer.StartSpan(deadLetterSpanName, opts...)
+	defer span.Finish()
+
+	ext.MessageBusDestination.Set(span, p.topic)
+	span.SetTag(sourcePartitionTag, message.Partition)
+	span.SetTag(sourceOffsetTag, message.Offset)
+
+	headers := recordHeaders(message)
+	headers[AttemptsHeaderKey] = strconv.Itoa(attempts(headers))
+	headers[DeadLetterErrorHeaderKey] = reason.Error()
+	setOrigin(headers, message)
+
+	if err := p.tracer.Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(headers)); err != nil {
+		tracing.SetError(span, err)
+	}
+
+	if err := p.sender.SendMessage(&sarama.ProducerMessage{
+		Topic:   p.topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: kafka.ProducerHeaders(headers),
+	}); err != nil {
+		tracing.SetError(span, err)
+		return fmt.Errorf("could not produce dead-letter message: %w", err)
+	}
+
+	return nil
+}
+
+// attempts returns the number of times the record carrying headers has been processed, this time included.
+// Records that were never retried don't carry the attempts header.
+func attempts(headers map[string]string) int {
+	n, err := strconv.Atoi(headers[AttemptsHeaderKey])
+	if err != nil || n < 0 {
+		return 1
+	}
+	return n + 1
+}
+
+// setOrigin sets the headers locating the original record of message, unless it's been retried already
+// and they're set.
+func setOrigin(headers map[string]string, message *sarama.ConsumerMessage) {
+	if _, ok := headers[OriginTopicHeaderKey]; ok {
+		return
+	}
+	headers[OriginTopicHeaderKey] = message.Topic
+	headers[OriginPartitionHeaderKey] = strconv.FormatInt(int64(message.Partition), 10)
+	headers[OriginOffsetHeaderKey] = strconv.FormatInt(message.Offset, 10)
+}
+
+// origin returns where the original record of message was produced, as topic/partition/offset.
+func origin(headers map[string]string, message *sarama.ConsumerMessage) string {
+	located := make(map[string]string, 3)
+	for _, k := range []string{OriginTopicHeaderKey, OriginPartitionHeaderKey, OriginOffsetHeaderKey} {
+		if v, ok := headers[k]; ok {
+			located[k] = v
+		}
+	}
+	setOrigin(located, message)
+
+	return located[OriginTopicHeaderKey] + "/" + located[OriginPartitionHeaderKey] + "/" + located[OriginOffsetHeaderKey]
+}
@@ -0,0 +1,132 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+	sendermock "github.com/andream16/go-opentracing-example/src/test/mock/kafka"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+func TestNewDeadLetterPublisher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("it should return an error because the topic is empty", func(t *testing.T) {
+		_, err := kafka.NewDeadLetterPublisher("", sendermock.NewMockSender(ctrl), recorder.New())
+		require.Error(t, err)
+		assert.Equal(t, "topic must be not empty", err.Error())
+	})
+	t.Run("it should return an error because the sender is nil", func(t *testing.T) {
+		_, err := kafka.NewDeadLetterPublisher("someTopic", nil, recorder.New())
+		require.Error(t, err)
+		assert.Equal(t, "sender must be not nil", err.Error())
+	})
+	t.Run("it should return an error because the tracer is nil", func(t *testing.T) {
+		_, err := kafka.NewDeadLetterPublisher("someTopic", sendermock.NewMockSender(ctrl), nil)
+		require.Error(t, err)
+		assert.Equal(t, "tracer must be not nil", err.Error())
+	})
+}
+
+func TestDeadLetterPublisher_DeadLetter(t *testing.T) {
+	t.Run("it should re-produce the message with the reason, the attempts and its origin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockSender   = sendermock.NewMockSender(ctrl)
+			tracer       = recorder.New()
+			consumerSpan = tracer.StartSpan("todo_consumer")
+			produced     *sarama.ProducerMessage
+		)
+
+		publisher, err := kafka.NewDeadLetterPublisher("todos.dead-letter", mockSender, tracer)
+		require.NoError(t, err)
+
+		mockSender.EXPECT().SendMessage(gomock.Any()).DoAndReturn(func(message *sarama.ProducerMessage) error {
+			produced = message
+			return nil
+		}).Times(1)
+
+		require.NoError(t, publisher.DeadLetter(
+			opentracing.ContextWithSpan(context.Background(), consumerSpan),
+			&sarama.ConsumerMessage{
+				Topic:     "todos",
+				Partition: 2,
+				Offset:    42,
+				Key:       []byte("someKey"),
+				Value:     []byte("someValue"),
+				Headers: []*sarama.RecordHeader{
+					{Key: []byte("someHeader"), Value: []byte("someHeaderValue")},
+					{Key: []byte(kafka.AttemptsHeaderKey), Value: []byte("1")},
+				},
+			},
+			errors.New("someErr"),
+		))
+
+		consumerSpan.Finish()
+
+		require.NotNil(t, produced)
+		assert.Equal(t, "todos.dead-letter", produced.Topic)
+		assert.Equal(t, sarama.ByteEncoder("someKey"), produced.Key)
+		assert.Equal(t, sarama.ByteEncoder("someValue"), produced.Value)
+
+		headers := make(map[string]string, len(produced.Headers))
+		for _, header := range produced.Headers {
+			headers[string(header.Key)] = string(header.Value)
+		}
+
+		assert.Equal(t, "someHeaderValue", headers["someHeader"])
+		assert.Equal(t, "2", headers[kafka.AttemptsHeaderKey])
+		assert.Equal(t, "someErr", headers[kafka.DeadLetterErrorHeaderKey])
+		assert.Equal(t, "todos", headers[kafka.OriginTopicHeaderKey])
+		assert.Equal(t, "2", headers[kafka.OriginPartitionHeaderKey])
+		assert.Equal(t, "42", headers[kafka.OriginOffsetHeaderKey])
+
+		var (
+			spans          = tracer.FinishedSpans()
+			deadLetterSpan = traceassert.Span(t, spans, "todo_dead_letter")
+		)
+
+		traceassert.FollowsFrom(t, deadLetterSpan, traceassert.Span(t, spans, "todo_consumer"))
+		traceassert.HasTag(t, deadLetterSpan, string(ext.SpanKind), ext.SpanKindProducerEnum)
+		traceassert.HasTag(t, deadLetterSpan, string(ext.MessageBusDestination), "todos.dead-letter")
+		traceassert.NoTag(t, deadLetterSpan, string(ext.Error))
+
+		spanCtx, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(headers))
+		require.NoError(t, err)
+		assert.NotNil(t, spanCtx)
+	})
+	t.Run("it should return an error and mark the span as failed because the message could not be produced", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockSender = sendermock.NewMockSender(ctrl)
+			tracer     = recorder.New()
+		)
+
+		publisher, err := kafka.NewDeadLetterPublisher("todos.dead-letter", mockSender, tracer)
+		require.NoError(t, err)
+
+		mockSender.EXPECT().SendMessage(gomock.Any()).Return(errors.New("someErr")).Times(1)
+
+		require.Error(t, publisher.DeadLetter(context.Background(), &sarama.ConsumerMessage{}, errors.New("otherErr")))
+
+		span := traceassert.Span(t, tracer.FinishedSpans(), "todo_dead_letter")
+		traceassert.Root(t, span)
+		traceassert.HasTag(t, span, string(ext.Error), true)
+		traceassert.HasLogField(t, span, "message", "someErr")
+	})
+}
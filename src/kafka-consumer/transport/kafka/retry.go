@@ -0,0 +1,213 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/jackc/pgconn"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/andream16/go-opentracing-example/src/shared/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const (
+	retrySpanName = "todo_retry"
+
+	// NotBeforeHeaderKey is the header carrying the unix time, in milliseconds, before which a retried record
+	// must not be processed.
+	NotBeforeHeaderKey = "not-before"
+	// RetryErrorHeaderKey is the header carrying the reason why a record is being retried.
+	RetryErrorHeaderKey = "retry-error"
+
+	retryAttemptTag = "retry.attempt"
+	retryDelayTag   = "retry.delay"
+)
+
+// ErrRetriesExhausted is returned when a record has been through all the retry tiers already.
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// RetryTier is a topic holding the records to be processed again after Delay.
+type RetryTier struct {
+	Topic string
+	Delay time.Duration
+}
+
+// ParseRetryTiers returns the retry tiers of topic for the given comma separated delays, e.g. "5s,1m".
+// Each tier is named after topic and its delay, e.g. "todos.retry.5s". No delays means no tiers.
+func ParseRetryTiers(topic, delays string) ([]RetryTier, error) {
+	if strings.TrimSpace(delays) == "" {
+		return nil, nil
+	}
+
+	var tiers []RetryTier
+	for _, d := range strings.Split(delays, ",") {
+		d = strings.TrimSpace(d)
+
+		delay, err := time.ParseDuration(d)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse retry delay %q: %w", d, err)
+		}
+		if delay <= 0 {
+			return nil, fmt.Errorf("retry delay must be positive, got %s", d)
+		}
+
+		tiers = append(tiers, RetryTier{Topic: topic + ".retry." + d, Delay: delay})
+	}
+
+	return tiers, nil
+}
+
+// Retrier describes the retry contract.
+type Retrier interface {
+	// Retry schedules message, which failed transiently because of reason, to be processed again later on.
+	// ctx carries the span of the consumer that failed to process it.
+	// It returns ErrRetriesExhausted when message can't be retried anymore.
+	Retry(ctx context.Context, message *sarama.ConsumerMessage, reason error) error
+}
+
+// RetryPublisher retries records by re-producing them to a tier of retry topics, a tier further at each attempt.
+type RetryPublisher struct {
+	tiers  []RetryTier
+	sender kafka.Sender
+	tracer tracing.Tracer
+}
+
+// NewRetryPublisher returns a new retry publisher producing to tiers, in order.
+func NewRetryPublisher(tiers []RetryTier, sender kafka.Sender, tracer tracing.Tracer) (RetryPublisher, error) {
+	switch {
+	case sender == nil:
+		return RetryPublisher{}, errors.New("sender must be not nil")
+	case tracer == nil:
+		return RetryPublisher{}, errors.New("tracer must be not nil")
+	}
+
+	for _, tier := range tiers {
+		if tier.Topic == "" || tier.Delay <= 0 {
+			return RetryPublisher{}, fmt.Errorf("invalid retry tier %+v", tier)
+		}
+	}
+
+	return RetryPublisher{
+		tiers:  tiers,
+		sender: sender,
+		tracer: tracer,
+	}, nil
+}
+
+// Retry re-produces message to the tier following the one it comes from, with its key, value and headers,
+// along with headers carrying reason, the number of attempts made to process it, where it originally comes from
+// and the time it can be processed again at.
+// The publish is traced in a span following from the consumer span in ctx, whose context replaces the one
+// carried by message, so that the trace goes on from the retry topic.
+func (p RetryPublisher) Retry(ctx context.Context, message *sarama.ConsumerMessage, reason error) error {
+	var (
+		headers = recordHeaders(message)
+		attempt = attempts(headers)
+	)
+
+	if attempt > len(p.tiers) {
+		return ErrRetriesExhausted
+	}
+
+	tier := p.tiers[attempt-1]
+
+	opts := []opentracing.StartSpanOption{ext.SpanKindProducer}
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		opts = append(opts, opentracing.FollowsFrom(span.Context()))
+	}
+
+	span := p.tracer.StartSpan(retrySpanName, opts...)
+	defer span.Finish()
+
+	ext.MessageBusDestination.Set(span, tier.Topic)
+	span.SetTag(retryAttemptTag, attempt)
+	span.SetTag(retryDelayTag, tier.Delay.String())
+
+	headers[AttemptsHeaderKey] = strconv.Itoa(attempt)
+	headers[RetryErrorHeaderKey] = reason.Error()
+	headers[NotBeforeHeaderKey] = strconv.FormatInt(time.Now().Add(tier.Delay).UnixMilli(), 10)
+	setOrigin(headers, message)
+
+	if err := p.tracer.Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(headers)); err != nil {
+		tracing.SetError(span, err)
+	}
+
+	if err := p.sender.SendMessage(&sarama.ProducerMessage{
+		Topic:   tier.Topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: kafka.ProducerHeaders(headers),
+	}); err != nil {
+		tracing.SetError(span, err)
+		return fmt.Errorf("could not produce retry message: %w", err)
+	}
+
+	return nil
+}
+
+// fatalError is an error that processing a record again won't solve.
+type fatalError struct {
+	err error
+}
+
+func (e fatalError) Error() string {
+	return e.err.Error()
+}
+
+func (e fatalError) Unwrap() error {
+	return e.err
+}
+
+// isRetriable reports whether processing a record that failed because of err again may succeed.
+// Errors are retriable unless they're fatal or postgres rejected the data: data exceptions, integrity
+// constraint violations and syntax errors or access rule violations won't go away by themselves.
+func isRetriable(err error) bool {
+	var fatal fatalError
+	if errors.As(err, &fatal) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && len(pgErr.Code) >= 2 {
+		switch pgErr.Code[:2] {
+		case "22", "23", "42":
+			return false
+		}
+	}
+
+	return true
+}
+
+// waitUntilDue waits until all the messages can be processed, as told by their not-before header.
+// Retried records are produced in order to topics with a fixed delay, so it waits for the last one.
+func waitUntilDue(ctx context.Context, messages []*sarama.ConsumerMessage) error {
+	var notBefore time.Time
+	for _, message := range messages {
+		for _, header := range message.Headers {
+			if string(header.Key) != NotBeforeHeaderKey {
+				continue
+			}
+			ms, err := strconv.ParseInt(string(header.Value), 10, 64)
+			if err != nil {
+				continue
+			}
+			if t := time.UnixMilli(ms); t.After(notBefore) {
+				notBefore = t
+			}
+		}
+	}
+
+	d := time.Until(notBefore)
+	if d <= 0 {
+		return nil
+	}
+
+	return sleep(ctx, d)
+}
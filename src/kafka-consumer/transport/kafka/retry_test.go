@@ -0,0 +1,178 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+	sendermock "github.com/andream16/go-opentracing-example/src/test/mock/kafka"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+var someRetryTiers = []kafka.RetryTier{
+	{Topic: "todos.retry.5s", Delay: 5 * time.Second},
+	{Topic: "todos.retry.1m", Delay: time.Minute},
+}
+
+func TestParseRetryTiers(t *testing.T) {
+	t.Run("it should return a tier for each delay, named after the topic and the delay", func(t *testing.T) {
+		tiers, err := kafka.ParseRetryTiers("todos", "5s, 1m")
+		require.NoError(t, err)
+		assert.Equal(t, someRetryTiers, tiers)
+	})
+	t.Run("it should return no tiers because there are no delays", func(t *testing.T) {
+		tiers, err := kafka.ParseRetryTiers("todos", "")
+		require.NoError(t, err)
+		assert.Empty(t, tiers)
+	})
+	t.Run("it should return an error because a delay is not valid", func(t *testing.T) {
+		for _, delays := range []string{"5s,soon", "5s,0s", "-1m"} {
+			_, err := kafka.ParseRetryTiers("todos", delays)
+			assert.Error(t, err, delays)
+		}
+	})
+}
+
+func TestNewRetryPublisher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("it should return an error because the sender is nil", func(t *testing.T) {
+		_, err := kafka.NewRetryPublisher(someRetryTiers, nil, recorder.New())
+		require.Error(t, err)
+		assert.Equal(t, "sender must be not nil", err.Error())
+	})
+	t.Run("it should return an error because the tracer is nil", func(t *testing.T) {
+		_, err := kafka.NewRetryPublisher(someRetryTiers, sendermock.NewMockSender(ctrl), nil)
+		require.Error(t, err)
+		assert.Equal(t, "tracer must be not nil", err.Error())
+	})
+	t.Run("it should return an error because a tier is not valid", func(t *testing.T) {
+		_, err := kafka.NewRetryPublisher([]kafka.RetryTier{{Topic: "todos.retry.5s"}}, sendermock.NewMockSender(ctrl), recorder.New())
+		require.Error(t, err)
+	})
+}
+
+func TestRetryPublisher_Retry(t *testing.T) {
+	t.Run("it should re-produce the message to the tier following the one it comes from", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockSender   = sendermock.NewMockSender(ctrl)
+			tracer       = recorder.New()
+			consumerSpan = tracer.StartSpan("todo_consumer")
+			produced     *sarama.ProducerMessage
+		)
+
+		publisher, err := kafka.NewRetryPublisher(someRetryTiers, mockSender, tracer)
+		require.NoError(t, err)
+
+		mockSender.EXPECT().SendMessage(gomock.Any()).DoAndReturn(func(message *sarama.ProducerMessage) error {
+			produced = message
+			return nil
+		}).Times(1)
+
+		before := time.Now()
+
+		require.NoError(t, publisher.Retry(
+			opentracing.ContextWithSpan(context.Background(), consumerSpan),
+			&sarama.ConsumerMessage{
+				Topic:  "todos.retry.5s",
+				Offset: 7,
+				Key:    []byte("someKey"),
+				Value:  []byte("someValue"),
+				Headers: []*sarama.RecordHeader{
+					{Key: []byte("someHeader"), Value: []byte("someHeaderValue")},
+					{Key: []byte(kafka.AttemptsHeaderKey), Value: []byte("1")},
+					{Key: []byte(kafka.OriginTopicHeaderKey), Value: []byte("todos")},
+					{Key: []byte(kafka.OriginPartitionHeaderKey), Value: []byte("0")},
+					{Key: []byte(kafka.OriginOffsetHeaderKey), Value: []byte("42")},
+				},
+			},
+			errors.New("someErr"),
+		))
+
+		consumerSpan.Finish()
+
+		require.NotNil(t, produced)
+		assert.Equal(t, "todos.retry.1m", produced.Topic)
+		assert.Equal(t, sarama.ByteEncoder("someKey"), produced.Key)
+		assert.Equal(t, sarama.ByteEncoder("someValue"), produced.Value)
+
+		headers := make(map[string]string, len(produced.Headers))
+		for _, header := range produced.Headers {
+			headers[string(header.Key)] = string(header.Value)
+		}
+
+		assert.Equal(t, "someHeaderValue", headers["someHeader"])
+		assert.Equal(t, "2", headers[kafka.AttemptsHeaderKey])
+		assert.Equal(t, "someErr", headers[kafka.RetryErrorHeaderKey])
+		assert.Equal(t, "todos", headers[kafka.OriginTopicHeaderKey])
+		assert.Equal(t, "42", headers[kafka.OriginOffsetHeaderKey])
+
+		notBefore, err := strconv.ParseInt(headers[kafka.NotBeforeHeaderKey], 10, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, notBefore, before.Add(time.Minute).UnixMilli())
+
+		var (
+			spans     = tracer.FinishedSpans()
+			retrySpan = traceassert.Span(t, spans, "todo_retry")
+		)
+
+		traceassert.FollowsFrom(t, retrySpan, traceassert.Span(t, spans, "todo_consumer"))
+		traceassert.HasTag(t, retrySpan, string(ext.SpanKind), ext.SpanKindProducerEnum)
+		traceassert.HasTag(t, retrySpan, string(ext.MessageBusDestination), "todos.retry.1m")
+		traceassert.HasTag(t, retrySpan, "retry.attempt", 2)
+		traceassert.HasTag(t, retrySpan, "retry.delay", "1m0s")
+	})
+	t.Run("it should return ErrRetriesExhausted because the message has been through all the tiers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		tracer := recorder.New()
+
+		publisher, err := kafka.NewRetryPublisher(someRetryTiers, sendermock.NewMockSender(ctrl), tracer)
+		require.NoError(t, err)
+
+		err = publisher.Retry(context.Background(), &sarama.ConsumerMessage{
+			Headers: []*sarama.RecordHeader{{Key: []byte(kafka.AttemptsHeaderKey), Value: []byte("2")}},
+		}, errors.New("someErr"))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, kafka.ErrRetriesExhausted))
+		assert.Empty(t, tracer.FinishedSpans())
+	})
+	t.Run("it should return an error and mark the span as failed because the message could not be produced", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			mockSender = sendermock.NewMockSender(ctrl)
+			tracer     = recorder.New()
+		)
+
+		publisher, err := kafka.NewRetryPublisher(someRetryTiers, mockSender, tracer)
+		require.NoError(t, err)
+
+		mockSender.EXPECT().SendMessage(gomock.Any()).Return(errors.New("someErr")).Times(1)
+
+		err = publisher.Retry(context.Background(), &sarama.ConsumerMessage{}, errors.New("otherErr"))
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, kafka.ErrRetriesExhausted))
+
+		span := traceassert.Span(t, tracer.FinishedSpans(), "todo_retry")
+		traceassert.HasTag(t, span, string(ext.MessageBusDestination), "todos.retry.5s")
+		traceassert.HasTag(t, span, string(ext.Error), true)
+	})
+}
@@ -1,9 +1,60 @@
 package postgres
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoRows is returned by Row.Scan when a query selects no rows.
+var ErrNoRows = errors.New("no rows in result set")
 
 // Executor describes the executor interface.
 type Executor interface {
 	// Exec abstracts the query execution. queryName is used for tracing and prepared statements.
 	Exec(ctx context.Context, queryName, sql string, args ...interface{}) error
 }
+
+// Transactor describes the transactor interface.
+type Transactor interface {
+	// InTx runs fn within a transaction, committed when fn returns nil and rolled back otherwise.
+	// The queries run with the context passed to fn are part of the transaction, whatever runs them.
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Querier describes the querier interface.
+type Querier interface {
+	// Query abstracts a query returning rows. queryName is used for tracing and prepared statements.
+	Query(ctx context.Context, queryName, sql string, args ...interface{}) (Rows, error)
+	// QueryRow abstracts a query returning at most one row. queryName is used for tracing and prepared statements.
+	QueryRow(ctx context.Context, queryName, sql string, args ...interface{}) Row
+}
+
+// Row describes a single row returned by a query.
+type Row interface {
+	// Scan reads the row values into dest. It returns ErrNoRows when the query selected no rows.
+	Scan(dest ...interface{}) error
+}
+
+// Rows describes the rows returned by a query.
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+	Close()
+}
+
+// Listener describes the listener interface.
+type Listener interface {
+	// Listen subscribes to the notifications sent on channel.
+	Listen(ctx context.Context, channel string) error
+	// WaitForNotification blocks until a notification is received on a listened channel and returns its payload.
+	WaitForNotification(ctx context.Context) (string, error)
+	// Close closes the connection of the listener.
+	Close(ctx context.Context) error
+}
+
+// ListenerFactory describes the listener factory interface.
+type ListenerFactory interface {
+	// NewListener returns a listener on a new dedicated connection, to be closed once done with.
+	NewListener(ctx context.Context) (Listener, error)
+}
@@ -0,0 +1,47 @@
+package pgxwrapper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+)
+
+// Listener receives postgres notifications on a dedicated connection,
+// as LISTEN only applies to the session that issued it.
+type Listener struct {
+	conn *pgx.Conn
+}
+
+// NewListener returns a new Listener connected with the configuration of the pool.
+func (p PgxWrapper) NewListener(ctx context.Context) (postgres.Listener, error) {
+	conn, err := pgx.ConnectConfig(ctx, p.pool.Config().ConnConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect: %w", err)
+	}
+	return &Listener{conn: conn}, nil
+}
+
+// Listen subscribes to the notifications sent on channel.
+func (l *Listener) Listen(ctx context.Context, channel string) error {
+	if _, err := l.conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("could not listen to channel %s: %w", channel, err)
+	}
+	return nil
+}
+
+// WaitForNotification blocks until a notification is received on a listened channel and returns its payload.
+func (l *Listener) WaitForNotification(ctx context.Context) (string, error) {
+	n, err := l.conn.WaitForNotification(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not wait for notification: %w", err)
+	}
+	return n.Payload, nil
+}
+
+// Close closes the dedicated connection.
+func (l *Listener) Close(ctx context.Context) error {
+	return l.conn.Close(ctx)
+}
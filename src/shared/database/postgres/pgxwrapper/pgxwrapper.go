@@ -2,57 +2,158 @@ package pgxwrapper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
 )
 
 // PgxWrapper is a wrapper to jackc/pgx/v4.
 type PgxWrapper struct {
-	tracer opentracing.Tracer
-	pool   *pgxpool.Pool
+	tracer      opentracing.Tracer
+	queryTracer QueryTracer
+	pool        *pgxpool.Pool
 }
 
 // New returns a new PgxWrapper given a postgresql dsn.
-// The wrapper has built in tracing.
+// The wrapper has built in tracing: every query run on its connections is traced by a QueryTracer,
+// query arguments being redacted unless traceArgs is set.
 // The connection will be retried until completion.
 func New(
 	ctx context.Context,
 	dsn string,
 	waitFor time.Duration,
 	tracer opentracing.Tracer,
+	traceArgs bool,
 ) (PgxWrapper, error) {
 	cfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return PgxWrapper{}, fmt.Errorf("could not create new connection configuration: %w", err)
 	}
 
+	queryTracer, err := NewQueryTracer(tracer, cfg.ConnConfig, traceArgs)
+	if err != nil {
+		return PgxWrapper{}, fmt.Errorf("could not create new query tracer: %w", err)
+	}
+
+	cfg.ConnConfig.Logger = queryTracer
+	cfg.ConnConfig.LogLevel = pgx.LogLevelInfo
+
 	pool, err := newPgxPool(ctx, cfg, waitFor)
 	if err != nil {
 		return PgxWrapper{}, fmt.Errorf("could not create new connection pool: %w", err)
 	}
 
 	return PgxWrapper{
-		pool:   pool,
-		tracer: tracer,
+		pool:        pool,
+		tracer:      tracer,
+		queryTracer: queryTracer,
 	}, nil
 }
 
+// txKey is the context key of the transaction started by InTx.
+type txKey struct{}
+
+// conn runs the queries of a pool or of a transaction.
+type conn interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// InTx is pgx's concrete implementation for running fn within a transaction.
+// Calling it again with the context passed to fn runs within the same transaction.
+func (p PgxWrapper) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return fmt.Errorf("%w, could not roll back transaction: %v", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// conn returns the transaction carried by ctx, if any, or the pool.
+func (p PgxWrapper) conn(ctx context.Context) conn {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return p.pool
+}
+
 // Exec is pgx's concrete implementation for executing a query with tracing.
 func (p PgxWrapper) Exec(ctx context.Context, queryName, sql string, args ...interface{}) error {
-	span, _ := opentracing.StartSpanFromContext(ctx, queryName)
-	span.Finish()
+	span, ctx := p.startSpan(ctx, queryName, sql)
+	defer span.Finish()
 
-	if _, err := p.pool.Exec(ctx, sql, args...); err != nil {
+	if _, err := p.conn(ctx).Exec(ctx, sql, args...); err != nil {
+		tracing.SetError(span, err)
 		return fmt.Errorf("could not execute query: %w", err)
 	}
 
 	return nil
 }
 
+// Query is pgx's concrete implementation for executing a query returning rows with tracing.
+// The query span is finished once the rows are closed.
+func (p PgxWrapper) Query(ctx context.Context, queryName, sql string, args ...interface{}) (postgres.Rows, error) {
+	span, ctx := p.startSpan(ctx, queryName, sql)
+
+	rows, err := p.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		tracing.SetError(span, err)
+		span.Finish()
+		return nil, fmt.Errorf("could not execute query: %w", err)
+	}
+
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+// QueryRow is pgx's concrete implementation for executing a query returning at most one row with tracing.
+// The query span is finished once the row is scanned.
+func (p PgxWrapper) QueryRow(ctx context.Context, queryName, sql string, args ...interface{}) postgres.Row {
+	span, ctx := p.startSpan(ctx, queryName, sql)
+
+	return row{row: p.conn(ctx).QueryRow(ctx, sql, args...), span: span}
+}
+
+// startSpan starts the span of a query named queryName, child of the span in ctx.
+// The returned context carries the span for QueryTracer to complete it.
+func (p PgxWrapper) startSpan(ctx context.Context, queryName, sql string) (opentracing.Span, context.Context) {
+	opts := []opentracing.StartSpanOption{ext.SpanKindRPCClient}
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+
+	span := p.tracer.StartSpan(queryName, opts...)
+	p.queryTracer.tag(span, sql)
+
+	return span, contextWithQuerySpan(ctx, span)
+}
+
 // GetConn returns the underlying pgx connection.
 func (p PgxWrapper) GetConn(ctx context.Context) (*pgx.Conn, error) {
 	conn, err := p.pool.Acquire(ctx)
@@ -74,3 +175,40 @@ func newPgxPool(ctx context.Context, config *pgxpool.Config, waitFor time.Durati
 
 	return pool, nil
 }
+
+// row translates pgx's no rows error into postgres.ErrNoRows and finishes the query span once scanned.
+type row struct {
+	row  pgx.Row
+	span opentracing.Span
+}
+
+func (r row) Scan(dest ...interface{}) error {
+	defer r.span.Finish()
+
+	if err := r.row.Scan(dest...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return postgres.ErrNoRows
+		}
+		tracing.SetError(r.span, err)
+		return fmt.Errorf("could not scan row: %w", err)
+	}
+	return nil
+}
+
+// tracedRows finishes the query span once the rows are closed.
+type tracedRows struct {
+	pgx.Rows
+	span opentracing.Span
+	once sync.Once
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+
+	r.once.Do(func() {
+		if err := r.Rows.Err(); err != nil {
+			tracing.SetError(r.span, err)
+		}
+		r.span.Finish()
+	})
+}
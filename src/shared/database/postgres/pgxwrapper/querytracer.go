@@ -0,0 +1,131 @@
+package pgxwrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const (
+	dbType         = "sql"
+	component      = "pgx"
+	rowsAffected   = "db.rows_affected"
+	argsTag        = "db.args"
+	redactedArgs   = "[redacted]"
+	spanNamePrefix = "postgres_"
+)
+
+type querySpanKey struct{}
+
+// contextWithQuerySpan returns a copy of ctx carrying the span of a query issued through PgxWrapper,
+// so that QueryTracer completes it rather than starting a new one.
+func contextWithQuerySpan(ctx context.Context, span opentracing.Span) context.Context {
+	return context.WithValue(opentracing.ContextWithSpan(ctx, span), querySpanKey{}, span)
+}
+
+// QueryTracer traces the queries run by pgx as a pgx.Logger, the only hook pgx v4 offers into query execution.
+// It's called once queries complete, so it completes the spans PgxWrapper starts around the queries it issues
+// with the affected rows and traces the other queries, e.g. migrations and LISTEN, with spans starting when they did.
+// Failed queries are reported without their duration, hence the spans started by PgxWrapper.
+type QueryTracer struct {
+	tracer    opentracing.Tracer
+	cfg       *pgx.ConnConfig
+	traceArgs bool
+}
+
+// NewQueryTracer returns a new QueryTracer tracing the queries run on connections configured by cfg.
+// Query arguments are redacted unless traceArgs is set.
+func NewQueryTracer(tracer opentracing.Tracer, cfg *pgx.ConnConfig, traceArgs bool) (QueryTracer, error) {
+	switch {
+	case tracer == nil:
+		return QueryTracer{}, errors.New("tracer cannot be nil")
+	case cfg == nil:
+		return QueryTracer{}, errors.New("cfg cannot be nil")
+	}
+	return QueryTracer{
+		tracer:    tracer,
+		cfg:       cfg,
+		traceArgs: traceArgs,
+	}, nil
+}
+
+// Log traces the executed queries, other messages are ignored.
+func (qt QueryTracer) Log(ctx context.Context, _ pgx.LogLevel, msg string, data map[string]interface{}) {
+	switch msg {
+	case "Exec", "Query", "BatchResult.Exec", "BatchResult.Query", "BatchResult.Close":
+	default:
+		return
+	}
+
+	// Spans started by PgxWrapper are tagged with the query error by PgxWrapper itself.
+	if span, ok := ctx.Value(querySpanKey{}).(opentracing.Span); ok {
+		qt.complete(span, data)
+		return
+	}
+
+	sql, _ := data["sql"].(string)
+
+	opts := []opentracing.StartSpanOption{ext.SpanKindRPCClient}
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+	if d, ok := data["time"].(time.Duration); ok {
+		opts = append(opts, opentracing.StartTime(time.Now().Add(-d)))
+	}
+
+	span := qt.tracer.StartSpan(operationName(sql), opts...)
+	defer span.Finish()
+
+	qt.tag(span, sql)
+	qt.complete(span, data)
+	if err, ok := data["err"].(error); ok {
+		tracing.SetError(span, err)
+	}
+}
+
+// tag tags span with the database semantic conventions of a query running sql.
+func (qt QueryTracer) tag(span opentracing.Span, sql string) {
+	ext.Component.Set(span, component)
+	ext.DBType.Set(span, dbType)
+	ext.DBInstance.Set(span, qt.cfg.Database)
+	ext.DBUser.Set(span, qt.cfg.User)
+	ext.DBStatement.Set(span, sql)
+	ext.PeerHostname.Set(span, qt.cfg.Host)
+	ext.PeerPort.Set(span, qt.cfg.Port)
+	ext.PeerAddress.Set(span, qt.cfg.Host+":"+strconv.Itoa(int(qt.cfg.Port)))
+	tracing.TagBaggage(span)
+}
+
+// complete tags span with the arguments and the affected rows of a query.
+func (qt QueryTracer) complete(span opentracing.Span, data map[string]interface{}) {
+	if qt.traceArgs {
+		span.SetTag(argsTag, fmt.Sprint(data["args"]))
+	} else {
+		span.SetTag(argsTag, redactedArgs)
+	}
+
+	if commandTag, ok := data["commandTag"].(interface{ RowsAffected() int64 }); ok {
+		span.SetTag(rowsAffected, commandTag.RowsAffected())
+	}
+	if rowCount, ok := data["rowCount"].(int); ok {
+		span.SetTag(rowsAffected, int64(rowCount))
+	}
+}
+
+// operationName returns the name of the span of a query running sql, e.g. postgres_select.
+func operationName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return spanNamePrefix + "query"
+	}
+	return spanNamePrefix + strings.ToLower(fields[0])
+}
@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// HeaderName is the http header carrying the idempotency key.
+	HeaderName = "Idempotency-Key"
+	// MetadataKey is the grpc metadata key carrying the idempotency key.
+	MetadataKey = "idempotency-key"
+	// RecordHeaderKey is the kafka record header carrying the idempotency key.
+	RecordHeaderKey = "idempotency-key"
+	// MaxKeyLength is the maximum accepted length of an idempotency key.
+	MaxKeyLength = 255
+)
+
+// Validate checks that key is an acceptable idempotency key. An empty key is valid and means no idempotency.
+func Validate(key string) error {
+	if len(key) > MaxKeyLength {
+		return fmt.Errorf("idempotency key cannot be longer than %d characters", MaxKeyLength)
+	}
+	return nil
+}
+
+// ItemKey returns the idempotency key of the item at index of a batch created with key.
+// It returns an empty key when key is empty.
+func ItemKey(key string, index int) string {
+	if key == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", key, index)
+}
+
+// Scope returns key scoped to the tenant and the user that sent it, so that the same key sent by different tenants
+// or users never refers to the same todo. It returns an empty key when key is empty.
+func Scope(key, tenantID, userID string) string {
+	if key == "" {
+		return ""
+	}
+	return url.PathEscape(tenantID) + "/" + url.PathEscape(userID) + "/" + key
+}
+
+// RequestHash returns the hash of req, stored along with what is created with an idempotency key so that the key
+// can't be reused for a different request.
+func RequestHash(req proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal request: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// OutgoingContext returns a context carrying key in the outgoing grpc metadata.
+func OutgoingContext(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, key)
+}
+
+// FromIncomingContext returns the idempotency key carried in the incoming grpc metadata, if any.
+func FromIncomingContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,25 @@
+package kafka
+
+import (
+	"sort"
+
+	"github.com/Shopify/sarama"
+)
+
+// ProducerHeaders returns headers as record headers sorted by key.
+func ProducerHeaders(headers map[string]string) []sarama.RecordHeader {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	saramaHeaders := make([]sarama.RecordHeader, 0, len(keys))
+	for _, k := range keys {
+		saramaHeaders = append(saramaHeaders, sarama.RecordHeader{
+			Key:   []byte(k),
+			Value: []byte(headers[k]),
+		})
+	}
+	return saramaHeaders
+}
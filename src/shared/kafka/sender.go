@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/Shopify/sarama"
 )
@@ -9,6 +10,9 @@ import (
 // Sender describe the send contract.
 type Sender interface {
 	SendMessage(message *sarama.ProducerMessage) error
+	// SendMessages sends messages in a single batch.
+	// When some of them could not be sent, the returned error is a sarama.ProducerErrors listing them.
+	SendMessages(messages []*sarama.ProducerMessage) error
 }
 
 // SyncProducer represents a sync producer.
@@ -30,3 +34,30 @@ func (sp SyncProducer) SendMessage(message *sarama.ProducerMessage) error {
 	_, _, err := sp.producer.SendMessage(message)
 	return err
 }
+
+// SendMessages wraps the send messages method.
+func (sp SyncProducer) SendMessages(messages []*sarama.ProducerMessage) error {
+	return sp.producer.SendMessages(messages)
+}
+
+// EnableIdempotence makes the producers created with cfg idempotent: brokers discard the duplicates that
+// producer retries would write otherwise, and keep the records of a partition in order.
+// It requires kafka 0.11 or later, acks from all the in-sync replicas and a single in-flight request.
+func EnableIdempotence(cfg *sarama.Config) {
+	cfg.Producer.Idempotent = true
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Net.MaxOpenRequests = 1
+
+	if cfg.Producer.Retry.Max < 1 {
+		cfg.Producer.Retry.Max = 1
+	}
+	if !cfg.Version.IsAtLeast(sarama.V0_11_0_0) {
+		cfg.Version = sarama.V0_11_0_0
+	}
+}
+
+// ExactlyOnceFromEnv tells whether KAFKA_CONSUMER_COMMIT_MODE selects the exactly-once mode, in which every
+// service producing todo records must produce them idempotently.
+func ExactlyOnceFromEnv() bool {
+	return os.Getenv("KAFKA_CONSUMER_COMMIT_MODE") == "exactly-once"
+}
@@ -0,0 +1,27 @@
+package operation
+
+import "time"
+
+// Status describes the status of an operation.
+type Status string
+
+const (
+	// StatusPending is used while the todo has not been persisted yet.
+	StatusPending Status = "pending"
+	// StatusSucceeded is used once the todo has been persisted.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed is used when the todo could not be persisted.
+	StatusFailed Status = "failed"
+)
+
+// Operation describes the asynchronous creation of a todo.
+type Operation struct {
+	ID        string    `json:"id"`
+	TodoID    string    `json:"todo_id"`
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	// RequestHash is the hash of the request that created the operation with an idempotency key, if any.
+	RequestHash string `json:"-"`
+}
@@ -0,0 +1,69 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
+)
+
+// MaxBatchSize is the maximum number of todos that can be created in a single batch.
+const MaxBatchSize = 100
+
+const (
+	// maxEscapedRuneLen is the length of a character escaped in JSON as a \uXXXX surrogate pair, the longest form.
+	maxEscapedRuneLen = 12
+	// bodyOverhead leaves room in a body for the keys, the due date, the priority and the other tags.
+	bodyOverhead = 16 << 10
+)
+
+var (
+	// MaxBodySize is the maximum size in bytes of the JSON body of a request creating a todo. It fits every string
+	// field of todov1.CreateRequest at its max_len, each character escaped, so valid todos always fit.
+	MaxBodySize = int64(validation.MaxLen(&todov1.CreateRequest{})*maxEscapedRuneLen + bodyOverhead)
+	// MaxBatchBodySize is the maximum size in bytes of the JSON body of a request creating a batch of todos.
+	MaxBatchBodySize = MaxBatchSize * MaxBodySize
+)
+
+// Batch describes a batch of todos to be created.
+type Batch struct {
+	Todos []Todo `json:"todos"`
+}
+
+// BatchResult describes the outcome of creating a batch of todos, in request order.
+type BatchResult struct {
+	Results []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult describes the outcome of creating a todo of a batch.
+// ID and OperationID are set when the todo has been accepted, Error when it has been rejected.
+type BatchItemResult struct {
+	ID          string          `json:"id,omitempty"`
+	OperationID string          `json:"operation_id,omitempty"`
+	Error       *BatchItemError `json:"error,omitempty"`
+}
+
+// BatchItemError describes why a todo of a batch has been rejected.
+type BatchItemError struct {
+	Status     int                         `json:"status"`
+	Code       string                      `json:"code"`
+	Message    string                      `json:"message"`
+	Violations []validation.FieldViolation `json:"violations,omitempty"`
+}
+
+// Validate checks that the batch holds between one and MaxBatchSize todos, none of them completed.
+func (b Batch) Validate() error {
+	switch {
+	case len(b.Todos) == 0:
+		return errors.New("batch must contain at least one todo")
+	case len(b.Todos) > MaxBatchSize:
+		return fmt.Errorf("batch cannot contain more than %d todos", MaxBatchSize)
+	}
+	for i, t := range b.Todos {
+		if t.Completed {
+			return fmt.Errorf("todos[%d].%s %s", i, completedOnCreate.Field, completedOnCreate.Description)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package todo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+// ChangeChannel is the postgres channel on which committed todo changes are notified.
+const ChangeChannel = "todo_changes"
+
+// ChangeType describes the change a todo went through.
+type ChangeType string
+
+const (
+	// ChangeCreated is notified when a todo is created.
+	ChangeCreated ChangeType = "created"
+	// ChangeUpdated is notified when a todo is updated.
+	ChangeUpdated ChangeType = "updated"
+	// ChangeDeleted is notified when a todo is deleted.
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// Change is the payload notified on ChangeChannel.
+// It only carries the todo id as notification payloads are limited in size.
+type Change struct {
+	Type ChangeType `json:"type"`
+	ID   string     `json:"id"`
+	// Trace is the text map of the span that originated the change.
+	Trace map[string]string `json:"trace,omitempty"`
+}
+
+// ChangeTrace returns the json encoded text map of the span in ctx, to be notified along with a change.
+func ChangeTrace(ctx context.Context) (string, error) {
+	b, err := json.Marshal(tracing.TextMapFromContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("could not serialise trace: %w", err)
+	}
+	return string(b), nil
+}
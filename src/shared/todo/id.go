@@ -0,0 +1,38 @@
+package todo
+
+import (
+	"crypto/sha256"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// NewID returns a new todo id.
+// Ids are ULIDs, so they are unique and lexicographically sortable by creation time.
+func NewID() string {
+	return ulid.Make().String()
+}
+
+// IDFromKey returns the id of the todo created with the given idempotency key, so that every attempt to create
+// it refers to the same todo. These ids are shaped like ULIDs but don't sort by creation time.
+func IDFromKey(key string) string {
+	return derivedID("todo", key)
+}
+
+// OperationIDFromKey returns the id of the operation creating the todo with the given idempotency key.
+func OperationIDFromKey(key string) string {
+	return derivedID("operation", key)
+}
+
+// IDFromRecord returns the id of the todo carried by a record that doesn't carry one, derived from where the record
+// was originally produced (e.g. "todos/0/42"), so that every delivery of the record refers to the same todo.
+func IDFromRecord(origin string) string {
+	return derivedID("record", origin)
+}
+
+func derivedID(kind, key string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + key))
+
+	var id ulid.ULID
+	copy(id[:], sum[:])
+	return id.String()
+}
@@ -0,0 +1,28 @@
+package todo
+
+import "fmt"
+
+// Priority describes how urgent a todo is.
+type Priority string
+
+const (
+	// PriorityUnspecified is used when no priority has been set.
+	PriorityUnspecified Priority = ""
+	// PriorityLow is used for todos that can wait.
+	PriorityLow Priority = "low"
+	// PriorityMedium is used for todos that should be done soon.
+	PriorityMedium Priority = "medium"
+	// PriorityHigh is used for urgent todos.
+	PriorityHigh Priority = "high"
+)
+
+// UnmarshalText rejects unknown priorities.
+func (p *Priority) UnmarshalText(text []byte) error {
+	switch v := Priority(text); v {
+	case PriorityUnspecified, PriorityLow, PriorityMedium, PriorityHigh:
+		*p = v
+		return nil
+	default:
+		return fmt.Errorf("unknown priority %q", v)
+	}
+}
@@ -0,0 +1,117 @@
+package todo
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
+)
+
+// completedOnCreate is the violation of a todo created already completed, which the create request can't carry.
+var completedOnCreate = validation.FieldViolation{Field: "completed", Description: "must be false when creating a todo"}
+
+// ToProto converts t to its protobuf representation.
+func ToProto(t *Todo) *todov1.Todo {
+	return &todov1.Todo{
+		Id:          t.ID,
+		Message:     t.Message,
+		Title:       t.Title,
+		Description: t.Description,
+		DueAt:       TimeToProto(t.DueAt),
+		Priority:    PriorityToProto(t.Priority),
+		Tags:        t.Tags,
+		Completed:   t.Completed,
+		CreatedAt:   timestamppb.New(t.CreatedAt),
+		UpdatedAt:   timestamppb.New(t.UpdatedAt),
+	}
+}
+
+// FromProto converts a protobuf todo to a Todo.
+func FromProto(t *todov1.Todo) *Todo {
+	return &Todo{
+		ID:          t.Id,
+		Message:     t.Message,
+		Title:       t.Title,
+		Description: t.Description,
+		DueAt:       TimeFromProto(t.DueAt),
+		Priority:    PriorityFromProto(t.Priority),
+		Tags:        t.Tags,
+		Completed:   t.Completed,
+		CreatedAt:   t.CreatedAt.AsTime(),
+		UpdatedAt:   t.UpdatedAt.AsTime(),
+	}
+}
+
+// ToCreateRequest returns the request creating t.
+func ToCreateRequest(t *Todo) *todov1.CreateRequest {
+	return &todov1.CreateRequest{
+		Message:     t.Message,
+		Title:       t.Title,
+		Description: t.Description,
+		DueAt:       TimeToProto(t.DueAt),
+		Priority:    PriorityToProto(t.Priority),
+		Tags:        t.Tags,
+	}
+}
+
+// ValidateCreate checks t against the rules of the request creating it.
+// Todos are always created pending, so t cannot be completed either.
+func ValidateCreate(t *Todo) error {
+	err := validation.Validate(ToCreateRequest(t))
+	if !t.Completed {
+		return err
+	}
+
+	var validationErr validation.Error
+	errors.As(err, &validationErr)
+	validationErr.Violations = append(validationErr.Violations, completedOnCreate)
+
+	return validationErr
+}
+
+// PriorityToProto converts p to its protobuf representation.
+func PriorityToProto(p Priority) todov1.Priority {
+	switch p {
+	case PriorityLow:
+		return todov1.Priority_PRIORITY_LOW
+	case PriorityMedium:
+		return todov1.Priority_PRIORITY_MEDIUM
+	case PriorityHigh:
+		return todov1.Priority_PRIORITY_HIGH
+	default:
+		return todov1.Priority_PRIORITY_UNSPECIFIED
+	}
+}
+
+// PriorityFromProto converts a protobuf priority to a Priority.
+func PriorityFromProto(p todov1.Priority) Priority {
+	switch p {
+	case todov1.Priority_PRIORITY_LOW:
+		return PriorityLow
+	case todov1.Priority_PRIORITY_MEDIUM:
+		return PriorityMedium
+	case todov1.Priority_PRIORITY_HIGH:
+		return PriorityHigh
+	default:
+		return PriorityUnspecified
+	}
+}
+
+// TimeToProto returns t as a timestamp, or nil when t is the zero time.
+func TimeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// TimeFromProto returns the time held by ts, or the zero time when ts is not set.
+func TimeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
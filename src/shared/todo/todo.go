@@ -1,6 +1,35 @@
 package todo
 
+import "time"
+
 // Todo describes a todo.
 type Todo struct {
-	Message string `json:"message"`
+	ID          string    `json:"id,omitempty"`
+	Message     string    `json:"message"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	DueAt       time.Time `json:"due_at,omitzero"`
+	Priority    Priority  `json:"priority,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Completed   bool      `json:"completed"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	// IdempotencyKey is the key supplied by the client when creating the todo.
+	IdempotencyKey string `json:"-"`
+	// TenantID is the id of the tenant the todo has been created on behalf of, carried by the trace baggage.
+	TenantID string `json:"-"`
+	// UserID is the id of the user that created the todo, carried by the trace baggage.
+	UserID string `json:"-"`
+}
+
+// Page describes a page of todos.
+type Page struct {
+	Todos         []*Todo `json:"todos"`
+	NextPageToken string  `json:"next_page_token,omitempty"`
+}
+
+// Created describes a todo whose creation has been accepted.
+type Created struct {
+	Todo
+	OperationID string `json:"operation_id,omitempty"`
 }
@@ -0,0 +1,141 @@
+package todo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
+)
+
+func TestTodo_UnmarshalJSON(t *testing.T) {
+	t.Run("it should return an error because the priority is unknown", func(t *testing.T) {
+		var got todo.Todo
+		require.Error(t, json.Unmarshal([]byte(`{"message":"hello","priority":"urgent"}`), &got))
+	})
+	t.Run("it should deserialise a todo carrying only a message", func(t *testing.T) {
+		var got todo.Todo
+		require.NoError(t, json.Unmarshal([]byte(`{"message":"hello"}`), &got))
+		assert.Equal(t, todo.Todo{Message: "hello"}, got)
+	})
+	t.Run("it should deserialise a todo with all its details", func(t *testing.T) {
+		var got todo.Todo
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"message": "hello",
+			"title": "someTitle",
+			"description": "someDescription",
+			"due_at": "2021-01-02T00:00:00Z",
+			"priority": "high",
+			"tags": ["home"],
+			"completed": true
+		}`), &got))
+		assert.Equal(t, todo.Todo{
+			Message:     "hello",
+			Title:       "someTitle",
+			Description: "someDescription",
+			DueAt:       time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+			Priority:    todo.PriorityHigh,
+			Tags:        []string{"home"},
+			Completed:   true,
+		}, got)
+	})
+}
+
+func TestFromProto(t *testing.T) {
+	t.Run("it should convert a todo back and forth", func(t *testing.T) {
+		var (
+			now  = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			want = &todo.Todo{
+				ID:          "someID",
+				Message:     "hello",
+				Title:       "someTitle",
+				Description: "someDescription",
+				DueAt:       now.Add(24 * time.Hour),
+				Priority:    todo.PriorityMedium,
+				Tags:        []string{"home"},
+				Completed:   true,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+		)
+
+		assert.Equal(t, want, todo.FromProto(todo.ToProto(want)))
+	})
+	t.Run("it should leave the due date unset", func(t *testing.T) {
+		assert.Nil(t, todo.ToProto(&todo.Todo{}).DueAt)
+		assert.True(t, todo.FromProto(todo.ToProto(&todo.Todo{})).DueAt.IsZero())
+	})
+}
+
+func TestChangeTrace(t *testing.T) {
+	t.Run("it should return an empty text map because the context carries no span", func(t *testing.T) {
+		got, err := todo.ChangeTrace(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "{}", got)
+	})
+}
+
+func TestBatch_Validate(t *testing.T) {
+	t.Run("it should return an error because the batch is empty", func(t *testing.T) {
+		assert.Error(t, todo.Batch{}.Validate())
+	})
+	t.Run("it should return an error because the batch is too large", func(t *testing.T) {
+		assert.Error(t, todo.Batch{Todos: make([]todo.Todo, todo.MaxBatchSize+1)}.Validate())
+	})
+	t.Run("it should accept a batch of the maximum size", func(t *testing.T) {
+		assert.NoError(t, todo.Batch{Todos: make([]todo.Todo, todo.MaxBatchSize)}.Validate())
+	})
+	t.Run("it should return an error because a todo is completed", func(t *testing.T) {
+		assert.EqualError(
+			t,
+			todo.Batch{Todos: []todo.Todo{{Message: "hello"}, {Message: "world", Completed: true}}}.Validate(),
+			"todos[1].completed must be false when creating a todo",
+		)
+	})
+}
+
+func TestValidateCreate(t *testing.T) {
+	t.Run("it should accept a pending todo", func(t *testing.T) {
+		assert.NoError(t, todo.ValidateCreate(&todo.Todo{Message: "hello"}))
+	})
+	t.Run("it should report every violation of a completed todo", func(t *testing.T) {
+		err := todo.ValidateCreate(&todo.Todo{Completed: true})
+		require.Error(t, err)
+
+		var e validation.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, []validation.FieldViolation{
+			{Field: "message", Description: "must be at least 1 characters long"},
+			{Field: "completed", Description: "must be false when creating a todo"},
+		}, e.Violations)
+	})
+}
+
+func TestIDFromKey(t *testing.T) {
+	t.Run("it should derive the same ids from the same key", func(t *testing.T) {
+		assert.Equal(t, todo.IDFromKey("someKey"), todo.IDFromKey("someKey"))
+		assert.Equal(t, todo.OperationIDFromKey("someKey"), todo.OperationIDFromKey("someKey"))
+		assert.Len(t, todo.IDFromKey("someKey"), len(todo.NewID()))
+	})
+	t.Run("it should derive different ids from different keys", func(t *testing.T) {
+		assert.NotEqual(t, todo.IDFromKey("someKey"), todo.IDFromKey("otherKey"))
+		assert.NotEqual(t, todo.IDFromKey("someKey"), todo.OperationIDFromKey("someKey"))
+	})
+}
+
+func TestIDFromRecord(t *testing.T) {
+	t.Run("it should derive the same id from the same origin", func(t *testing.T) {
+		assert.Equal(t, todo.IDFromRecord("todos/0/42"), todo.IDFromRecord("todos/0/42"))
+		assert.Len(t, todo.IDFromRecord("todos/0/42"), len(todo.NewID()))
+	})
+	t.Run("it should derive different ids from different origins", func(t *testing.T) {
+		assert.NotEqual(t, todo.IDFromRecord("todos/0/42"), todo.IDFromRecord("todos/0/43"))
+		assert.NotEqual(t, todo.IDFromRecord("someKey"), todo.IDFromKey("someKey"))
+	})
+}
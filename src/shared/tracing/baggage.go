@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	// BaggageTenantID is the baggage item carrying the id of the tenant a request is made on behalf of.
+	BaggageTenantID = "tenant-id"
+	// BaggageUserID is the baggage item carrying the id of the user making a request.
+	BaggageUserID = "user-id"
+
+	baggageTagPrefix = "baggage."
+)
+
+// DefaultBaggageHeaders maps the http headers lifted into span baggage when none are configured to their baggage items.
+var DefaultBaggageHeaders = map[string]string{
+	"X-Tenant-Id": BaggageTenantID,
+	"X-User-Id":   BaggageUserID,
+}
+
+// ParseBaggageHeaders returns the http headers mapped to baggage items by a comma separated string of
+// header=item pairs, e.g. "X-Tenant-Id=tenant-id,X-User-Id=user-id".
+// It returns DefaultBaggageHeaders when s is empty.
+func ParseBaggageHeaders(s string) (map[string]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultBaggageHeaders, nil
+	}
+
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		header, item, ok := strings.Cut(pair, "=")
+		header, item = strings.TrimSpace(header), strings.ToLower(strings.TrimSpace(item))
+		if !ok || header == "" || item == "" {
+			return nil, fmt.Errorf("invalid baggage header %q, expected header=item", pair)
+		}
+
+		headers[http.CanonicalHeaderKey(header)] = item
+	}
+
+	return headers, nil
+}
+
+// SetBaggageFromHeaders sets the baggage items of span to the values of the http headers mapped to them.
+// Headers missing from header are skipped.
+func SetBaggageFromHeaders(span opentracing.Span, header http.Header, headers map[string]string) {
+	for name, item := range headers {
+		if v := header.Get(name); v != "" {
+			span.SetBaggageItem(item, v)
+		}
+	}
+}
+
+// SetBaggage sets the baggage items of span to the ones carried by sc.
+// It's used to keep the baggage of a span context span doesn't descend from, e.g. the one it follows from.
+func SetBaggage(span opentracing.Span, sc opentracing.SpanContext) {
+	if sc == nil {
+		return
+	}
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		span.SetBaggageItem(k, v)
+		return true
+	})
+}
+
+// TagBaggage tags span with its baggage items, prefixed by "baggage.", for them to show up on the span.
+func TagBaggage(span opentracing.Span) {
+	span.Context().ForeachBaggageItem(func(k, v string) bool {
+		span.SetTag(baggageTagPrefix+k, v)
+		return true
+	})
+}
+
+// BaggageItem returns the baggage item carried by the span in ctx, or an empty string when there's none.
+func BaggageItem(ctx context.Context, key string) string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	return span.BaggageItem(key)
+}
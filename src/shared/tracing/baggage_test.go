@@ -0,0 +1,92 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+)
+
+func TestParseBaggageHeaders(t *testing.T) {
+	t.Run("it should return the default headers because none are configured", func(t *testing.T) {
+		headers, err := tracing.ParseBaggageHeaders(" ")
+		require.NoError(t, err)
+		assert.Equal(t, tracing.DefaultBaggageHeaders, headers)
+	})
+	t.Run("it should return the configured headers", func(t *testing.T) {
+		headers, err := tracing.ParseBaggageHeaders("x-tenant = Tenant-ID, X-Request-Origin=origin,")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"X-Tenant": "tenant-id", "X-Request-Origin": "origin"}, headers)
+	})
+	t.Run("it should return an error because a header is not mapped to an item", func(t *testing.T) {
+		for _, s := range []string{"X-Tenant", "X-Tenant=", "=tenant-id"} {
+			_, err := tracing.ParseBaggageHeaders(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
+
+func TestSetBaggageFromHeaders(t *testing.T) {
+	t.Run("it should lift the mapped headers into the span baggage", func(t *testing.T) {
+		span := recorder.New().StartSpan("someOperation")
+
+		header := http.Header{}
+		header.Set("X-Tenant-Id", "someTenant")
+		header.Set("X-Other", "someValue")
+
+		tracing.SetBaggageFromHeaders(span, header, tracing.DefaultBaggageHeaders)
+
+		assert.Equal(t, "someTenant", span.BaggageItem(tracing.BaggageTenantID))
+		assert.Empty(t, span.BaggageItem(tracing.BaggageUserID))
+	})
+}
+
+func TestSetBaggage(t *testing.T) {
+	t.Run("it should copy the baggage of a span context the span doesn't descend from", func(t *testing.T) {
+		tracer := recorder.New()
+
+		origin := tracer.StartSpan("origin")
+		origin.SetBaggageItem(tracing.BaggageUserID, "someUser")
+
+		span := tracer.StartSpan("span")
+		tracing.SetBaggage(span, origin.Context())
+		tracing.SetBaggage(span, nil)
+
+		assert.Equal(t, "someUser", span.BaggageItem(tracing.BaggageUserID))
+	})
+}
+
+func TestTagBaggage(t *testing.T) {
+	t.Run("it should tag the span with its baggage", func(t *testing.T) {
+		tracer := recorder.New()
+
+		span := tracer.StartSpan("span")
+		span.SetBaggageItem(tracing.BaggageTenantID, "someTenant")
+		tracing.TagBaggage(span)
+		span.Finish()
+
+		spans := tracer.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, map[string]interface{}{"baggage.tenant-id": "someTenant"}, spans[0].Tags)
+	})
+}
+
+func TestBaggageItem(t *testing.T) {
+	t.Run("it should return the baggage item of the span in the context", func(t *testing.T) {
+		span := recorder.New().StartSpan("span")
+		span.SetBaggageItem(tracing.BaggageTenantID, "someTenant")
+
+		ctx := opentracing.ContextWithSpan(context.Background(), span)
+		assert.Equal(t, "someTenant", tracing.BaggageItem(ctx, tracing.BaggageTenantID))
+		assert.Empty(t, tracing.BaggageItem(ctx, tracing.BaggageUserID))
+	})
+	t.Run("it should return an empty item because the context carries no span", func(t *testing.T) {
+		assert.Empty(t, tracing.BaggageItem(context.Background(), tracing.BaggageTenantID))
+	})
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	grpcStatusCodeKey = "grpc.status_code"
+	httpStatusCodeKey = "http.status_code"
+)
+
+// SetError marks span as failed because of err, so that it stands out in the tracing UI,
+// and logs err as an error event along with the stack that reported it and fields.
+// The grpc status code of err is logged as well when err carries one.
+// It does nothing when span or err are nil.
+func SetError(span opentracing.Span, err error, fields ...log.Field) {
+	if span == nil || err == nil {
+		return
+	}
+
+	ext.Error.Set(span, true)
+
+	logFields := []log.Field{
+		log.String("event", "error"),
+		log.String("message", err.Error()),
+		log.String("error.kind", fmt.Sprintf("%T", err)),
+		log.String("stack", string(debug.Stack())),
+	}
+	if st, ok := status.FromError(err); ok {
+		logFields = append(logFields, GRPCStatusCode(st.Code()))
+	}
+
+	span.LogFields(append(logFields, fields...)...)
+}
+
+// Fail marks the span in ctx, if any, as failed because of err as SetError does and returns err,
+// so that errors are traced as they're returned.
+func Fail(ctx context.Context, err error, fields ...log.Field) error {
+	SetError(opentracing.SpanFromContext(ctx), err, fields...)
+	return err
+}
+
+// SetHTTPError tags span with the http status code of a failed response and marks it as failed because of err.
+func SetHTTPError(span opentracing.Span, statusCode int, err error, fields ...log.Field) {
+	if span == nil {
+		return
+	}
+
+	ext.HTTPStatusCode.Set(span, uint16(statusCode))
+	SetError(span, err, append([]log.Field{HTTPStatusCode(statusCode)}, fields...)...)
+}
+
+// GRPCStatusCode returns the log field of the grpc status code of a failure.
+func GRPCStatusCode(code codes.Code) log.Field {
+	return log.String(grpcStatusCodeKey, code.String())
+}
+
+// HTTPStatusCode returns the log field of the http status code of a failure.
+func HTTPStatusCode(statusCode int) log.Field {
+	return log.Int(httpStatusCodeKey, statusCode)
+}
@@ -0,0 +1,108 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+func TestSetError(t *testing.T) {
+	t.Run("it should mark the span as failed and log the error", func(t *testing.T) {
+		tracer := recorder.New()
+
+		span := tracer.StartSpan("someOperation")
+		tracing.SetError(span, errors.New("someErr"), log.String("todo.id", "someID"))
+		span.Finish()
+
+		finished := traceassert.Span(t, tracer.FinishedSpans(), "someOperation")
+		traceassert.HasTag(t, finished, string(ext.Error), true)
+		traceassert.HasLogField(t, finished, "event", "error")
+		traceassert.HasLogField(t, finished, "message", "someErr")
+		traceassert.HasLogField(t, finished, "error.kind", "*errors.errorString")
+		traceassert.HasLogField(t, finished, "todo.id", "someID")
+		require.Len(t, finished.Logs, 1)
+
+		var stack string
+		for _, field := range finished.Logs[0].Fields {
+			if field.Key() == "stack" {
+				stack = field.Value().(string)
+			}
+		}
+		assert.Contains(t, stack, "TestSetError")
+	})
+	t.Run("it should log the grpc status code of the error", func(t *testing.T) {
+		tracer := recorder.New()
+
+		span := tracer.StartSpan("someOperation")
+		tracing.SetError(span, status.Error(codes.NotFound, "todo not found"))
+		span.Finish()
+
+		finished := traceassert.Span(t, tracer.FinishedSpans(), "someOperation")
+		traceassert.HasLogField(t, finished, "message", "rpc error: code = NotFound desc = todo not found")
+		traceassert.HasLogField(t, finished, "grpc.status_code", "NotFound")
+	})
+	t.Run("it should leave the span untouched because there's no error", func(t *testing.T) {
+		tracer := recorder.New()
+
+		span := tracer.StartSpan("someOperation")
+		tracing.SetError(span, nil)
+		span.Finish()
+
+		finished := traceassert.Span(t, tracer.FinishedSpans(), "someOperation")
+		traceassert.NoTag(t, finished, string(ext.Error))
+		assert.Empty(t, finished.Logs)
+	})
+	t.Run("it should do nothing because there's no span", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			tracing.SetError(nil, errors.New("someErr"))
+		})
+	})
+}
+
+func TestFail(t *testing.T) {
+	t.Run("it should mark the span in the context as failed and return the error", func(t *testing.T) {
+		var (
+			tracer  = recorder.New()
+			span    = tracer.StartSpan("someOperation")
+			someErr = errors.New("someErr")
+		)
+
+		err := tracing.Fail(opentracing.ContextWithSpan(context.Background(), span), someErr)
+		span.Finish()
+
+		assert.Equal(t, someErr, err)
+		traceassert.HasTag(t, traceassert.Span(t, tracer.FinishedSpans(), "someOperation"), string(ext.Error), true)
+	})
+	t.Run("it should return the error because there's no span in the context", func(t *testing.T) {
+		someErr := errors.New("someErr")
+		assert.Equal(t, someErr, tracing.Fail(context.Background(), someErr))
+	})
+}
+
+func TestSetHTTPError(t *testing.T) {
+	t.Run("it should tag the span with the status code and mark it as failed", func(t *testing.T) {
+		tracer := recorder.New()
+
+		span := tracer.StartSpan("someOperation")
+		tracing.SetHTTPError(span, http.StatusServiceUnavailable, errors.New("someErr"))
+		span.Finish()
+
+		finished := traceassert.Span(t, tracer.FinishedSpans(), "someOperation")
+		traceassert.HasTag(t, finished, string(ext.HTTPStatusCode), uint16(http.StatusServiceUnavailable))
+		traceassert.HasTag(t, finished, string(ext.Error), true)
+		traceassert.HasLogField(t, finished, "http.status_code", http.StatusServiceUnavailable)
+	})
+}
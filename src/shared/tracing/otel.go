@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// OTLPProtocolGRPC exports spans to the collector over gRPC.
+	OTLPProtocolGRPC = "grpc"
+	// OTLPProtocolHTTP exports spans to the collector over HTTP with protobuf payloads.
+	OTLPProtocolHTTP = "http/protobuf"
+
+	// otlpProtocolEnv is the standard environment variable selecting the OTLP protocol.
+	otlpProtocolEnv = "OTEL_EXPORTER_OTLP_PROTOCOL"
+
+	otelShutdownTimeout = 10 * time.Second
+)
+
+// OTelTracer wraps the opentracing bridge of an OpenTelemetry tracer provider exporting spans through OTLP.
+type OTelTracer struct {
+	provider     *sdktrace.TracerProvider
+	globalTracer opentracing.Tracer
+}
+
+// NewOTelTracer returns a tracer exporting spans to an OpenTelemetry collector and propagating them
+// in the formats of cfg along with the W3C baggage.
+// The OTLP protocol is read from OTEL_EXPORTER_OTLP_PROTOCOL and defaults to grpc, while the
+// endpoint, headers and transport security are read by the exporter from the standard OTEL_EXPORTER_OTLP_* variables.
+func NewOTelTracer(ctx context.Context, cfg Config) (Tracer, error) {
+	if cfg.ServiceName == "" {
+		return nil, InvalidTracerParameterError{parameter: "ServiceName", reason: "cannot be empty"}
+	}
+
+	propagator, err := newOTelPropagator(cfg.propagation())
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := newOTLPExporter(ctx, os.Getenv(otlpProtocolEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	bridgeTracer, wrapperProvider := otelbridge.NewTracerPair(provider.Tracer(cfg.ServiceName))
+	bridgeTracer.SetTextMapPropagator(propagator)
+
+	otel.SetTracerProvider(wrapperProvider)
+	opentracing.SetGlobalTracer(bridgeTracer)
+
+	return OTelTracer{
+		provider:     provider,
+		globalTracer: bridgeTracer,
+	}, nil
+}
+
+func newOTLPExporter(ctx context.Context, protocol string) (sdktrace.SpanExporter, error) {
+	switch protocol {
+	case "", OTLPProtocolGRPC:
+		exporter, err := otlptracegrpc.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not create otlp grpc exporter: %w", err)
+		}
+		return exporter, nil
+	case OTLPProtocolHTTP:
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not create otlp http exporter: %w", err)
+		}
+		return exporter, nil
+	default:
+		return nil, InvalidTracerParameterError{
+			parameter: otlpProtocolEnv,
+			reason:    fmt.Sprintf("unsupported protocol %q", protocol),
+		}
+	}
+}
+
+// StartSpan wraps StartSpan.
+func (ot OTelTracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	return ot.globalTracer.StartSpan(operationName, opts...)
+}
+
+// Inject wraps Inject.
+func (ot OTelTracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	return ot.globalTracer.Inject(sm, format, carrier)
+}
+
+// Extract wraps Extract.
+func (ot OTelTracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	return ot.globalTracer.Extract(format, carrier)
+}
+
+// Close flushes the pending spans and shuts the exporter down.
+func (ot OTelTracer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+	defer cancel()
+
+	return ot.provider.Shutdown(ctx)
+}
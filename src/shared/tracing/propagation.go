@@ -0,0 +1,308 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+	"github.com/uber/jaeger-client-go/zipkin"
+	b3prop "go.opentelemetry.io/contrib/propagators/b3"
+	jaegerprop "go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// PropagationUber carries span contexts in the uber-trace-id header.
+	PropagationUber = "uber"
+	// PropagationTraceContext carries span contexts in the W3C traceparent and tracestate headers.
+	PropagationTraceContext = "tracecontext"
+	// PropagationB3 carries span contexts in the zipkin X-B3-* headers.
+	PropagationB3 = "b3"
+
+	traceParentHeader  = "traceparent"
+	baggageHeader      = "baggage"
+	traceParentVersion = "00"
+	traceFlagsSampled  = 0x01
+)
+
+// DefaultPropagation lists the formats injected when none are configured, from the most to the least preferred on extraction.
+var DefaultPropagation = []string{PropagationUber, PropagationTraceContext, PropagationB3}
+
+// ParsePropagation returns the formats listed in a comma separated string, e.g. "tracecontext,b3".
+func ParsePropagation(s string) []string {
+	var formats []string
+	for _, format := range strings.Split(s, ",") {
+		if format = strings.ToLower(strings.TrimSpace(format)); format != "" {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// JaegerPropagator injects jaeger span contexts into carriers and extracts them from carriers.
+type JaegerPropagator interface {
+	jaeger.Injector
+	jaeger.Extractor
+}
+
+// CompositePropagator injects span contexts in every format it's made of and extracts them
+// from the first format present in the carrier. Whatever the formats, their baggage is carried
+// in the W3C baggage header too, as only the uber format has room for it.
+type CompositePropagator struct {
+	propagators []JaegerPropagator
+}
+
+// NewCompositePropagator returns a new CompositePropagator. The propagators are tried in order on extraction.
+func NewCompositePropagator(propagators ...JaegerPropagator) (CompositePropagator, error) {
+	if len(propagators) == 0 {
+		return CompositePropagator{}, InvalidTracerParameterError{parameter: "propagators", reason: "cannot be empty"}
+	}
+	for _, p := range propagators {
+		if p == nil {
+			return CompositePropagator{}, InvalidTracerParameterError{parameter: "propagators", reason: "cannot contain nil"}
+		}
+	}
+	return CompositePropagator{propagators: propagators}, nil
+}
+
+// Inject injects sc in every format, and its baggage in the baggage header.
+func (c CompositePropagator) Inject(sc jaeger.SpanContext, carrier interface{}) error {
+	for _, p := range c.propagators {
+		if err := p.Inject(sc, carrier); err != nil {
+			return err
+		}
+	}
+	return injectBaggage(sc, carrier)
+}
+
+// Extract returns the span context carried in the first format present in carrier, along with the baggage
+// carried by the baggage header that the format doesn't carry already.
+// A corrupted format is skipped in favour of the next ones and its error is returned only when none is valid.
+func (c CompositePropagator) Extract(carrier interface{}) (jaeger.SpanContext, error) {
+	var extractErr error
+	for _, p := range c.propagators {
+		sc, err := p.Extract(carrier)
+		switch {
+		case err == nil && sc.IsValid():
+			return extractBaggage(sc, carrier)
+		case err == nil, errors.Is(err, opentracing.ErrSpanContextNotFound):
+			continue
+		case extractErr == nil:
+			extractErr = err
+		}
+	}
+	if extractErr != nil {
+		return jaeger.SpanContext{}, extractErr
+	}
+	return jaeger.SpanContext{}, opentracing.ErrSpanContextNotFound
+}
+
+// injectBaggage sets the W3C baggage header of carrier to the baggage of sc, if any.
+// Keys and values are percent-encoded, so that they never clash with the delimiters of the header.
+func injectBaggage(sc jaeger.SpanContext, carrier interface{}) error {
+	var members []string
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		members = append(members, url.PathEscape(k)+"="+url.PathEscape(v))
+		return true
+	})
+	if len(members) == 0 {
+		return nil
+	}
+
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	sort.Strings(members)
+	writer.Set(baggageHeader, strings.Join(members, ","))
+
+	return nil
+}
+
+// extractBaggage returns sc along with the items of the W3C baggage header of carrier it doesn't carry already.
+// Malformed members and the properties of the members are ignored.
+func extractBaggage(sc jaeger.SpanContext, carrier interface{}) (jaeger.SpanContext, error) {
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return jaeger.SpanContext{}, opentracing.ErrInvalidCarrier
+	}
+
+	var baggage []string
+	if err := reader.ForeachKey(func(key, value string) error {
+		if strings.EqualFold(key, baggageHeader) {
+			baggage = append(baggage, value)
+		}
+		return nil
+	}); err != nil {
+		return jaeger.SpanContext{}, err
+	}
+
+	carried := make(map[string]bool)
+	sc.ForeachBaggageItem(func(k, _ string) bool {
+		carried[k] = true
+		return true
+	})
+
+	for _, member := range strings.Split(strings.Join(baggage, ","), ",") {
+		member = strings.SplitN(member, ";", 2)[0]
+
+		kv := strings.SplitN(member, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		k, kErr := url.PathUnescape(strings.TrimSpace(kv[0]))
+		v, vErr := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if kErr != nil || vErr != nil || k == "" || carried[k] {
+			continue
+		}
+
+		sc = sc.WithBaggageItem(k, v)
+		carried[k] = true
+	}
+
+	return sc, nil
+}
+
+// TraceContextPropagator carries jaeger span contexts in the W3C traceparent header.
+// The jaeger span context has no room for the vendor specific tracestate, which is not forwarded.
+type TraceContextPropagator struct{}
+
+// Inject sets the traceparent header of carrier.
+func (TraceContextPropagator) Inject(sc jaeger.SpanContext, carrier interface{}) error {
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	var flags byte
+	if sc.IsSampled() {
+		flags |= traceFlagsSampled
+	}
+
+	traceID := sc.TraceID()
+	writer.Set(traceParentHeader, fmt.Sprintf(
+		"%s-%016x%016x-%016x-%02x",
+		traceParentVersion,
+		traceID.High,
+		traceID.Low,
+		uint64(sc.SpanID()),
+		flags,
+	))
+
+	return nil
+}
+
+// Extract returns the span context carried by the traceparent header of carrier.
+func (TraceContextPropagator) Extract(carrier interface{}) (jaeger.SpanContext, error) {
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return jaeger.SpanContext{}, opentracing.ErrInvalidCarrier
+	}
+
+	var traceParent string
+	if err := reader.ForeachKey(func(key, value string) error {
+		if strings.EqualFold(key, traceParentHeader) {
+			traceParent = value
+		}
+		return nil
+	}); err != nil {
+		return jaeger.SpanContext{}, err
+	}
+
+	if traceParent == "" {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextNotFound
+	}
+
+	return parseTraceParent(traceParent)
+}
+
+// parseTraceParent parses a version-traceid-parentid-flags traceparent header.
+// Fields appended by future versions are ignored as the specification requires.
+func parseTraceParent(traceParent string) (jaeger.SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	version, err := strconv.ParseUint(parts[0], 16, 8)
+	if err != nil || version == 0xff || (version == 0 && len(parts) != 4) {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	traceID, err := jaeger.TraceIDFromString(parts[1])
+	if err != nil || !traceID.IsValid() {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	spanID, err := jaeger.SpanIDFromString(parts[2])
+	if err != nil || spanID == 0 {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	return jaeger.NewSpanContext(traceID, spanID, 0, flags&traceFlagsSampled != 0, nil), nil
+}
+
+// newJaegerPropagator returns the propagator injecting the given formats into carriers of the given
+// opentracing format, either opentracing.TextMap or opentracing.HTTPHeaders.
+func newJaegerPropagator(format opentracing.BuiltinFormat, formats []string) (CompositePropagator, error) {
+	headers := (&jaeger.HeadersConfig{}).ApplyDefaults()
+
+	var propagators []JaegerPropagator
+	for _, f := range formats {
+		switch f {
+		case PropagationUber:
+			if format == opentracing.HTTPHeaders {
+				propagators = append(propagators, jaeger.NewHTTPHeaderPropagator(headers, *jaeger.NewNullMetrics()))
+			} else {
+				propagators = append(propagators, jaeger.NewTextMapPropagator(headers, *jaeger.NewNullMetrics()))
+			}
+		case PropagationTraceContext:
+			propagators = append(propagators, TraceContextPropagator{})
+		case PropagationB3:
+			propagators = append(propagators, zipkin.NewZipkinB3HTTPHeaderPropagator())
+		default:
+			return CompositePropagator{}, InvalidTracerParameterError{
+				parameter: "Propagation",
+				reason:    fmt.Sprintf("unsupported format %q", f),
+			}
+		}
+	}
+
+	return NewCompositePropagator(propagators...)
+}
+
+// newOTelPropagator returns the OpenTelemetry propagator injecting the given formats and the W3C baggage.
+func newOTelPropagator(formats []string) (propagation.TextMapPropagator, error) {
+	propagators := []propagation.TextMapPropagator{propagation.Baggage{}}
+
+	// the composite propagator lets the last format found in the carrier win, so the preferred one goes last.
+	for i := len(formats) - 1; i >= 0; i-- {
+		switch formats[i] {
+		case PropagationUber:
+			propagators = append(propagators, jaegerprop.Jaeger{})
+		case PropagationTraceContext:
+			propagators = append(propagators, propagation.TraceContext{})
+		case PropagationB3:
+			propagators = append(propagators, b3prop.New(b3prop.WithInjectEncoding(b3prop.B3MultipleHeader)))
+		default:
+			return nil, InvalidTracerParameterError{
+				parameter: "Propagation",
+				reason:    fmt.Sprintf("unsupported format %q", formats[i]),
+			}
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
@@ -0,0 +1,260 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
+	"github.com/uber/jaeger-client-go/zipkin"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const (
+	someTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	someTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+)
+
+func TestParsePropagation(t *testing.T) {
+	assert.Nil(t, tracing.ParsePropagation(""))
+	assert.Equal(t, []string{"tracecontext", "b3"}, tracing.ParsePropagation(" TraceContext, b3,,"))
+}
+
+func TestNewCompositePropagator(t *testing.T) {
+	t.Run("it should return an error because no propagator is given", func(t *testing.T) {
+		_, err := tracing.NewCompositePropagator()
+		require.Error(t, err)
+	})
+	t.Run("it should return an error because a propagator is nil", func(t *testing.T) {
+		_, err := tracing.NewCompositePropagator(tracing.TraceContextPropagator{}, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestCompositePropagator(t *testing.T) {
+	propagator, err := tracing.NewCompositePropagator(
+		tracing.TraceContextPropagator{},
+		zipkin.NewZipkinB3HTTPHeaderPropagator(),
+	)
+	require.NoError(t, err)
+
+	t.Run("it should inject every format", func(t *testing.T) {
+		traceID, err := jaeger.TraceIDFromString(someTraceID)
+		require.NoError(t, err)
+
+		carrier := opentracing.TextMapCarrier{}
+		require.NoError(t, propagator.Inject(jaeger.NewSpanContext(traceID, 1, 0, true, nil), carrier))
+
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000001-01", carrier["traceparent"])
+		assert.Equal(t, someTraceID, carrier["x-b3-traceid"])
+		assert.Equal(t, "1", carrier["x-b3-spanid"])
+		assert.Equal(t, "1", carrier["x-b3-sampled"])
+	})
+	t.Run("it should inject the baggage in the baggage header", func(t *testing.T) {
+		traceID, err := jaeger.TraceIDFromString(someTraceID)
+		require.NoError(t, err)
+
+		sc := jaeger.NewSpanContext(traceID, 1, 0, true, map[string]string{
+			tracing.BaggageTenantID: "some tenant",
+			tracing.BaggageUserID:   "someUser,1",
+		})
+
+		carrier := opentracing.TextMapCarrier{}
+		require.NoError(t, propagator.Inject(sc, carrier))
+
+		assert.Equal(t, "tenant-id=some%20tenant,user-id=someUser%2C1", carrier["baggage"])
+	})
+	t.Run("it should extract the baggage carried by the baggage header", func(t *testing.T) {
+		sc, err := propagator.Extract(opentracing.HTTPHeadersCarrier{
+			"Traceparent": []string{someTraceParent},
+			"Baggage":     []string{"tenant-id=some%20tenant;someProperty, malformed", "user-id=someUser%2C1"},
+		})
+		require.NoError(t, err)
+
+		baggage := make(map[string]string)
+		sc.ForeachBaggageItem(func(k, v string) bool {
+			baggage[k] = v
+			return true
+		})
+		assert.Equal(t, map[string]string{
+			tracing.BaggageTenantID: "some tenant",
+			tracing.BaggageUserID:   "someUser,1",
+		}, baggage)
+	})
+	t.Run("it should extract the first format present", func(t *testing.T) {
+		sc, err := propagator.Extract(opentracing.TextMapCarrier{
+			"traceparent":  someTraceParent,
+			"x-b3-traceid": "1",
+			"x-b3-spanid":  "2",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, someTraceID, sc.TraceID().String())
+	})
+	t.Run("it should skip a corrupted format in favour of the next one", func(t *testing.T) {
+		sc, err := propagator.Extract(opentracing.TextMapCarrier{
+			"traceparent":  "00-notatraceid-00f067aa0ba902b7-01",
+			"x-b3-traceid": "1",
+			"x-b3-spanid":  "2",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "1", sc.TraceID().String())
+	})
+	t.Run("it should return the corruption error because no format is valid", func(t *testing.T) {
+		_, err := propagator.Extract(opentracing.TextMapCarrier{"traceparent": "00-notatraceid-00f067aa0ba902b7-01"})
+		assert.Equal(t, opentracing.ErrSpanContextCorrupted, err)
+	})
+	t.Run("it should return a not found error because no format is present", func(t *testing.T) {
+		_, err := propagator.Extract(opentracing.TextMapCarrier{"some": "header"})
+		assert.Equal(t, opentracing.ErrSpanContextNotFound, err)
+	})
+}
+
+func TestTraceContextPropagator_Extract(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		traceParent string
+		wantErr     error
+	}{
+		{name: "it should return an error because the version is invalid", traceParent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: opentracing.ErrSpanContextCorrupted},
+		{name: "it should return an error because version 00 has extra fields", traceParent: someTraceParent + "-extra", wantErr: opentracing.ErrSpanContextCorrupted},
+		{name: "it should return an error because the trace id is all zeros", traceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: opentracing.ErrSpanContextCorrupted},
+		{name: "it should return an error because the parent id is all zeros", traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: opentracing.ErrSpanContextCorrupted},
+		{name: "it should return an error because the flags are not hexadecimal", traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz", wantErr: opentracing.ErrSpanContextCorrupted},
+		{name: "it should return an error because fields are missing", traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736", wantErr: opentracing.ErrSpanContextCorrupted},
+		{name: "it should ignore the fields appended by future versions", traceParent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := tracing.TraceContextPropagator{}.Extract(opentracing.TextMapCarrier{"traceparent": tt.traceParent})
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, someTraceID, sc.TraceID().String())
+			assert.Equal(t, "f067aa0ba902b7", sc.SpanID().String())
+			assert.True(t, sc.IsSampled())
+		})
+	}
+	t.Run("it should extract the traceparent from http headers regardless of the case", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+		sc, err := tracing.TraceContextPropagator{}.Extract(opentracing.HTTPHeadersCarrier(header))
+		require.NoError(t, err)
+		assert.Equal(t, someTraceID, sc.TraceID().String())
+		assert.False(t, sc.IsSampled())
+	})
+	t.Run("it should return an error because the carrier is not a text map", func(t *testing.T) {
+		_, err := tracing.TraceContextPropagator{}.Extract("someCarrier")
+		assert.Equal(t, opentracing.ErrInvalidCarrier, err)
+	})
+}
+
+func TestTracer_Propagation(t *testing.T) {
+	for _, backend := range []string{tracing.BackendJaeger, tracing.BackendOTel} {
+		t.Run("it should inject every configured format and extract any of them with the "+backend+" backend", func(t *testing.T) {
+			// nothing is exported, so closing the tracer doesn't wait for a collector.
+			t.Setenv("OTEL_TRACES_SAMPLER", "always_off")
+
+			tracer, err := tracing.New(context.Background(), tracing.Config{
+				Backend:         backend,
+				ServiceName:     "someService",
+				JaegerAgentHost: "localhost",
+				JaegerAgentPort: "6831",
+				Sampler:         tracing.SamplerConfig{Param: 1},
+			})
+			require.NoError(t, err)
+			defer tracer.Close()
+
+			span := tracer.StartSpan("someOperation")
+			defer span.Finish()
+
+			span.SetBaggageItem(tracing.BaggageTenantID, "someTenant")
+
+			header := http.Header{}
+			require.NoError(t, tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)))
+
+			extracted, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+			require.NoError(t, err)
+
+			baggage := make(map[string]string)
+			extracted.ForeachBaggageItem(func(k, v string) bool {
+				baggage[k] = v
+				return true
+			})
+			assert.Equal(t, map[string]string{tracing.BaggageTenantID: "someTenant"}, baggage)
+
+			assert.NotEmpty(t, header.Get("Uber-Trace-Id"))
+			assert.NotEmpty(t, header.Get("Traceparent"))
+			assert.NotEmpty(t, header.Get("X-B3-Traceid"))
+
+			for _, key := range []string{"Uber-Trace-Id", "Traceparent", "X-B3-Traceid"} {
+				single := http.Header{}
+				for k, v := range header {
+					if k == key || (key == "X-B3-Traceid" && (k == "X-B3-Spanid" || k == "X-B3-Sampled")) {
+						single[k] = v
+					}
+				}
+
+				sc, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(single))
+				require.NoError(t, err, key)
+				assert.Equal(t, tracing.TraceID(span), tracing.SpanContextTraceID(sc), key)
+			}
+
+			sc, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{"traceparent": someTraceParent})
+			require.NoError(t, err)
+			assert.Equal(t, someTraceID, tracing.SpanContextTraceID(sc))
+		})
+	}
+	for _, backend := range []string{tracing.BackendJaeger, tracing.BackendOTel} {
+		t.Run("it should carry the baggage without the uber format with the "+backend+" backend", func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_SAMPLER", "always_off")
+
+			tracer, err := tracing.New(context.Background(), tracing.Config{
+				Backend:         backend,
+				ServiceName:     "someService",
+				JaegerAgentHost: "localhost",
+				JaegerAgentPort: "6831",
+				Sampler:         tracing.SamplerConfig{Param: 1},
+				Propagation:     []string{tracing.PropagationTraceContext, tracing.PropagationB3},
+			})
+			require.NoError(t, err)
+			defer tracer.Close()
+
+			span := tracer.StartSpan("someOperation")
+			defer span.Finish()
+
+			span.SetBaggageItem(tracing.BaggageTenantID, "someTenant")
+
+			header := http.Header{}
+			require.NoError(t, tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)))
+
+			extracted, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+			require.NoError(t, err)
+
+			baggage := make(map[string]string)
+			extracted.ForeachBaggageItem(func(k, v string) bool {
+				baggage[k] = v
+				return true
+			})
+			assert.Equal(t, map[string]string{tracing.BaggageTenantID: "someTenant"}, baggage)
+		})
+	}
+	t.Run("it should return an error because a propagation format is not supported", func(t *testing.T) {
+		for _, backend := range []string{tracing.BackendJaeger, tracing.BackendOTel} {
+			tracer, err := tracing.New(context.Background(), tracing.Config{
+				Backend:         backend,
+				ServiceName:     "someService",
+				JaegerAgentHost: "localhost",
+				JaegerAgentPort: "6831",
+				Propagation:     []string{"xray"},
+			})
+			require.Error(t, err, backend)
+			assert.Nil(t, tracer, backend)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uber/jaeger-client-go"
+	"github.com/uber/jaeger-client-go/thrift-gen/sampling"
+)
+
+const (
+	// SamplerConst samples either every trace or none of them.
+	SamplerConst = "const"
+	// SamplerProbabilistic samples traces with the probability set by the sampler param.
+	SamplerProbabilistic = "probabilistic"
+	// SamplerRateLimiting samples up to the number of traces per second set by the sampler param.
+	SamplerRateLimiting = "ratelimiting"
+	// SamplerPerOperation samples each operation with the probability set for it by the strategies file.
+	SamplerPerOperation = "peroperation"
+	// SamplerRemote periodically pulls the sampling strategy from a sampling server or from the strategies file.
+	SamplerRemote = "remote"
+)
+
+// SamplerConfig describes how the traces started by a service are sampled.
+type SamplerConfig struct {
+	// Type is one of the Sampler* types. It defaults to SamplerConst.
+	Type string
+	// Param is the decision of SamplerConst (0 or 1), the probability of SamplerProbabilistic,
+	// the traces per second of SamplerRateLimiting and the initial probability of SamplerRemote.
+	Param float64
+	// ServerURL is the sampling server SamplerRemote pulls the strategies from.
+	ServerURL string
+	// StrategiesFile is a file holding a sampling server response. It is required by SamplerPerOperation
+	// and replaces the sampling server of SamplerRemote, which reloads it at every refresh.
+	StrategiesFile string
+	// RefreshInterval is how often SamplerRemote pulls the strategies.
+	RefreshInterval time.Duration
+	// MaxOperations is the number of operations sampled on their own, the others use the default strategy.
+	MaxOperations int
+}
+
+// SamplerConfigFromEnv reads the sampler configuration from the JAEGER_SAMPLER_TYPE, JAEGER_SAMPLER_PARAM,
+// JAEGER_SAMPLING_ENDPOINT, JAEGER_SAMPLING_STRATEGIES_FILE, JAEGER_SAMPLER_REFRESH_INTERVAL and
+// JAEGER_SAMPLER_MAX_OPERATIONS environment variables. They are all optional and Param defaults to 1.
+func SamplerConfigFromEnv() (SamplerConfig, error) {
+	cfg := SamplerConfig{
+		Type:           strings.ToLower(os.Getenv("JAEGER_SAMPLER_TYPE")),
+		Param:          1,
+		ServerURL:      os.Getenv("JAEGER_SAMPLING_ENDPOINT"),
+		StrategiesFile: os.Getenv("JAEGER_SAMPLING_STRATEGIES_FILE"),
+	}
+
+	if v, ok := os.LookupEnv("JAEGER_SAMPLER_PARAM"); ok {
+		param, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return SamplerConfig{}, fmt.Errorf("could not parse JAEGER_SAMPLER_PARAM: %w", err)
+		}
+		cfg.Param = param
+	}
+
+	if v, ok := os.LookupEnv("JAEGER_SAMPLER_REFRESH_INTERVAL"); ok {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return SamplerConfig{}, fmt.Errorf("could not parse JAEGER_SAMPLER_REFRESH_INTERVAL: %w", err)
+		}
+		cfg.RefreshInterval = interval
+	}
+
+	if v, ok := os.LookupEnv("JAEGER_SAMPLER_MAX_OPERATIONS"); ok {
+		maxOperations, err := strconv.Atoi(v)
+		if err != nil {
+			return SamplerConfig{}, fmt.Errorf("could not parse JAEGER_SAMPLER_MAX_OPERATIONS: %w", err)
+		}
+		cfg.MaxOperations = maxOperations
+	}
+
+	return cfg, nil
+}
+
+// NewSampler returns the sampler described by cfg for the given service.
+func NewSampler(serviceName string, cfg SamplerConfig) (jaeger.Sampler, error) {
+	switch cfg.Type {
+	case "", SamplerConst:
+		return jaeger.NewConstSampler(cfg.Param != 0), nil
+	case SamplerProbabilistic:
+		sampler, err := jaeger.NewProbabilisticSampler(cfg.Param)
+		if err != nil {
+			return nil, InvalidTracerParameterError{parameter: "Param", reason: err.Error()}
+		}
+		return sampler, nil
+	case SamplerRateLimiting:
+		if cfg.Param <= 0 {
+			return nil, InvalidTracerParameterError{parameter: "Param", reason: "must be greater than 0"}
+		}
+		return jaeger.NewRateLimitingSampler(cfg.Param), nil
+	case SamplerPerOperation:
+		return newPerOperationSampler(cfg)
+	case SamplerRemote:
+		return newRemoteSampler(serviceName, cfg)
+	default:
+		return nil, InvalidTracerParameterError{
+			parameter: "Type",
+			reason:    fmt.Sprintf("unsupported sampler %q", cfg.Type),
+		}
+	}
+}
+
+func newPerOperationSampler(cfg SamplerConfig) (jaeger.Sampler, error) {
+	if cfg.StrategiesFile == "" {
+		return nil, InvalidTracerParameterError{parameter: "StrategiesFile", reason: "cannot be empty"}
+	}
+
+	b, err := os.ReadFile(cfg.StrategiesFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read sampling strategies: %w", err)
+	}
+
+	var strategy sampling.SamplingStrategyResponse
+	if err := json.Unmarshal(b, &strategy); err != nil {
+		return nil, fmt.Errorf("could not deserialise sampling strategies: %w", err)
+	}
+
+	if strategy.OperationSampling == nil {
+		return nil, InvalidTracerParameterError{parameter: "StrategiesFile", reason: "must declare operation sampling"}
+	}
+
+	return jaeger.NewPerOperationSampler(jaeger.PerOperationSamplerParams{
+		MaxOperations: cfg.MaxOperations,
+		Strategies:    strategy.OperationSampling,
+	}), nil
+}
+
+func newRemoteSampler(serviceName string, cfg SamplerConfig) (jaeger.Sampler, error) {
+	initial, err := jaeger.NewProbabilisticSampler(cfg.Param)
+	if err != nil {
+		return nil, InvalidTracerParameterError{parameter: "Param", reason: err.Error()}
+	}
+
+	opts := []jaeger.SamplerOption{
+		jaeger.SamplerOptions.InitialSampler(initial),
+		jaeger.SamplerOptions.SamplingRefreshInterval(cfg.RefreshInterval),
+		jaeger.SamplerOptions.MaxOperations(cfg.MaxOperations),
+	}
+
+	switch {
+	case cfg.StrategiesFile != "":
+		opts = append(opts, jaeger.SamplerOptions.SamplingStrategyFetcher(FileStrategyFetcher{path: cfg.StrategiesFile}))
+	case cfg.ServerURL != "":
+		opts = append(opts, jaeger.SamplerOptions.SamplingServerURL(cfg.ServerURL))
+	}
+
+	sampler := jaeger.NewRemotelyControlledSampler(serviceName, opts...)
+	if cfg.StrategiesFile != "" {
+		// the file is at hand, so don't wait for the first refresh to apply it.
+		sampler.UpdateSampler()
+	}
+
+	return sampler, nil
+}
+
+// FileStrategyFetcher reads the sampling strategies from a file holding a sampling server response
+// instead of requesting them to a sampling server.
+type FileStrategyFetcher struct {
+	path string
+}
+
+// NewFileStrategyFetcher returns a new FileStrategyFetcher reading the strategies from path.
+func NewFileStrategyFetcher(path string) (FileStrategyFetcher, error) {
+	if path == "" {
+		return FileStrategyFetcher{}, InvalidTracerParameterError{parameter: "path", reason: "cannot be empty"}
+	}
+	return FileStrategyFetcher{path: path}, nil
+}
+
+// Fetch returns the content of the strategies file, regardless of the service.
+func (f FileStrategyFetcher) Fetch(string) ([]byte, error) {
+	return os.ReadFile(f.path)
+}
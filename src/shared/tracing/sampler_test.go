@@ -0,0 +1,212 @@
+package tracing_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const operationStrategies = `{
+  "strategyType": "PROBABILISTIC",
+  "operationSampling": {
+    "defaultSamplingProbability": 0,
+    "defaultLowerBoundTracesPerSecond": 0.0001,
+    "perOperationStrategies": [
+      {"operation": "always", "probabilisticSampling": {"samplingRate": 1}}
+    ]
+  }
+}`
+
+func TestSamplerConfigFromEnv(t *testing.T) {
+	t.Run("it should return an error because the param is not a number", func(t *testing.T) {
+		t.Setenv("JAEGER_SAMPLER_PARAM", "half")
+
+		_, err := tracing.SamplerConfigFromEnv()
+		require.Error(t, err)
+	})
+	t.Run("it should return an error because the refresh interval is not a duration", func(t *testing.T) {
+		t.Setenv("JAEGER_SAMPLER_REFRESH_INTERVAL", "often")
+
+		_, err := tracing.SamplerConfigFromEnv()
+		require.Error(t, err)
+	})
+	t.Run("it should return an error because the max operations is not an integer", func(t *testing.T) {
+		t.Setenv("JAEGER_SAMPLER_MAX_OPERATIONS", "many")
+
+		_, err := tracing.SamplerConfigFromEnv()
+		require.Error(t, err)
+	})
+	t.Run("it should return the configuration sampling every trace when nothing is set", func(t *testing.T) {
+		cfg, err := tracing.SamplerConfigFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, tracing.SamplerConfig{Param: 1}, cfg)
+	})
+	t.Run("it should return the configuration read from the environment", func(t *testing.T) {
+		t.Setenv("JAEGER_SAMPLER_TYPE", "Remote")
+		t.Setenv("JAEGER_SAMPLER_PARAM", "0.25")
+		t.Setenv("JAEGER_SAMPLING_ENDPOINT", "http://jaeger:5778/sampling")
+		t.Setenv("JAEGER_SAMPLING_STRATEGIES_FILE", "/etc/sampling.json")
+		t.Setenv("JAEGER_SAMPLER_REFRESH_INTERVAL", "30s")
+		t.Setenv("JAEGER_SAMPLER_MAX_OPERATIONS", "10")
+
+		cfg, err := tracing.SamplerConfigFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, tracing.SamplerConfig{
+			Type:            tracing.SamplerRemote,
+			Param:           0.25,
+			ServerURL:       "http://jaeger:5778/sampling",
+			StrategiesFile:  "/etc/sampling.json",
+			RefreshInterval: 30 * time.Second,
+			MaxOperations:   10,
+		}, cfg)
+	})
+}
+
+func TestNewSampler(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  tracing.SamplerConfig
+	}{
+		{
+			name: "it should return an error because the sampler is not supported",
+			cfg:  tracing.SamplerConfig{Type: "adaptive"},
+		},
+		{
+			name: "it should return an error because the probability is greater than 1",
+			cfg:  tracing.SamplerConfig{Type: tracing.SamplerProbabilistic, Param: 2},
+		},
+		{
+			name: "it should return an error because the rate is not positive",
+			cfg:  tracing.SamplerConfig{Type: tracing.SamplerRateLimiting},
+		},
+		{
+			name: "it should return an error because the per operation strategies file is missing",
+			cfg:  tracing.SamplerConfig{Type: tracing.SamplerPerOperation},
+		},
+		{
+			name: "it should return an error because the per operation strategies file does not exist",
+			cfg:  tracing.SamplerConfig{Type: tracing.SamplerPerOperation, StrategiesFile: "/does/not/exist.json"},
+		},
+		{
+			name: "it should return an error because the initial remote probability is negative",
+			cfg:  tracing.SamplerConfig{Type: tracing.SamplerRemote, Param: -1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sampler, err := tracing.NewSampler("someService", tt.cfg)
+			require.Error(t, err)
+			assert.Nil(t, sampler)
+		})
+	}
+	t.Run("it should return a sampler sampling every trace by default", func(t *testing.T) {
+		sampler, err := tracing.NewSampler("someService", tracing.SamplerConfig{Param: 1})
+		require.NoError(t, err)
+		assert.True(t, sampler.Equal(jaeger.NewConstSampler(true)))
+	})
+	t.Run("it should return a probabilistic sampler", func(t *testing.T) {
+		sampler, err := tracing.NewSampler("someService", tracing.SamplerConfig{
+			Type:  tracing.SamplerProbabilistic,
+			Param: 0.1,
+		})
+		require.NoError(t, err)
+
+		want, err := jaeger.NewProbabilisticSampler(0.1)
+		require.NoError(t, err)
+		assert.True(t, sampler.Equal(want))
+	})
+	t.Run("it should return a rate limiting sampler", func(t *testing.T) {
+		sampler, err := tracing.NewSampler("someService", tracing.SamplerConfig{
+			Type:  tracing.SamplerRateLimiting,
+			Param: 5,
+		})
+		require.NoError(t, err)
+		assert.True(t, sampler.Equal(jaeger.NewRateLimitingSampler(5)))
+	})
+	t.Run("it should return a sampler sampling each operation as the strategies file says", func(t *testing.T) {
+		sampler, err := tracing.NewSampler("someService", tracing.SamplerConfig{
+			Type:           tracing.SamplerPerOperation,
+			StrategiesFile: writeStrategies(t, operationStrategies),
+		})
+		require.NoError(t, err)
+
+		tracer, closer := jaeger.NewTracer("someService", sampler, jaeger.NewNullReporter())
+		defer closer.Close()
+
+		assert.Equal(t, 10, sampledSpans(tracer, "always", 10))
+		// the lower bound lets a single trace per operation through.
+		assert.Equal(t, 1, sampledSpans(tracer, "never", 10))
+	})
+	t.Run("it should return a remote sampler applying the strategy read from the strategies file", func(t *testing.T) {
+		sampler, err := tracing.NewSampler("someService", tracing.SamplerConfig{
+			Type:           tracing.SamplerRemote,
+			Param:          1,
+			StrategiesFile: writeStrategies(t, `{"strategyType":"PROBABILISTIC","probabilisticSampling":{"samplingRate":0.5}}`),
+		})
+		require.NoError(t, err)
+		defer sampler.Close()
+
+		remote, ok := sampler.(*jaeger.RemotelyControlledSampler)
+		require.True(t, ok)
+
+		probabilistic, ok := remote.Sampler().(*jaeger.ProbabilisticSampler)
+		require.True(t, ok)
+		assert.Equal(t, 0.5, probabilistic.SamplingRate())
+	})
+	t.Run("it should return a remote sampler keeping the initial probability until a strategy is read", func(t *testing.T) {
+		sampler, err := tracing.NewSampler("someService", tracing.SamplerConfig{
+			Type:           tracing.SamplerRemote,
+			Param:          0.2,
+			StrategiesFile: filepath.Join(t.TempDir(), "missing.json"),
+		})
+		require.NoError(t, err)
+		defer sampler.Close()
+
+		remote, ok := sampler.(*jaeger.RemotelyControlledSampler)
+		require.True(t, ok)
+
+		probabilistic, ok := remote.Sampler().(*jaeger.ProbabilisticSampler)
+		require.True(t, ok)
+		assert.Equal(t, 0.2, probabilistic.SamplingRate())
+	})
+}
+
+func TestFileStrategyFetcher_Fetch(t *testing.T) {
+	t.Run("it should return an error because the path is empty", func(t *testing.T) {
+		_, err := tracing.NewFileStrategyFetcher("")
+		require.Error(t, err)
+	})
+	t.Run("it should return the content of the strategies file", func(t *testing.T) {
+		fetcher, err := tracing.NewFileStrategyFetcher(writeStrategies(t, operationStrategies))
+		require.NoError(t, err)
+
+		b, err := fetcher.Fetch("someService")
+		require.NoError(t, err)
+		assert.Equal(t, operationStrategies, string(b))
+	})
+}
+
+func writeStrategies(t *testing.T, strategies string) string {
+	path := filepath.Join(t.TempDir(), "sampling.json")
+	require.NoError(t, os.WriteFile(path, []byte(strategies), 0600))
+	return path
+}
+
+func sampledSpans(tracer opentracing.Tracer, operation string, n int) int {
+	var sampled int
+	for i := 0; i < n; i++ {
+		span := tracer.StartSpan(operation)
+		if span.Context().(jaeger.SpanContext).IsSampled() {
+			sampled++
+		}
+		span.Finish()
+	}
+	return sampled
+}
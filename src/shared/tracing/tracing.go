@@ -1,6 +1,7 @@
 package tracing
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -11,8 +12,26 @@ import (
 	"github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/config"
 	jaegercfg "github.com/uber/jaeger-client-go/config"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
+const (
+	// BackendJaeger reports spans to a jaeger agent.
+	BackendJaeger = "jaeger"
+	// BackendOTel exports spans to an OpenTelemetry collector through the opentracing bridge.
+	BackendOTel = "otel"
+)
+
+// InvalidTracerParameterError is returned when a tracer cannot be created with the given parameters.
+type InvalidTracerParameterError struct {
+	parameter string
+	reason    string
+}
+
+func (i InvalidTracerParameterError) Error() string {
+	return fmt.Sprintf("invalid parameter %s: %s", i.parameter, i.reason)
+}
+
 // Tracer defines the open tracing interface.
 type Tracer interface {
 	StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span
@@ -21,34 +40,93 @@ type Tracer interface {
 	Close() error
 }
 
+// Config describes which backend a tracer reports its spans to.
+type Config struct {
+	// Backend is either BackendJaeger or BackendOTel. It defaults to BackendJaeger.
+	Backend     string
+	ServiceName string
+	// JaegerAgentHost and JaegerAgentPort are required by BackendJaeger only.
+	JaegerAgentHost string
+	JaegerAgentPort string
+	// Sampler is used by BackendJaeger only, BackendOTel follows the standard OTEL_TRACES_SAMPLER variables.
+	Sampler SamplerConfig
+	// Propagation lists the formats span contexts are injected in, from the most to the least
+	// preferred on extraction. It defaults to DefaultPropagation.
+	Propagation []string
+}
+
+func (c Config) propagation() []string {
+	if len(c.Propagation) == 0 {
+		return DefaultPropagation
+	}
+	return c.Propagation
+}
+
+// New returns the tracer of the backend selected by cfg.
+func New(ctx context.Context, cfg Config) (Tracer, error) {
+	switch cfg.Backend {
+	case "", BackendJaeger:
+		return NewJaegerTracer(cfg)
+	case BackendOTel:
+		return NewOTelTracer(ctx, cfg)
+	default:
+		return nil, InvalidTracerParameterError{
+			parameter: "Backend",
+			reason:    fmt.Sprintf("unsupported backend %q", cfg.Backend),
+		}
+	}
+}
+
 // JaegerTracer wraps an opentracing tracer with a jaeger reporter.
 type JaegerTracer struct {
 	closer       io.Closer
 	globalTracer opentracing.Tracer
 }
 
-// NewJaegerTracer returns a traces with defaults.
-func NewJaegerTracer(serviceName, reporterHostName, reporterPort string) (Tracer, error) {
+// NewJaegerTracer returns a tracer reporting to the jaeger agent of cfg, sampling and propagating
+// traces as cfg describes.
+func NewJaegerTracer(cfg Config) (Tracer, error) {
+	if cfg.JaegerAgentHost == "" {
+		return nil, InvalidTracerParameterError{parameter: "JaegerAgentHost", reason: "cannot be empty"}
+	}
+	if cfg.JaegerAgentPort == "" {
+		return nil, InvalidTracerParameterError{parameter: "JaegerAgentPort", reason: "cannot be empty"}
+	}
+
 	logger, err := logging.NewZapLogger()
 	if err != nil {
 		return nil, fmt.Errorf("could not create a new logger: %w", err)
 	}
 
-	cfg := jaegercfg.Configuration{
-		ServiceName: serviceName,
-		Sampler: &jaegercfg.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1,
-		},
+	sampler, err := NewSampler(cfg.ServiceName, cfg.Sampler)
+	if err != nil {
+		return nil, fmt.Errorf("could not create a new sampler: %w", err)
+	}
+
+	opts := []config.Option{
+		config.Logger(logger),
+		config.Sampler(sampler),
+		// W3C trace context requires 128 bit trace ids.
+		config.Gen128Bit(true),
+	}
+
+	for _, format := range []opentracing.BuiltinFormat{opentracing.TextMap, opentracing.HTTPHeaders} {
+		propagator, err := newJaegerPropagator(format, cfg.propagation())
+		if err != nil {
+			return nil, fmt.Errorf("could not create a new propagator: %w", err)
+		}
+		opts = append(opts, config.Injector(format, propagator), config.Extractor(format, propagator))
+	}
+
+	jaegerCfg := jaegercfg.Configuration{
+		ServiceName: cfg.ServiceName,
 		Reporter: &jaegercfg.ReporterConfig{
 			LogSpans:           true,
-			LocalAgentHostPort: reporterHostName + ":" + reporterPort,
+			LocalAgentHostPort: cfg.JaegerAgentHost + ":" + cfg.JaegerAgentPort,
 		},
 	}
 
-	tracer, closer, err := cfg.NewTracer(
-		config.Logger(logger),
-	)
+	tracer, closer, err := jaegerCfg.NewTracer(opts...)
 	if err != nil {
 		log.Fatalf("could not initialise tracer: %v", err)
 	}
@@ -80,3 +158,35 @@ func (jt JaegerTracer) Extract(format interface{}, carrier interface{}) (opentra
 func (jt JaegerTracer) Close() error {
 	return jt.closer.Close()
 }
+
+// TraceID returns the id of the trace span belongs to, or an empty string when it's unknown.
+func TraceID(span opentracing.Span) string {
+	if span == nil {
+		return ""
+	}
+	return SpanContextTraceID(span.Context())
+}
+
+// SpanContextTraceID returns the id of the trace sc belongs to, or an empty string when it's unknown.
+func SpanContextTraceID(sc opentracing.SpanContext) string {
+	switch c := sc.(type) {
+	case jaeger.SpanContext:
+		return c.TraceID().String()
+	case interface{ TraceID() oteltrace.TraceID }:
+		// Span contexts exposing an OpenTelemetry trace id, like the ones of the OpenTelemetry bridge.
+		if id := c.TraceID(); id.IsValid() {
+			return id.String()
+		}
+	}
+	return ""
+}
+
+// TextMapFromContext returns the text map carrying the span in ctx.
+// The returned map is empty when ctx carries no span.
+func TextMapFromContext(ctx context.Context) map[string]string {
+	carrier := make(map[string]string)
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		_ = span.Tracer().Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(carrier))
+	}
+	return carrier
+}
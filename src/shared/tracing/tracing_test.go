@@ -0,0 +1,77 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+func TestNew(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  tracing.Config
+	}{
+		{
+			name: "it should return an error because the backend is not supported",
+			cfg:  tracing.Config{Backend: "zipkin", ServiceName: "someService"},
+		},
+		{
+			name: "it should return an error because the jaeger agent host is missing",
+			cfg:  tracing.Config{ServiceName: "someService", JaegerAgentPort: "6831"},
+		},
+		{
+			name: "it should return an error because the jaeger agent port is missing",
+			cfg:  tracing.Config{Backend: tracing.BackendJaeger, ServiceName: "someService", JaegerAgentHost: "jaeger"},
+		},
+		{
+			name: "it should return an error because the otel service name is missing",
+			cfg:  tracing.Config{Backend: tracing.BackendOTel},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, err := tracing.New(context.Background(), tt.cfg)
+			require.Error(t, err)
+			assert.Nil(t, tracer)
+		})
+	}
+	t.Run("it should return an error because the otlp protocol is not supported", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+
+		tracer, err := tracing.New(context.Background(), tracing.Config{
+			Backend:     tracing.BackendOTel,
+			ServiceName: "someService",
+		})
+		require.Error(t, err)
+		assert.Nil(t, tracer)
+	})
+}
+
+func TestTraceID(t *testing.T) {
+	t.Run("it should return an empty trace id because there is no span", func(t *testing.T) {
+		assert.Empty(t, tracing.TraceID(nil))
+	})
+	t.Run("it should return an empty trace id because the span context is unknown", func(t *testing.T) {
+		span := opentracing.NoopTracer{}.StartSpan("someOperation")
+		assert.Empty(t, tracing.TraceID(span))
+	})
+	t.Run("it should return the trace id of a span started through the opentelemetry bridge", func(t *testing.T) {
+		provider := sdktrace.NewTracerProvider()
+		defer provider.Shutdown(context.Background())
+
+		bridgeTracer, _ := otelbridge.NewTracerPair(provider.Tracer("someService"))
+
+		span := bridgeTracer.StartSpan("someOperation")
+		defer span.Finish()
+
+		traceID := tracing.TraceID(span)
+		assert.Len(t, traceID, 32)
+		assert.NotEqual(t, "00000000000000000000000000000000", traceID)
+	})
+}
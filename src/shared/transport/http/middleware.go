@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc/codes"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const (
+	component       = "net/http"
+	routeTag        = "http.route"
+	responseSizeTag = "http.response_size"
+)
+
+// NewTracingMiddleware returns a middleware tracing the requests served by a mux router.
+// Each request gets a server span following the span context extracted from its headers in any of the formats
+// propagated by tracer, named after its method and the template of the matched route, e.g. "POST /receiver/todo".
+// The span is carried by the request context, so handlers get it through opentracing.SpanFromContext,
+// and is tagged with the method, the url, the status code and the size of the response.
+// Panics are recovered, reported on the span and answered with an internal problem.
+func NewTracingMiddleware(tracer opentracing.Tracer) (mux.MiddlewareFunc, error) {
+	if tracer == nil {
+		return nil, errors.New("tracer cannot be nil")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			spanCtx, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
+			if err != nil && !errors.Is(err, opentracing.ErrSpanContextNotFound) {
+				log.Println(fmt.Sprintf("could not extract tracing headers: %s", err))
+			}
+
+			route := routeTemplate(r)
+
+			span := tracer.StartSpan(r.Method+" "+route, ext.RPCServerOption(spanCtx))
+			defer span.Finish()
+
+			ext.Component.Set(span, component)
+			ext.HTTPMethod.Set(span, r.Method)
+			ext.HTTPUrl.Set(span, r.URL.String())
+			span.SetTag(routeTag, route)
+
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				if p := recover(); p != nil {
+					recoverPanic(rec, span, p)
+				}
+				ext.HTTPStatusCode.Set(span, uint16(rec.statusCode()))
+				span.SetTag(responseSizeTag, rec.size)
+			}()
+
+			next.ServeHTTP(rec, r.WithContext(opentracing.ContextWithSpan(r.Context(), span)))
+		})
+	}, nil
+}
+
+// recoverPanic reports the panic p on span and answers with an internal problem, unless the response is
+// already on its way. http.ErrAbortHandler is panicked again for net/http to abort the response.
+func recoverPanic(rec *statusRecorder, span opentracing.Span, p interface{}) {
+	err := fmt.Errorf("panic: %v", p)
+	log.Println(fmt.Sprintf("recovered from %s", err))
+
+	if p == http.ErrAbortHandler {
+		tracing.SetError(span, err)
+		panic(p)
+	}
+
+	if rec.status != 0 {
+		tracing.SetError(span, err)
+		return
+	}
+
+	WriteProblem(rec, span, err, codes.Internal, "internal error")
+}
+
+// routeTemplate returns the path template of the route matched by r, or its path when no route matched.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return r.URL.Path
+}
+
+// statusRecorder records the status code and the size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if s.status == 0 {
+		s.status = statusCode
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.size += n
+	return n, err
+}
+
+// Unwrap returns the recorded writer, for http.ResponseController to reach it.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// statusCode returns the recorded status code, http.StatusOK when the handler wrote nothing.
+func (s *statusRecorder) statusCode() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
@@ -0,0 +1,111 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	transporthttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+func TestNewTracingMiddleware(t *testing.T) {
+	t.Run("it should return an error because the tracer is nil", func(t *testing.T) {
+		_, err := transporthttp.NewTracingMiddleware(nil)
+		require.Error(t, err)
+	})
+	t.Run("it should trace the request in a server span following the incoming span context", func(t *testing.T) {
+		tracer := recorder.New()
+
+		middleware, err := transporthttp.NewTracingMiddleware(tracer)
+		require.NoError(t, err)
+
+		var handlerSpan opentracing.Span
+
+		router := mux.NewRouter()
+		router.Use(middleware)
+		router.HandleFunc("/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
+			handlerSpan = opentracing.SpanFromContext(r.Context())
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("hello"))
+		}).Methods(http.MethodPut)
+
+		client := tracer.StartSpan("client")
+		req := httptest.NewRequest(http.MethodPut, "/todos/someID?someParam=someValue", nil)
+		require.NoError(t, tracer.Inject(client.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header)))
+		client.Finish()
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusCreated, rec.Result().StatusCode)
+		require.NotNil(t, handlerSpan)
+
+		var (
+			spans = tracer.FinishedSpans()
+			span  = traceassert.Span(t, spans, "PUT /todos/{id}")
+		)
+
+		traceassert.ChildOf(t, span, traceassert.Span(t, spans, "client"))
+		assert.Equal(t, map[string]interface{}{
+			string(ext.SpanKind):       ext.SpanKindRPCServerEnum,
+			string(ext.Component):      "net/http",
+			string(ext.HTTPMethod):     http.MethodPut,
+			string(ext.HTTPUrl):        "/todos/someID?someParam=someValue",
+			string(ext.HTTPStatusCode): uint16(http.StatusCreated),
+			"http.route":               "/todos/{id}",
+			"http.response_size":       5,
+		}, span.Tags)
+	})
+	t.Run("it should start a root span because the request carries no span context", func(t *testing.T) {
+		tracer := recorder.New()
+
+		middleware, err := transporthttp.NewTracingMiddleware(tracer)
+		require.NoError(t, err)
+
+		router := mux.NewRouter()
+		router.Use(middleware)
+		router.HandleFunc("/todos", func(http.ResponseWriter, *http.Request) {}).Methods(http.MethodGet)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/todos", nil))
+
+		span := traceassert.Span(t, tracer.FinishedSpans(), "GET /todos")
+		traceassert.Root(t, span)
+		traceassert.HasTag(t, span, string(ext.HTTPStatusCode), uint16(http.StatusOK))
+		traceassert.NoTag(t, span, string(ext.Error))
+	})
+	t.Run("it should recover from a panic with an internal problem and mark the span as failed", func(t *testing.T) {
+		tracer := recorder.New()
+
+		middleware, err := transporthttp.NewTracingMiddleware(tracer)
+		require.NoError(t, err)
+
+		router := mux.NewRouter()
+		router.Use(middleware)
+		router.HandleFunc("/todos", func(http.ResponseWriter, *http.Request) {
+			panic("someErr")
+		}).Methods(http.MethodPost)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/todos", nil))
+
+		require.Equal(t, http.StatusInternalServerError, rec.Result().StatusCode)
+
+		var p transporthttp.Problem
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&p))
+		assert.Equal(t, "INTERNAL", p.Code)
+
+		span := traceassert.Span(t, tracer.FinishedSpans(), "POST /todos")
+		traceassert.HasTag(t, span, string(ext.Error), true)
+		traceassert.HasTag(t, span, string(ext.HTTPStatusCode), uint16(http.StatusInternalServerError))
+		traceassert.HasLogField(t, span, "message", "panic: someErr")
+	})
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
+)
+
+// ProblemContentType is the media type of problem details as defined in RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// ErrNotAProblem is returned when a response does not carry problem details.
+var ErrNotAProblem = errors.New("response does not carry problem details")
+
+// Problem describes an error response following RFC 7807.
+// Code, Message, TraceID and Violations are extension members.
+type Problem struct {
+	Title      string                      `json:"title"`
+	Status     int                         `json:"status"`
+	Code       string                      `json:"code"`
+	Message    string                      `json:"message"`
+	TraceID    string                      `json:"trace_id,omitempty"`
+	Violations []validation.FieldViolation `json:"violations,omitempty"`
+}
+
+// NewProblem returns the problem matching the given grpc code, tagged with the trace id of span.
+func NewProblem(span opentracing.Span, code codes.Code, message string) Problem {
+	statusCode := StatusFromCode(code)
+	return Problem{
+		Title:   http.StatusText(statusCode),
+		Status:  statusCode,
+		Code:    CodeName(code),
+		Message: message,
+		TraceID: tracing.TraceID(span),
+	}
+}
+
+// Error returns the code and the message of the problem, so that it can be reported as an error.
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Code, p.Message)
+}
+
+// Trace tags span with the status of the problem and marks it as failed because of err,
+// or because of the problem itself when err is nil.
+func (p Problem) Trace(span opentracing.Span, err error, fields ...otlog.Field) {
+	if err == nil {
+		err = p
+	}
+	tracing.SetHTTPError(span, p.Status, err, fields...)
+}
+
+// Write writes the problem as the response.
+func (p Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Println(fmt.Sprintf("could not serialise problem: %s", err))
+	}
+}
+
+// WriteProblem writes the problem matching the given grpc code as the response
+// and marks span as failed because of err, the cause of the problem.
+func WriteProblem(w http.ResponseWriter, span opentracing.Span, err error, code codes.Code, message string) {
+	p := NewProblem(span, code, message)
+	p.Trace(span, err, tracing.GRPCStatusCode(code))
+	p.Write(w)
+}
+
+// WriteStatusError writes the problem matching the grpc status carried by err as the response,
+// including the field violations it details, and marks span as failed because of err.
+// Errors that don't carry a status are reported as unknown.
+func WriteStatusError(w http.ResponseWriter, span opentracing.Span, err error) {
+	st := status.Convert(err)
+	p := NewProblem(span, st.Code(), st.Message())
+	p.Violations = validation.ViolationsFromStatus(st)
+	p.Trace(span, err)
+	p.Write(w)
+}
+
+// WriteBodyError writes the problem describing why the request body could not be decoded as the response:
+// 413 when it's larger than the limit of its http.MaxBytesReader, 400 when it's malformed.
+func WriteBodyError(w http.ResponseWriter, span opentracing.Span, err error) {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		WriteProblem(w, span, err, codes.InvalidArgument, "malformed request body")
+		return
+	}
+
+	p := NewProblem(span, codes.InvalidArgument, fmt.Sprintf("request body must be at most %d bytes", tooLarge.Limit))
+	p.Title = http.StatusText(http.StatusRequestEntityTooLarge)
+	p.Status = http.StatusRequestEntityTooLarge
+	p.Trace(span, err, tracing.GRPCStatusCode(codes.InvalidArgument))
+	p.Write(w)
+}
+
+// ReadProblem decodes the problem details carried by resp.
+// ErrNotAProblem is returned when resp is not a problem+json response.
+func ReadProblem(resp *http.Response) (Problem, error) {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != ProblemContentType {
+		return Problem{}, ErrNotAProblem
+	}
+
+	var p Problem
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return Problem{}, fmt.Errorf("could not deserialise problem: %w", err)
+	}
+
+	if p.Status == 0 {
+		p.Status = resp.StatusCode
+	}
+
+	return p, nil
+}
+
+// StatusFromCode maps a grpc code to the matching http status code.
+func StatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		// 499 Client Closed Request is not part of net/http.
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CodeName returns the canonical upper snake case name of code, e.g. INVALID_ARGUMENT.
+func CodeName(code codes.Code) string {
+	var (
+		b         strings.Builder
+		prevLower bool
+	)
+	for _, r := range code.String() {
+		isUpper := r >= 'A' && r <= 'Z'
+		if isUpper && prevLower {
+			b.WriteByte('_')
+		}
+		prevLower = !isUpper
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
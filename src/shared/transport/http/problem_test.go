@@ -0,0 +1,110 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	transporthttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+)
+
+func TestWriteStatusError(t *testing.T) {
+	for _, tc := range []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{err: status.Error(codes.InvalidArgument, "someErr"), wantStatus: http.StatusBadRequest, wantCode: "INVALID_ARGUMENT"},
+		{err: status.Error(codes.NotFound, "someErr"), wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND"},
+		{err: status.Error(codes.Unavailable, "someErr"), wantStatus: http.StatusServiceUnavailable, wantCode: "UNAVAILABLE"},
+		{err: status.Error(codes.DeadlineExceeded, "someErr"), wantStatus: http.StatusGatewayTimeout, wantCode: "DEADLINE_EXCEEDED"},
+		{err: errors.New("someErr"), wantStatus: http.StatusInternalServerError, wantCode: "UNKNOWN"},
+	} {
+		t.Run("it should write a "+tc.wantCode+" problem", func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			transporthttp.WriteStatusError(recorder, nil, tc.err)
+
+			require.Equal(t, tc.wantStatus, recorder.Result().StatusCode)
+			assert.Equal(t, transporthttp.ProblemContentType, recorder.Result().Header.Get("Content-Type"))
+
+			var p transporthttp.Problem
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&p))
+			assert.Equal(t, tc.wantStatus, p.Status)
+			assert.Equal(t, tc.wantCode, p.Code)
+			assert.Equal(t, "someErr", p.Message)
+			assert.Equal(t, http.StatusText(tc.wantStatus), p.Title)
+		})
+	}
+}
+
+func TestWriteBodyError(t *testing.T) {
+	t.Run("it should write a 413 problem because the body is too large", func(t *testing.T) {
+		var (
+			recorder = httptest.NewRecorder()
+			body     = http.MaxBytesReader(recorder, io.NopCloser(bytes.NewBufferString(`{"message":"hello"}`)), 4)
+			v        map[string]string
+		)
+
+		transporthttp.WriteBodyError(recorder, nil, json.NewDecoder(body).Decode(&v))
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Result().StatusCode)
+
+		var p transporthttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&p))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, p.Status)
+		assert.Equal(t, "INVALID_ARGUMENT", p.Code)
+		assert.Equal(t, "request body must be at most 4 bytes", p.Message)
+	})
+	t.Run("it should write a 400 problem because the body is malformed", func(t *testing.T) {
+		var (
+			recorder = httptest.NewRecorder()
+			v        map[string]string
+		)
+
+		transporthttp.WriteBodyError(recorder, nil, json.NewDecoder(bytes.NewBufferString(`{`)).Decode(&v))
+
+		require.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+
+		var p transporthttp.Problem
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&p))
+		assert.Equal(t, "malformed request body", p.Message)
+	})
+}
+
+func TestReadProblem(t *testing.T) {
+	t.Run("it should return an error because the response is not a problem", func(t *testing.T) {
+		_, err := transporthttp.ReadProblem(&http.Response{
+			StatusCode: http.StatusBadGateway,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       io.NopCloser(bytes.NewBufferString(`bad gateway`)),
+		})
+		assert.True(t, errors.Is(err, transporthttp.ErrNotAProblem))
+	})
+	t.Run("it should return the problem carried by the response", func(t *testing.T) {
+		p, err := transporthttp.ReadProblem(&http.Response{
+			StatusCode: http.StatusBadRequest,
+			Header:     http.Header{"Content-Type": []string{transporthttp.ProblemContentType + "; charset=utf-8"}},
+			Body: io.NopCloser(bytes.NewBufferString(
+				`{"title":"Bad Request","status":400,"code":"INVALID_ARGUMENT","message":"someErr","trace_id":"someTraceID"}`,
+			)),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, transporthttp.Problem{
+			Title:   "Bad Request",
+			Status:  http.StatusBadRequest,
+			Code:    "INVALID_ARGUMENT",
+			Message: "someErr",
+			TraceID: "someTraceID",
+		}, p)
+	})
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+)
+
+// RetryPolicy describes how TracingDoer retries the requests that failed transiently.
+type RetryPolicy struct {
+	// MaxAttempts caps the attempts of a request, the first one included. 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff and MaxBackoff bound the exponential backoff between attempts, which is fully jittered.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// BudgetTokens and BudgetTokenRatio throttle retries the way grpc does: every transient failure takes a token,
+	// every other response gives BudgetTokenRatio tokens back and retries stop while half of the tokens are missing.
+	BudgetTokens     float64
+	BudgetTokenRatio float64
+}
+
+// DefaultRetryPolicy makes up to 3 attempts, backing off from 100ms up to 2s.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:      3,
+	InitialBackoff:   100 * time.Millisecond,
+	MaxBackoff:       2 * time.Second,
+	BudgetTokens:     10,
+	BudgetTokenRatio: 0.1,
+}
+
+// RetryPolicyFromEnv reads the retry policy from the HTTP_CLIENT_MAX_ATTEMPTS, HTTP_CLIENT_INITIAL_BACKOFF,
+// HTTP_CLIENT_MAX_BACKOFF, HTTP_CLIENT_RETRY_BUDGET_TOKENS and HTTP_CLIENT_RETRY_BUDGET_RATIO environment variables.
+// They are all optional and default to DefaultRetryPolicy.
+func RetryPolicyFromEnv() (RetryPolicy, error) {
+	policy := DefaultRetryPolicy
+
+	if v, ok := os.LookupEnv("HTTP_CLIENT_MAX_ATTEMPTS"); ok {
+		maxAttempts, err := strconv.Atoi(v)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("could not parse HTTP_CLIENT_MAX_ATTEMPTS: %w", err)
+		}
+		policy.MaxAttempts = maxAttempts
+	}
+
+	for k, dst := range map[string]*time.Duration{
+		"HTTP_CLIENT_INITIAL_BACKOFF": &policy.InitialBackoff,
+		"HTTP_CLIENT_MAX_BACKOFF":     &policy.MaxBackoff,
+	} {
+		v, ok := os.LookupEnv(k)
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("could not parse %s: %w", k, err)
+		}
+		*dst = d
+	}
+
+	for k, dst := range map[string]*float64{
+		"HTTP_CLIENT_RETRY_BUDGET_TOKENS": &policy.BudgetTokens,
+		"HTTP_CLIENT_RETRY_BUDGET_RATIO":  &policy.BudgetTokenRatio,
+	} {
+		v, ok := os.LookupEnv(k)
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("could not parse %s: %w", k, err)
+		}
+		*dst = f
+	}
+
+	return policy, nil
+}
+
+func (p RetryPolicy) validate() error {
+	switch {
+	case p.MaxAttempts < 1:
+		return fmt.Errorf("max attempts must be at least 1, got %d", p.MaxAttempts)
+	case p.InitialBackoff < 0:
+		return fmt.Errorf("initial backoff cannot be negative, got %s", p.InitialBackoff)
+	case p.MaxBackoff < p.InitialBackoff:
+		return fmt.Errorf("max backoff %s cannot be shorter than initial backoff %s", p.MaxBackoff, p.InitialBackoff)
+	case p.BudgetTokens <= 0:
+		return fmt.Errorf("budget tokens must be greater than 0, got %v", p.BudgetTokens)
+	case p.BudgetTokenRatio < 0:
+		return fmt.Errorf("budget token ratio cannot be negative, got %v", p.BudgetTokenRatio)
+	}
+	return nil
+}
+
+// backoff returns the jittered delay before the attempt following the given one.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// isRetryable reports whether req can be sent again: its method must be idempotent, or it must carry
+// an idempotency key, and its body must be replayable.
+func isRetryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get(idempotency.HeaderName) != ""
+}
+
+// isTransientFailure reports whether an attempt failed in a way that a later attempt may not.
+func isTransientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// retryBudget throttles retries so that they don't pile up on a struggling server.
+type retryBudget struct {
+	mu        sync.Mutex
+	maxTokens float64
+	ratio     float64
+	tokens    float64
+}
+
+func newRetryBudget(maxTokens, ratio float64) *retryBudget {
+	return &retryBudget{
+		maxTokens: maxTokens,
+		ratio:     ratio,
+		tokens:    maxTokens,
+	}
+}
+
+// record accounts for the outcome of an attempt.
+func (b *retryBudget) record(transientFailure bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if transientFailure {
+		b.tokens--
+		if b.tokens < 0 {
+			b.tokens = 0
+		}
+		return
+	}
+
+	b.tokens += b.ratio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
+
+// allows reports whether a retry can be made.
+func (b *retryBudget) allows() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.tokens > b.maxTokens/2
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+const attemptTag = "http.attempt"
+
+// TracingDoer decorates a Doer tracing every attempt of a request in a client span, child of the span carried
+// by the request context, and retrying the requests that can be sent again as described by a RetryPolicy.
+// Span contexts are injected into the headers of each attempt, whose span ends once the response headers are in.
+type TracingDoer struct {
+	doer   Doer
+	tracer opentracing.Tracer
+	policy RetryPolicy
+	budget *retryBudget
+}
+
+// NewTracingDoer returns a new TracingDoer sending requests through doer.
+func NewTracingDoer(doer Doer, tracer opentracing.Tracer, policy RetryPolicy) (TracingDoer, error) {
+	switch {
+	case doer == nil:
+		return TracingDoer{}, errors.New("doer cannot be nil")
+	case tracer == nil:
+		return TracingDoer{}, errors.New("tracer cannot be nil")
+	}
+
+	if err := policy.validate(); err != nil {
+		return TracingDoer{}, fmt.Errorf("invalid retry policy: %w", err)
+	}
+
+	return TracingDoer{
+		doer:   doer,
+		tracer: tracer,
+		policy: policy,
+		budget: newRetryBudget(policy.BudgetTokens, policy.BudgetTokenRatio),
+	}, nil
+}
+
+// Do sends req, retrying it with a jittered exponential backoff when it fails transiently, i.e. it can't be sent
+// or it's answered with 429, 502, 503 or 504, as long as the attempts and the retry budget allow it.
+// Only requests with an idempotent method or an idempotency key, and a replayable body, are retried.
+func (d TracingDoer) Do(req *http.Request) (*http.Response, error) {
+	retryable := isRetryable(req)
+
+	for attempt := 1; ; attempt++ {
+		r, err := attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := d.send(r, attempt)
+
+		transientFailure := isTransientFailure(resp, err) && req.Context().Err() == nil
+		d.budget.record(transientFailure)
+
+		if !transientFailure || !retryable || attempt == d.policy.MaxAttempts || !d.budget.allows() {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), d.policy.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends a single attempt of a request within its own span.
+func (d TracingDoer) send(req *http.Request, attempt int) (*http.Response, error) {
+	opts := []opentracing.StartSpanOption{ext.SpanKindRPCClient}
+	if parent := opentracing.SpanFromContext(req.Context()); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+
+	span := d.tracer.StartSpan("HTTP "+req.Method, opts...)
+	defer span.Finish()
+
+	ext.Component.Set(span, component)
+	ext.HTTPMethod.Set(span, req.Method)
+	ext.HTTPUrl.Set(span, req.URL.String())
+	ext.PeerHostname.Set(span, req.URL.Hostname())
+	span.SetTag(attemptTag, attempt)
+
+	if err := d.tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header)); err != nil {
+		log.Println(fmt.Sprintf("could not inject tracing headers: %s", err))
+	}
+
+	resp, err := d.doer.Do(req)
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		tracing.SetHTTPError(span, resp.StatusCode, errors.New(resp.Status))
+		return resp, nil
+	}
+
+	ext.HTTPStatusCode.Set(span, uint16(resp.StatusCode))
+
+	return resp, nil
+}
+
+// attemptRequest returns a copy of req for the given attempt, with a fresh body for the attempts following the first.
+// Copies keep the tracing headers of an attempt from leaking into the others.
+func attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if attempt == 1 || req.GetBody == nil {
+		return r, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("could not replay request body: %w", err)
+	}
+	r.Body = body
+
+	return r, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,246 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	transporthttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+	transporthttpmock "github.com/andream16/go-opentracing-example/src/test/mock/transport/http"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+var someRetryPolicy = transporthttp.RetryPolicy{
+	MaxAttempts:      3,
+	InitialBackoff:   time.Millisecond,
+	MaxBackoff:       time.Millisecond,
+	BudgetTokens:     10,
+	BudgetTokenRatio: 0.1,
+}
+
+func response(statusCode int) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+	}
+}
+
+func TestNewTracingDoer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("it should return an error because the doer is nil", func(t *testing.T) {
+		_, err := transporthttp.NewTracingDoer(nil, recorder.New(), someRetryPolicy)
+		require.Error(t, err)
+	})
+	t.Run("it should return an error because the tracer is nil", func(t *testing.T) {
+		_, err := transporthttp.NewTracingDoer(transporthttpmock.NewMockDoer(ctrl), nil, someRetryPolicy)
+		require.Error(t, err)
+	})
+	t.Run("it should return an error because the retry policy is invalid", func(t *testing.T) {
+		for _, policy := range []transporthttp.RetryPolicy{
+			{MaxAttempts: 0, BudgetTokens: 1},
+			{MaxAttempts: 1, InitialBackoff: time.Second, MaxBackoff: time.Millisecond, BudgetTokens: 1},
+			{MaxAttempts: 1, BudgetTokens: 0},
+		} {
+			_, err := transporthttp.NewTracingDoer(transporthttpmock.NewMockDoer(ctrl), recorder.New(), policy)
+			assert.Error(t, err, policy)
+		}
+	})
+}
+
+func TestTracingDoer_Do(t *testing.T) {
+	t.Run("it should trace the request in a client span child of the span in the context", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			tracer   = recorder.New()
+			mockDoer = transporthttpmock.NewMockDoer(ctrl)
+			parent   = tracer.StartSpan("parent")
+		)
+
+		doer, err := transporthttp.NewTracingDoer(mockDoer, tracer, someRetryPolicy)
+		require.NoError(t, err)
+
+		req, err := http.NewRequestWithContext(
+			opentracing.ContextWithSpan(context.Background(), parent),
+			http.MethodGet,
+			"http://someHost:8080/todos",
+			nil,
+		)
+		require.NoError(t, err)
+
+		mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			spanCtx, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
+			require.NoError(t, err)
+			assert.NotNil(t, spanCtx)
+			return response(http.StatusOK), nil
+		}).Times(1)
+
+		resp, err := doer.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, req.Header, "the request of the caller should be left untouched")
+
+		parent.Finish()
+
+		var (
+			spans = tracer.FinishedSpans()
+			span  = traceassert.Span(t, spans, "HTTP GET")
+		)
+
+		traceassert.ChildOf(t, span, traceassert.Span(t, spans, "parent"))
+		assert.Equal(t, map[string]interface{}{
+			string(ext.SpanKind):       ext.SpanKindRPCClientEnum,
+			string(ext.Component):      "net/http",
+			string(ext.HTTPMethod):     http.MethodGet,
+			string(ext.HTTPUrl):        "http://someHost:8080/todos",
+			string(ext.PeerHostname):   "someHost",
+			string(ext.HTTPStatusCode): uint16(http.StatusOK),
+			"http.attempt":             1,
+		}, span.Tags)
+	})
+	t.Run("it should retry a request carrying an idempotency key, replaying its body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var (
+			tracer   = recorder.New()
+			mockDoer = transporthttpmock.NewMockDoer(ctrl)
+			bodies   []string
+		)
+
+		doer, err := transporthttp.NewTracingDoer(mockDoer, tracer, someRetryPolicy)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "http://someHost/todos", bytes.NewBufferString(`{"message":"hello"}`))
+		require.NoError(t, err)
+		req.Header.Set(idempotency.HeaderName, "someKey")
+
+		record := func(r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(b))
+		}
+
+		gomock.InOrder(
+			mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+				record(r)
+				return response(http.StatusServiceUnavailable), nil
+			}).Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+				record(r)
+				return nil, errors.New("someErr")
+			}).Times(1),
+			mockDoer.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+				record(r)
+				return response(http.StatusOK), nil
+			}).Times(1),
+		)
+
+		resp, err := doer.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{`{"message":"hello"}`, `{"message":"hello"}`, `{"message":"hello"}`}, bodies)
+
+		spans := traceassert.Spans(tracer.FinishedSpans(), "HTTP POST")
+		require.Len(t, spans, 3)
+		traceassert.HasTag(t, spans[0], string(ext.HTTPStatusCode), uint16(http.StatusServiceUnavailable))
+		traceassert.HasTag(t, spans[0], string(ext.Error), true)
+		traceassert.HasTag(t, spans[1], "http.attempt", 2)
+		traceassert.HasLogField(t, spans[1], "message", "someErr")
+		traceassert.HasTag(t, spans[2], "http.attempt", 3)
+		traceassert.NoTag(t, spans[2], string(ext.Error))
+	})
+	t.Run("it should not retry a request that is not idempotent", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockDoer := transporthttpmock.NewMockDoer(ctrl)
+
+		doer, err := transporthttp.NewTracingDoer(mockDoer, recorder.New(), someRetryPolicy)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "http://someHost/todos", bytes.NewBufferString(`{}`))
+		require.NoError(t, err)
+
+		mockDoer.EXPECT().Do(gomock.Any()).Return(response(http.StatusServiceUnavailable), nil).Times(1)
+
+		resp, err := doer.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+	t.Run("it should not retry a request that failed permanently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockDoer := transporthttpmock.NewMockDoer(ctrl)
+
+		doer, err := transporthttp.NewTracingDoer(mockDoer, recorder.New(), someRetryPolicy)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, "http://someHost/todos", nil)
+		require.NoError(t, err)
+
+		mockDoer.EXPECT().Do(gomock.Any()).Return(response(http.StatusBadRequest), nil).Times(1)
+
+		resp, err := doer.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+	t.Run("it should give up after the last attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockDoer := transporthttpmock.NewMockDoer(ctrl)
+
+		doer, err := transporthttp.NewTracingDoer(mockDoer, recorder.New(), someRetryPolicy)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, "http://someHost/todos", nil)
+		require.NoError(t, err)
+
+		mockDoer.EXPECT().Do(gomock.Any()).Return(nil, errors.New("someErr")).Times(3)
+
+		_, err = doer.Do(req)
+		require.Error(t, err)
+	})
+	t.Run("it should stop retrying once the retry budget is exhausted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockDoer := transporthttpmock.NewMockDoer(ctrl)
+
+		policy := someRetryPolicy
+		policy.BudgetTokens = 4
+
+		doer, err := transporthttp.NewTracingDoer(mockDoer, recorder.New(), policy)
+		require.NoError(t, err)
+
+		// The first request spends two tokens out of four, leaving none for the retries of the second one.
+		mockDoer.EXPECT().Do(gomock.Any()).Return(response(http.StatusBadGateway), nil).Times(3)
+
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest(http.MethodGet, "http://someHost/todos", nil)
+			require.NoError(t, err)
+
+			resp, err := doer.Do(req)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		}
+	})
+}
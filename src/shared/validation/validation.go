@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	validatev1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/validate/v1"
+)
+
+// FieldViolation describes a field that does not satisfy its rules.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error is returned when a message does not satisfy the rules declared on its fields.
+type Error struct {
+	Violations []FieldViolation
+}
+
+func (e Error) Error() string {
+	descriptions := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		descriptions = append(descriptions, v.Field+": "+v.Description)
+	}
+	return "invalid message: " + strings.Join(descriptions, ", ")
+}
+
+// GRPCStatus returns an InvalidArgument status carrying the violations as BadRequest details.
+func (e Error) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	withDetails, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// ViolationsFromStatus returns the field violations carried by st, if any.
+func ViolationsFromStatus(st *status.Status) []FieldViolation {
+	var violations []FieldViolation
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, v := range badRequest.FieldViolations {
+			violations = append(violations, FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+	}
+	return violations
+}
+
+// Validate checks msg against the rules declared on its fields through the validate.v1 options.
+// It returns an Error listing every violation.
+func Validate(msg proto.Message) error {
+	return validate(msg, nil)
+}
+
+// ValidateFields checks msg like Validate, but only the top level fields with the given names, such as the
+// paths of an update mask.
+func ValidateFields(msg proto.Message, names ...string) error {
+	included := make(map[protoreflect.Name]bool, len(names))
+	for _, name := range names {
+		included[protoreflect.Name(name)] = true
+	}
+	return validate(msg, included)
+}
+
+// MaxLen returns the sum of the max_len declared on the top level string fields of msg, repeated ones being counted
+// once. Fields without a max_len don't count.
+func MaxLen(msg proto.Message) int {
+	var (
+		fields = msg.ProtoReflect().Descriptor().Fields()
+		sum    int
+	)
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Kind() != protoreflect.StringKind {
+			continue
+		}
+		if rules, ok := proto.GetExtension(fd.Options(), validatev1.E_String).(*validatev1.StringRules); ok && rules != nil {
+			sum += int(rules.MaxLen)
+		}
+	}
+	return sum
+}
+
+// validate checks the fields of msg, only the top level ones in included when it's not nil.
+func validate(msg proto.Message, included map[protoreflect.Name]bool) error {
+	var violations []FieldViolation
+	validateMessage(msg.ProtoReflect(), "", included, &violations)
+	if len(violations) > 0 {
+		return Error{Violations: violations}
+	}
+	return nil
+}
+
+func validateMessage(msg protoreflect.Message, prefix string, included map[protoreflect.Name]bool, violations *[]FieldViolation) {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		var (
+			fd   = fields.Get(i)
+			name = prefix + string(fd.Name())
+		)
+
+		if included != nil && !included[fd.Name()] {
+			continue
+		}
+
+		switch {
+		case fd.IsMap():
+			continue
+		case fd.IsList():
+			if fd.Kind() != protoreflect.StringKind {
+				continue
+			}
+			list := msg.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				validateStringField(fd, list.Get(j).String(), fmt.Sprintf("%s[%d]", name, j), violations)
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			if msg.Has(fd) {
+				validateMessage(msg.Get(fd).Message(), name+".", nil, violations)
+			}
+		case fd.Kind() == protoreflect.StringKind:
+			validateStringField(fd, msg.Get(fd).String(), name, violations)
+		}
+	}
+}
+
+func validateStringField(fd protoreflect.FieldDescriptor, value, name string, violations *[]FieldViolation) {
+	rules, ok := proto.GetExtension(fd.Options(), validatev1.E_String).(*validatev1.StringRules)
+	if !ok || rules == nil {
+		return
+	}
+	if description := validateString(value, rules); description != "" {
+		*violations = append(*violations, FieldViolation{Field: name, Description: description})
+	}
+}
+
+func validateString(value string, rules *validatev1.StringRules) string {
+	if rules.Utf8 && !utf8.ValidString(value) {
+		return "must be valid UTF-8"
+	}
+
+	length := uint32(utf8.RuneCountInString(value))
+	switch {
+	case length < rules.MinLen:
+		return fmt.Sprintf("must be at least %d characters long", rules.MinLen)
+	case rules.MaxLen > 0 && length > rules.MaxLen:
+		return fmt.Sprintf("must be at most %d characters long", rules.MaxLen)
+	}
+
+	if rules.NoControlChars && strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return "must not contain control characters"
+	}
+
+	return ""
+}
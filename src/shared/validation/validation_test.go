@@ -0,0 +1,91 @@
+package validation_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/shared/validation"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		message         string
+		wantDescription string
+	}{
+		{name: "it should reject an empty message", message: "", wantDescription: "must be at least 1 characters long"},
+		{name: "it should reject a message that is too long", message: strings.Repeat("a", 1025), wantDescription: "must be at most 1024 characters long"},
+		{name: "it should reject a message that is not valid UTF-8", message: "hello \xff", wantDescription: "must be valid UTF-8"},
+		{name: "it should reject a message with control characters", message: "hello\x00", wantDescription: "must not contain control characters"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validation.Validate(&todov1.CreateRequest{Message: tc.message})
+			require.Error(t, err)
+
+			var e validation.Error
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, []validation.FieldViolation{{Field: "message", Description: tc.wantDescription}}, e.Violations)
+		})
+	}
+	t.Run("it should accept a message of multi-byte characters up to the maximum length", func(t *testing.T) {
+		assert.NoError(t, validation.Validate(&todov1.CreateRequest{Message: strings.Repeat("é", 1024)}))
+	})
+	t.Run("it should report every invalid tag", func(t *testing.T) {
+		err := validation.Validate(&todov1.CreateRequest{Message: "hello", Tags: []string{"home", "", "bad\ttag"}})
+		require.Error(t, err)
+
+		var e validation.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, []validation.FieldViolation{
+			{Field: "tags[1]", Description: "must be at least 1 characters long"},
+			{Field: "tags[2]", Description: "must not contain control characters"},
+		}, e.Violations)
+	})
+	t.Run("it should ignore fields without rules", func(t *testing.T) {
+		assert.NoError(t, validation.Validate(&todov1.GetTodoRequest{}))
+	})
+}
+
+func TestValidateFields(t *testing.T) {
+	t.Run("it should ignore the fields that are not listed", func(t *testing.T) {
+		assert.NoError(t, validation.ValidateFields(&todov1.UpdateTodoRequest{Id: "1", Completed: true}, "completed"))
+	})
+	t.Run("it should report the violations of the listed fields", func(t *testing.T) {
+		err := validation.ValidateFields(&todov1.UpdateTodoRequest{Id: "1", Title: "bad\ttitle"}, "title", "completed")
+		require.Error(t, err)
+
+		var e validation.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, []validation.FieldViolation{{Field: "title", Description: "must not contain control characters"}}, e.Violations)
+	})
+}
+
+func TestMaxLen(t *testing.T) {
+	t.Run("it should sum the max_len of the string fields", func(t *testing.T) {
+		// message, title, description and tags.
+		assert.Equal(t, 1024+200+4096+64, validation.MaxLen(&todov1.CreateRequest{}))
+	})
+	t.Run("it should return zero because no field declares a max_len", func(t *testing.T) {
+		assert.Zero(t, validation.MaxLen(&todov1.GetTodoRequest{}))
+	})
+}
+
+func TestError_GRPCStatus(t *testing.T) {
+	t.Run("it should return an invalid argument status carrying the field violations", func(t *testing.T) {
+		err := validation.Validate(&todov1.UpdateTodoRequest{Id: "someID"})
+		require.Error(t, err)
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, []validation.FieldViolation{
+			{Field: "message", Description: "must be at least 1 characters long"},
+		}, validation.ViolationsFromStatus(st))
+	})
+}
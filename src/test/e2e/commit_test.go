@@ -0,0 +1,79 @@
+package e2e_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	transportkafka "github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+)
+
+func TestConsumer_Commit(t *testing.T) {
+	t.Run("it should commit the message once its todo is created", func(t *testing.T) {
+		h := newHarness(t)
+
+		created := h.createTodo(t, "someMessage")
+
+		require.Eventually(t, func() bool {
+			_, ok := h.db.todo(created.ID)
+			return ok && h.topic.committedOffset() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+	t.Run("it should process the message again without committing it because it could be neither retried nor dead-lettered", func(t *testing.T) {
+		h := newHarness(t)
+		for i := 0; i < 3; i++ {
+			h.db.failNext("create_todos", errors.New("connection reset by peer"))
+			h.retries[0].failNext(errors.New("not enough replicas"))
+			h.deadLetters.failNext(errors.New("not enough replicas"))
+		}
+
+		created := h.createTodo(t, "someMessage")
+
+		require.Eventually(t, func() bool {
+			_, ok := h.db.todo(created.ID)
+			return ok && h.topic.committedOffset() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, operation.StatusSucceeded, h.db.operation(created.OperationID))
+		assert.Equal(t, int64(0), h.retries[0].committedOffset())
+		assert.Equal(t, int64(0), h.deadLetters.committedOffset())
+	})
+	t.Run("it should store the offset of the message along with its todo in exactly-once mode", func(t *testing.T) {
+		h := newHarness(t, withCommitMode(transportkafka.CommitModeExactlyOnce))
+
+		created := h.createTodo(t, "someMessage")
+
+		require.Eventually(t, func() bool {
+			_, ok := h.db.todo(created.ID)
+			return ok && h.topic.committedOffset() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		next, ok := h.db.offset(consumerGroup, h.topic.name, 0)
+		require.True(t, ok)
+		assert.Equal(t, int64(1), next)
+	})
+	t.Run("it should store the offsets of the retried message and of its retry in exactly-once mode", func(t *testing.T) {
+		h := newHarness(t, withCommitMode(transportkafka.CommitModeExactlyOnce))
+		h.db.failNext("create_todos_with_offset", errors.New("connection reset by peer"))
+
+		created := h.createTodo(t, "someMessage")
+
+		require.Eventually(t, func() bool {
+			_, ok := h.db.todo(created.ID)
+			return ok && h.retries[0].committedOffset() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		next, ok := h.db.offset(consumerGroup, h.topic.name, 0)
+		require.True(t, ok)
+		assert.Equal(t, int64(1), next)
+
+		next, ok = h.db.offset(consumerGroup, h.retries[0].name, 0)
+		require.True(t, ok)
+		assert.Equal(t, int64(1), next)
+		assert.Equal(t, operation.StatusSucceeded, h.db.operation(created.OperationID))
+	})
+}
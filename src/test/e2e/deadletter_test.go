@@ -0,0 +1,85 @@
+package e2e_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	transportkafka "github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+func TestConsumer_DeadLetter(t *testing.T) {
+	t.Run("it should dead-letter the message that could not be deserialised, continuing its trace", func(t *testing.T) {
+		h := newHarness(t)
+
+		var (
+			producerSpan = h.tracer.StartSpan("producer")
+			carrier      = opentracing.TextMapCarrier{}
+		)
+		require.NoError(t, h.tracer.Inject(producerSpan.Context(), opentracing.TextMap, carrier))
+		producerSpan.Finish()
+
+		headers := []sarama.RecordHeader{{Key: []byte("someHeader"), Value: []byte("someValue")}}
+		for k, v := range carrier {
+			headers = append(headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+		}
+
+		require.NoError(t, h.topic.SendMessage(&sarama.ProducerMessage{
+			Topic:   h.topic.name,
+			Key:     sarama.StringEncoder("someKey"),
+			Value:   sarama.StringEncoder("not a todo"),
+			Headers: headers,
+		}))
+
+		var deadLetter *sarama.ConsumerMessage
+		select {
+		case deadLetter = <-h.deadLetters.messages:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "the message was not dead-lettered")
+		}
+
+		assert.Equal(t, "someKey", string(deadLetter.Key))
+		assert.Equal(t, "not a todo", string(deadLetter.Value))
+
+		deadLetterHeaders := make(map[string]string, len(deadLetter.Headers))
+		for _, header := range deadLetter.Headers {
+			deadLetterHeaders[string(header.Key)] = string(header.Value)
+		}
+
+		assert.Equal(t, "someValue", deadLetterHeaders["someHeader"])
+		assert.Equal(t, "1", deadLetterHeaders[transportkafka.AttemptsHeaderKey])
+		assert.Equal(t, "todos", deadLetterHeaders[transportkafka.OriginTopicHeaderKey])
+		assert.Equal(t, "0", deadLetterHeaders[transportkafka.OriginPartitionHeaderKey])
+		assert.Equal(t, "0", deadLetterHeaders[transportkafka.OriginOffsetHeaderKey])
+		assert.Contains(t, deadLetterHeaders[transportkafka.DeadLetterErrorHeaderKey], "could not deserialise todo")
+
+		require.Eventually(t, func() bool {
+			return h.topic.committedOffset() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		var (
+			spans          = h.tracer.FinishedSpans()
+			consumerSpan   = traceassert.Span(t, spans, "todo_consumer")
+			deadLetterSpan = traceassert.Span(t, spans, "todo_dead_letter")
+		)
+
+		traceassert.FollowsFrom(t, consumerSpan, traceassert.Span(t, spans, "producer"))
+		traceassert.FollowsFrom(t, deadLetterSpan, consumerSpan)
+		traceassert.SameTrace(t, consumerSpan, deadLetterSpan)
+		traceassert.HasTag(t, consumerSpan, string(ext.Error), true)
+		traceassert.HasTag(t, deadLetterSpan, string(ext.MessageBusDestination), "todos.dead-letter")
+
+		// The dead-lettered record carries the context of the dead-letter span, for its consumers to follow from it.
+		spanCtx, err := h.tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(deadLetterHeaders))
+		require.NoError(t, err)
+		consumerOfDeadLetter := h.tracer.StartSpan("dead_letter_consumer", opentracing.FollowsFrom(spanCtx))
+		consumerOfDeadLetter.Finish()
+		traceassert.FollowsFrom(t, traceassert.Span(t, h.tracer.FinishedSpans(), "dead_letter_consumer"), deadLetterSpan)
+	})
+}
@@ -0,0 +1,211 @@
+package e2e_test
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	grpcoperationrepository "github.com/andream16/go-opentracing-example/src/grpc-server/operation/repository"
+	"github.com/andream16/go-opentracing-example/src/grpc-server/outbox/relay"
+	outboxrepository "github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	grpctodorepository "github.com/andream16/go-opentracing-example/src/grpc-server/todo/repository"
+	grpctodo "github.com/andream16/go-opentracing-example/src/grpc-server/transport/grpc/todo"
+	initiatorhttp "github.com/andream16/go-opentracing-example/src/http-server-initiator/transport/http"
+	receiverhttp "github.com/andream16/go-opentracing-example/src/http-server-receiver/transport/http"
+	offsetrepository "github.com/andream16/go-opentracing-example/src/kafka-consumer/offset/repository"
+	consumeroperationrepository "github.com/andream16/go-opentracing-example/src/kafka-consumer/operation/repository"
+	consumertodorepository "github.com/andream16/go-opentracing-example/src/kafka-consumer/todo/repository"
+	transportkafka "github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	sharedhttp "github.com/andream16/go-opentracing-example/src/shared/transport/http"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+)
+
+// consumerGroup is the group of the consumer booted by the harness.
+const consumerGroup = "kafka-consumer"
+
+// harness boots the four services in process, wired as in their main packages.
+// Kafka and postgres are replaced by in-memory stand-ins and all services share a single recording tracer.
+type harness struct {
+	tracer      *recorder.Tracer
+	db          *database
+	topic       *topic
+	retries     []*topic
+	deadLetters *topic
+	initiator   *httptest.Server
+	todos       todov1.TodoServiceClient
+}
+
+// harnessOption customises the services booted by a harness.
+type harnessOption func(*transportkafka.CommitPolicy)
+
+// withCommitMode makes the consumer commit in the given mode rather than synchronously.
+func withCommitMode(mode transportkafka.CommitMode) harnessOption {
+	return func(policy *transportkafka.CommitPolicy) {
+		policy.Mode = mode
+	}
+}
+
+func newHarness(t *testing.T, opts ...harnessOption) *harness {
+	t.Helper()
+
+	var (
+		tracer = recorder.New()
+		db     = newDatabase(tracer)
+		tp     = newTopic("todos")
+		dlt    = newTopic("todos.dead-letter")
+	)
+
+	// grpc-server
+	todoRepo, err := grpctodorepository.New(db)
+	require.NoError(t, err)
+
+	operations, err := grpcoperationrepository.New(db)
+	require.NoError(t, err)
+
+	outbox, err := outboxrepository.New(db, db)
+	require.NoError(t, err)
+
+	relayOutbox, err := outboxrepository.New(db.untraced(), db.untraced())
+	require.NoError(t, err)
+
+	outboxRelay, err := relay.New(relayOutbox, db.untraced(), tp, tracer, 5*time.Millisecond, 100)
+	require.NoError(t, err)
+
+	service, err := grpctodo.NewService(tp.name, db, outbox, todoRepo, operations, noChanges{}, tracer)
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(
+		grpc.UnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer)),
+		grpc.StreamInterceptor(otgrpc.OpenTracingStreamServerInterceptor(tracer)),
+	)
+	todov1.RegisterTodoServiceServer(grpcSrv, service)
+
+	lis := bufconn.Listen(1 << 20)
+	go grpcSrv.Serve(lis)
+	t.Cleanup(grpcSrv.Stop)
+
+	// http-server-receiver
+	conn, err := grpc.Dial(
+		"bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(otgrpc.OpenTracingClientInterceptor(tracer)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	receiverHandler, err := receiverhttp.NewHandler(todov1.NewTodoServiceClient(conn), tracer)
+	require.NoError(t, err)
+
+	receiver := httptest.NewServer(receiverHandler.Router())
+	t.Cleanup(receiver.Close)
+
+	// http-server-initiator
+	receiverDoer, err := sharedhttp.NewTracingDoer(receiver.Client(), tracer, sharedhttp.DefaultRetryPolicy)
+	require.NoError(t, err)
+
+	initiatorHandler, err := initiatorhttp.NewHandler(receiver.URL, receiverDoer, tracer, tracing.DefaultBaggageHeaders)
+	require.NoError(t, err)
+
+	initiator := httptest.NewServer(initiatorHandler.Router())
+	t.Cleanup(initiator.Close)
+
+	// kafka-consumer
+	creator, err := consumertodorepository.New(db)
+	require.NoError(t, err)
+
+	operationRecorder, err := consumeroperationrepository.New(db)
+	require.NoError(t, err)
+
+	commitPolicy := transportkafka.CommitPolicy{
+		Mode:        transportkafka.CommitModeSync,
+		MaxFailures: 2,
+		Backoff:     time.Millisecond,
+		PauseFor:    10 * time.Millisecond,
+		Group:       consumerGroup,
+	}
+	for _, opt := range opts {
+		opt(&commitPolicy)
+	}
+
+	offsets, err := offsetrepository.New(db, db)
+	require.NoError(t, err)
+
+	retryTiers, err := transportkafka.ParseRetryTiers(tp.name, "10ms,20ms")
+	require.NoError(t, err)
+
+	var (
+		consumed = []*topic{tp}
+		retries  []*topic
+	)
+	for _, tier := range retryTiers {
+		retries = append(retries, newTopic(tier.Topic))
+	}
+	consumed = append(consumed, retries...)
+
+	brk := newBroker(append(consumed, dlt)...)
+
+	retrier, err := transportkafka.NewRetryPublisher(retryTiers, brk, tracer)
+	require.NoError(t, err)
+
+	deadLetterer, err := transportkafka.NewDeadLetterPublisher(dlt.name, brk, tracer)
+	require.NoError(t, err)
+
+	consumer, err := transportkafka.NewConsumer(creator, operationRecorder, retrier, deadLetterer, tracer, commitPolicy, offsets)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := outboxRelay.Run(ctx); err != nil {
+			t.Errorf("could not relay the outbox: %v", err)
+		}
+	}()
+	for _, c := range consumed {
+		wg.Add(1)
+		go func(c *topic) {
+			defer wg.Done()
+			if err := c.consume(ctx, consumer); err != nil {
+				t.Errorf("could not consume %s: %v", c.name, err)
+			}
+		}(c)
+	}
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	return &harness{
+		tracer:      tracer,
+		db:          db,
+		topic:       tp,
+		retries:     retries,
+		deadLetters: dlt,
+		initiator:   initiator,
+		todos:       todov1.NewTodoServiceClient(conn),
+	}
+}
+
+// noChanges is a watcher.Subscriber that never receives changes, as creating todos doesn't watch them.
+type noChanges struct{}
+
+func (noChanges) Subscribe() (<-chan todo.Change, func()) {
+	ch := make(chan todo.Change)
+	close(ch)
+	return ch, func() {}
+}
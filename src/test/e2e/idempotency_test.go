@@ -0,0 +1,59 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+)
+
+func TestTodo_Idempotency(t *testing.T) {
+	t.Run("it should return the id of the todo created by the first request when retried with the same key", func(t *testing.T) {
+		h := newHarness(t)
+
+		first := h.createTodoWithKey(t, "someMessage", "someKey")
+
+		require.Eventually(t, func() bool {
+			return h.db.operation(first.OperationID) == operation.StatusSucceeded
+		}, 5*time.Second, 10*time.Millisecond)
+
+		retried := h.createTodoWithKey(t, "someMessage", "someKey")
+		assert.Equal(t, first.ID, retried.ID)
+		assert.Equal(t, first.OperationID, retried.OperationID)
+
+		// The event of the retry is consumed too, without creating another todo.
+		require.Eventually(t, func() bool {
+			return h.topic.committedOffset() == 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		resp, err := h.todos.GetTodo(context.Background(), &todov1.GetTodoRequest{Id: retried.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "someMessage", resp.Todo.Message)
+		assert.Equal(t, operation.StatusSucceeded, h.db.operation(retried.OperationID))
+	})
+	t.Run("it should reject the key reused for a different todo", func(t *testing.T) {
+		h := newHarness(t)
+
+		h.createTodoWithKey(t, "someMessage", "someKey")
+
+		req, err := http.NewRequest(http.MethodPost, h.initiator.URL+"/initiator/todo", strings.NewReader(`{"message":"otherMessage"}`))
+		require.NoError(t, err)
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotency.HeaderName, "someKey")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
@@ -0,0 +1,187 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Shopify/sarama"
+)
+
+// topic is an in-memory stand-in for a single partition kafka topic.
+// Produced messages are handed to the consumer group handler claiming it.
+type topic struct {
+	name     string
+	messages chan *sarama.ConsumerMessage
+
+	mu         sync.Mutex
+	nextOffset int64
+	marked     int64
+	committed  int64
+	failures   []error
+}
+
+func newTopic(name string) *topic {
+	return &topic{
+		name:     name,
+		messages: make(chan *sarama.ConsumerMessage, 100),
+	}
+}
+
+func (t *topic) SendMessage(message *sarama.ProducerMessage) error {
+	return t.SendMessages([]*sarama.ProducerMessage{message})
+}
+
+func (t *topic) SendMessages(messages []*sarama.ProducerMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.failures) > 0 {
+		err := t.failures[0]
+		t.failures = t.failures[1:]
+		return err
+	}
+
+	for _, message := range messages {
+		key, err := message.Key.Encode()
+		if err != nil {
+			return err
+		}
+		value, err := message.Value.Encode()
+		if err != nil {
+			return err
+		}
+
+		headers := make([]*sarama.RecordHeader, 0, len(message.Headers))
+		for i := range message.Headers {
+			headers = append(headers, &message.Headers[i])
+		}
+
+		t.messages <- &sarama.ConsumerMessage{
+			Topic:   t.name,
+			Key:     key,
+			Value:   value,
+			Headers: headers,
+			Offset:  t.nextOffset,
+		}
+		t.nextOffset++
+	}
+
+	return nil
+}
+
+// broker is an in-memory stand-in for a kafka cluster, routing produced messages to their topic.
+type broker map[string]*topic
+
+func newBroker(topics ...*topic) broker {
+	b := make(broker, len(topics))
+	for _, t := range topics {
+		b[t.name] = t
+	}
+	return b
+}
+
+func (b broker) SendMessage(message *sarama.ProducerMessage) error {
+	return b.SendMessages([]*sarama.ProducerMessage{message})
+}
+
+func (b broker) SendMessages(messages []*sarama.ProducerMessage) error {
+	for _, message := range messages {
+		t, ok := b[message.Topic]
+		if !ok {
+			return fmt.Errorf("unknown topic %s", message.Topic)
+		}
+		if err := t.SendMessage(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// consume runs handler on the topic until ctx is done.
+func (t *topic) consume(ctx context.Context, handler sarama.ConsumerGroupHandler) error {
+	messages := make(chan *sarama.ConsumerMessage)
+	go func() {
+		defer close(messages)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message := <-t.messages:
+				messages <- message
+			}
+		}
+	}()
+
+	sess := session{ctx: ctx, topic: t}
+	if err := handler.Setup(sess); err != nil {
+		return err
+	}
+	defer handler.Cleanup(sess)
+
+	return handler.ConsumeClaim(sess, claim{topic: t, messages: messages})
+}
+
+// failNext makes the next produce to the topic fail with err.
+func (t *topic) failNext(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failures = append(t.failures, err)
+}
+
+// committedOffset returns the offset of the next message to be consumed by the group after a restart.
+func (t *topic) committedOffset() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.committed
+}
+
+type claim struct {
+	topic    *topic
+	messages <-chan *sarama.ConsumerMessage
+}
+
+func (c claim) Topic() string                            { return c.topic.name }
+func (c claim) Partition() int32                         { return 0 }
+func (c claim) InitialOffset() int64                     { return c.topic.committedOffset() }
+func (c claim) HighWaterMarkOffset() int64               { return 0 }
+func (c claim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+type session struct {
+	ctx   context.Context
+	topic *topic
+}
+
+func (s session) Claims() map[string][]int32 { return map[string][]int32{s.topic.name: {0}} }
+func (s session) MemberID() string           { return "e2e" }
+func (s session) GenerationID() int32        { return 1 }
+func (s session) Context() context.Context   { return s.ctx }
+
+func (s session) MarkOffset(_ string, _ int32, offset int64, _ string) {
+	s.topic.mu.Lock()
+	defer s.topic.mu.Unlock()
+
+	if offset > s.topic.marked {
+		s.topic.marked = offset
+	}
+}
+
+func (s session) Commit() {
+	s.topic.mu.Lock()
+	defer s.topic.mu.Unlock()
+
+	s.topic.committed = s.topic.marked
+}
+
+func (s session) ResetOffset(_ string, _ int32, offset int64, _ string) {
+	s.topic.mu.Lock()
+	defer s.topic.mu.Unlock()
+
+	s.topic.marked = offset
+}
+
+func (s session) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
@@ -0,0 +1,73 @@
+package e2e_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+func TestOutbox_Relay(t *testing.T) {
+	t.Run("it should relay the todos in order once kafka is available again", func(t *testing.T) {
+		h := newHarness(t)
+		h.topic.failNext(errors.New("not enough replicas"))
+
+		var (
+			first  = h.createTodo(t, "first")
+			second = h.createTodo(t, "second")
+		)
+
+		require.Eventually(t, func() bool {
+			_, ok := h.db.todo(second.ID)
+			return ok && h.topic.committedOffset() == 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		message, ok := h.db.todo(first.ID)
+		require.True(t, ok)
+		assert.Equal(t, "first", message)
+		assert.Equal(t, operation.StatusSucceeded, h.db.operation(first.OperationID))
+		assert.Equal(t, operation.StatusSucceeded, h.db.operation(second.OperationID))
+
+		relaySpans := traceassert.Spans(h.tracer.FinishedSpans(), "outbox_relay")
+		require.Len(t, relaySpans, 3)
+		traceassert.HasTag(t, relaySpans[0], "error", true)
+		traceassert.HasTag(t, relaySpans[0], "outbox.id", int64(1))
+		traceassert.HasTag(t, relaySpans[1], "outbox.id", int64(1))
+		traceassert.HasTag(t, relaySpans[2], "outbox.id", int64(2))
+	})
+	t.Run("it should roll the operation back when its event could not be added to the outbox", func(t *testing.T) {
+		h := newHarness(t)
+		h.db.failNext("add_outbox_messages", errors.New("someErr"))
+
+		var (
+			ctx         = metadata.AppendToOutgoingContext(context.Background(), idempotency.MetadataKey, "someKey")
+			operationID = todo.OperationIDFromKey(idempotency.Scope("someKey", "", ""))
+		)
+
+		_, err := h.todos.Create(ctx, &todov1.CreateRequest{Message: "someMessage"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Empty(t, h.db.operation(operationID))
+
+		// The retry creates the operation and relays its event as if the first attempt never happened.
+		resp, err := h.todos.Create(ctx, &todov1.CreateRequest{Message: "someMessage"})
+		require.NoError(t, err)
+		assert.Equal(t, operationID, resp.OperationId)
+
+		require.Eventually(t, func() bool {
+			return h.db.operation(operationID) == operation.StatusSucceeded
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Len(t, traceassert.Spans(h.tracer.FinishedSpans(), "outbox_relay"), 1)
+	})
+}
@@ -0,0 +1,352 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+
+	outboxrepository "github.com/andream16/go-opentracing-example/src/grpc-server/outbox/repository"
+	"github.com/andream16/go-opentracing-example/src/shared/database/postgres"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+// database is an in-memory stand-in for postgres, understanding the queries run while creating a todo.
+// Like pgxwrapper, it traces every query with a span named after it and tagged with its baggage.
+type database struct {
+	tracer opentracing.Tracer
+	*tables
+}
+
+// tables are the rows stored by the database, shared by its views.
+type tables struct {
+	mu         sync.Mutex
+	todos      map[string]string
+	operations map[string]operation.Status
+	outbox     []*outboxMessage
+	offsets    map[offsetKey]int64
+	failures   map[string][]error
+	// requestHashes are the request hashes of the operations, by operation id.
+	requestHashes map[string]string
+}
+
+// offsetKey identifies the offset of a partition stored for a consumer group.
+type offsetKey struct {
+	group, topic string
+	partition    int32
+}
+
+// outboxMessage is a message of the outbox, sent once the relay marks it.
+// Messages added by a transaction that was rolled back are kept, so that ids keep matching positions, but never relayed.
+type outboxMessage struct {
+	outboxrepository.Message
+	sent, rolledBack bool
+}
+
+// txKey is the context key of the undo log of the running transaction.
+type txKey struct{}
+
+func newDatabase(tracer opentracing.Tracer) *database {
+	return &database{
+		tracer: tracer,
+		tables: &tables{
+			todos:         make(map[string]string),
+			operations:    make(map[string]operation.Status),
+			requestHashes: make(map[string]string),
+			offsets:       make(map[offsetKey]int64),
+			failures:      make(map[string][]error),
+		},
+	}
+}
+
+// untraced returns a view of the database that doesn't trace queries, as the connections of the outbox relay.
+func (db *database) untraced() *database {
+	return &database{
+		tracer: opentracing.NoopTracer{},
+		tables: db.tables,
+	}
+}
+
+// failNext makes the next execution of the query with the given name fail with err.
+// Calling it again makes the execution following that one fail too.
+func (db *database) failNext(queryName string, err error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.failures[queryName] = append(db.failures[queryName], err)
+}
+
+// InTx runs fn within a transaction, undoing what its queries stored when it returns an error.
+// Like pgxwrapper, it joins the transaction already running with ctx, if any.
+func (db *database) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*[]func()); ok {
+		return fn(ctx)
+	}
+
+	var undo []func()
+	if err := fn(context.WithValue(ctx, txKey{}, &undo)); err != nil {
+		db.mu.Lock()
+		defer db.mu.Unlock()
+
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+		return err
+	}
+	return nil
+}
+
+// onRollback records how to undo a change stored within the transaction running with ctx, if any.
+// It must be called with the tables locked.
+func onRollback(ctx context.Context, undo func()) {
+	if log, ok := ctx.Value(txKey{}).(*[]func()); ok {
+		*log = append(*log, undo)
+	}
+}
+
+// todo returns the message of the stored todo with the given id.
+func (db *database) todo(id string) (string, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	message, ok := db.todos[id]
+	return message, ok
+}
+
+// offset returns the offset stored for the partition of topic consumed by group, if any.
+func (db *database) offset(group, topic string, partition int32) (int64, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	next, ok := db.offsets[offsetKey{group: group, topic: topic, partition: partition}]
+	return next, ok
+}
+
+// saveOffset stores the offset whose group, topic, partition and value are the trailing args, as
+// offset.UpsertCTE does. It must be called with the tables locked.
+func (db *database) saveOffset(args []interface{}) {
+	args = args[len(args)-4:]
+
+	key := offsetKey{group: args[0].(string), topic: args[1].(string), partition: args[2].(int32)}
+	if next := args[3].(int64); next > db.offsets[key] {
+		db.offsets[key] = next
+	}
+}
+
+// operation returns the status of the stored operation with the given id.
+func (db *database) operation(id string) operation.Status {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.operations[id]
+}
+
+func (db *database) Exec(ctx context.Context, queryName, _ string, args ...interface{}) error {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, db.tracer, queryName)
+	tracing.TagBaggage(span)
+	defer span.Finish()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if failures := db.failures[queryName]; len(failures) > 0 {
+		db.failures[queryName] = failures[1:]
+		return failures[0]
+	}
+
+	switch queryName {
+	case "create_todos", "create_todos_with_offset":
+		if _, ok := db.todos[args[0].(string)]; !ok {
+			db.todos[args[0].(string)] = args[1].(string)
+		}
+	case "create_todos_batch", "create_todos_batch_with_offset":
+		var todos []struct {
+			UID     string `json:"uid"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(args[0].(string)), &todos); err != nil {
+			return err
+		}
+		for _, t := range todos {
+			if _, ok := db.todos[t.UID]; !ok {
+				db.todos[t.UID] = t.Message
+			}
+		}
+	case "save_consumer_offset":
+		db.saveOffset(args)
+	case "succeed_operation":
+		db.operations[args[0].(string)] = operation.StatusSucceeded
+	case "succeed_operations":
+		for _, id := range args[0].([]string) {
+			db.operations[id] = operation.StatusSucceeded
+		}
+	case "fail_operation":
+		db.operations[args[0].(string)] = operation.StatusFailed
+	case "add_outbox_messages":
+		var messages []outboxrepository.Message
+		if err := json.Unmarshal([]byte(args[0].(string)), &messages); err != nil {
+			return err
+		}
+		for _, m := range messages {
+			m.ID = int64(len(db.outbox) + 1)
+			added := &outboxMessage{Message: m}
+			db.outbox = append(db.outbox, added)
+			onRollback(ctx, func() { added.rolledBack = true })
+		}
+	case "mark_outbox_message_sent":
+		marked := db.outbox[args[0].(int64)-1]
+		marked.sent = true
+		onRollback(ctx, func() { marked.sent = false })
+	default:
+		return fmt.Errorf("unexpected query %s", queryName)
+	}
+
+	// The todos and the offset stored with them are stored together, or not at all.
+	if strings.HasSuffix(queryName, "_with_offset") {
+		db.saveOffset(args)
+	}
+
+	return nil
+}
+
+func (db *database) Query(ctx context.Context, queryName, _ string, args ...interface{}) (postgres.Rows, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, db.tracer, queryName)
+	tracing.TagBaggage(span)
+	defer span.Finish()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if failures := db.failures[queryName]; len(failures) > 0 {
+		db.failures[queryName] = failures[1:]
+		return nil, failures[0]
+	}
+
+	switch queryName {
+	case "pending_outbox_messages":
+		var pending rows
+		for _, m := range db.outbox {
+			if len(pending) == args[0].(int) {
+				break
+			}
+			if m.sent || m.rolledBack {
+				continue
+			}
+			headers, err := json.Marshal(m.Headers)
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, row{values: []interface{}{m.ID, m.Topic, m.Key, m.Value, string(headers)}})
+		}
+		return &pending, nil
+	case "get_consumer_offsets":
+		var stored rows
+		for key, next := range db.offsets {
+			if key.group == args[0].(string) && key.topic == args[1].(string) {
+				stored = append(stored, row{values: []interface{}{key.partition, next}})
+			}
+		}
+		return &stored, nil
+	default:
+		return nil, fmt.Errorf("unexpected query %s", queryName)
+	}
+}
+
+func (db *database) QueryRow(ctx context.Context, queryName, _ string, args ...interface{}) postgres.Row {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, db.tracer, queryName)
+	tracing.TagBaggage(span)
+	defer span.Finish()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	switch queryName {
+	case "create_operation":
+		// Operations created again are left as they are, unless they failed for the same request.
+		var (
+			id          = args[0].(string)
+			requestHash = args[4].(string)
+		)
+		status, ok := db.operations[id]
+		if ok && (status != operation.StatusFailed || db.requestHashes[id] != requestHash) {
+			return row{err: postgres.ErrNoRows}
+		}
+		db.operations[id] = operation.StatusPending
+		db.requestHashes[id] = requestHash
+		onRollback(ctx, func() {
+			if ok {
+				db.operations[id] = status
+				return
+			}
+			delete(db.operations, id)
+			delete(db.requestHashes, id)
+		})
+		return row{values: []interface{}{args[0]}}
+	case "get_operation_request_hash":
+		return row{values: []interface{}{db.requestHashes[args[0].(string)]}}
+	case "get_todo":
+		message, ok := db.todos[args[0].(string)]
+		if !ok {
+			return row{err: postgres.ErrNoRows}
+		}
+		var noDueAt *time.Time
+		return row{values: []interface{}{args[0], message, "", "", noDueAt, "", []string{}, false, time.Time{}, time.Time{}}}
+	default:
+		return row{err: fmt.Errorf("unexpected query %s", queryName)}
+	}
+}
+
+type row struct {
+	values []interface{}
+	err    error
+}
+
+func (r row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, d := range dest {
+		switch d := d.(type) {
+		case *string:
+			*d = r.values[i].(string)
+		case *int32:
+			*d = r.values[i].(int32)
+		case *int64:
+			*d = r.values[i].(int64)
+		case *[]byte:
+			*d = r.values[i].([]byte)
+		case *[]string:
+			*d = r.values[i].([]string)
+		case *bool:
+			*d = r.values[i].(bool)
+		case *time.Time:
+			*d = r.values[i].(time.Time)
+		case **time.Time:
+			*d = r.values[i].(*time.Time)
+		default:
+			return fmt.Errorf("unexpected destination %T", d)
+		}
+	}
+	return nil
+}
+
+// rows are the rows returned by a query, scanned in order.
+type rows []row
+
+func (r *rows) Next() bool {
+	return len(*r) > 0
+}
+
+func (r *rows) Scan(dest ...interface{}) error {
+	current := (*r)[0]
+	*r = (*r)[1:]
+	return current.Scan(dest...)
+}
+
+func (r *rows) Err() error { return nil }
+func (r *rows) Close()     {}
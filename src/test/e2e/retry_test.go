@@ -0,0 +1,117 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	transportkafka "github.com/andream16/go-opentracing-example/src/kafka-consumer/transport/kafka"
+	"github.com/andream16/go-opentracing-example/src/shared/idempotency"
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+func TestConsumer_Retry(t *testing.T) {
+	t.Run("it should create the todo on a retry topic because creating it failed transiently", func(t *testing.T) {
+		h := newHarness(t)
+		h.db.failNext("create_todos", errors.New("connection reset by peer"))
+
+		created := h.createTodo(t, "someMessage")
+
+		require.Eventually(t, func() bool {
+			_, ok := h.db.todo(created.ID)
+			return ok && h.retries[0].committedOffset() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, operation.StatusSucceeded, h.db.operation(created.OperationID))
+		assert.Equal(t, int64(1), h.topic.committedOffset())
+		assert.Equal(t, int64(0), h.retries[1].committedOffset())
+		assert.Equal(t, int64(0), h.deadLetters.committedOffset())
+
+		var (
+			spans         = h.tracer.FinishedSpans()
+			consumerSpans = traceassert.Spans(spans, "todo_consumer")
+			retrySpan     = traceassert.Span(t, spans, "todo_retry")
+		)
+
+		require.Len(t, consumerSpans, 2)
+		traceassert.FollowsFrom(t, retrySpan, consumerSpans[0])
+		traceassert.FollowsFrom(t, consumerSpans[1], retrySpan)
+		traceassert.SameTrace(t, consumerSpans[0], retrySpan, consumerSpans[1])
+		traceassert.HasTag(t, retrySpan, "message_bus.destination", "todos.retry.10ms")
+		traceassert.HasTag(t, retrySpan, "retry.attempt", 1)
+		assert.Empty(t, traceassert.Spans(spans, "fail_operation"), "the operation should not fail while the todo is retried")
+	})
+	t.Run("it should dead-letter the message once it's been through all the retry tiers", func(t *testing.T) {
+		h := newHarness(t)
+		for i := 0; i < 3; i++ {
+			h.db.failNext("create_todos", errors.New("connection reset by peer"))
+		}
+
+		created := h.createTodo(t, "someMessage")
+
+		var deadLetter *sarama.ConsumerMessage
+		select {
+		case deadLetter = <-h.deadLetters.messages:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "the message was not dead-lettered")
+		}
+
+		headers := make(map[string]string, len(deadLetter.Headers))
+		for _, header := range deadLetter.Headers {
+			headers[string(header.Key)] = string(header.Value)
+		}
+
+		assert.Equal(t, "3", headers[transportkafka.AttemptsHeaderKey])
+		assert.Equal(t, "todos", headers[transportkafka.OriginTopicHeaderKey])
+		assert.Equal(t, "0", headers[transportkafka.OriginOffsetHeaderKey])
+		assert.Contains(t, headers[transportkafka.DeadLetterErrorHeaderKey], "connection reset by peer")
+
+		require.Eventually(t, func() bool {
+			return h.db.operation(created.OperationID) == operation.StatusFailed
+		}, 5*time.Second, 10*time.Millisecond)
+
+		_, ok := h.db.todo(created.ID)
+		assert.False(t, ok)
+		assert.Len(t, traceassert.Spans(h.tracer.FinishedSpans(), "todo_retry"), 2)
+	})
+}
+
+// createTodo creates a todo with the given message through the initiator.
+func (h *harness) createTodo(t *testing.T, message string) todo.Created {
+	t.Helper()
+
+	return h.createTodoWithKey(t, message, "")
+}
+
+// createTodoWithKey creates a todo through the initiator, with the given idempotency key when it's not empty.
+func (h *harness) createTodoWithKey(t *testing.T, message, key string) todo.Created {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, h.initiator.URL+"/initiator/todo", strings.NewReader(`{"message":"`+message+`"}`))
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(idempotency.HeaderName, key)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var created todo.Created
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+	return created
+}
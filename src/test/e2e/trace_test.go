@@ -0,0 +1,141 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/shared/operation"
+	"github.com/andream16/go-opentracing-example/src/shared/todo"
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+const createMethod = "/go_opentracing_example.grpc_server.todo.v1.TodoService/Create"
+
+func TestCreateTodo_Trace(t *testing.T) {
+	t.Run("it should trace the creation of a todo across all services in a single trace", func(t *testing.T) {
+		h := newHarness(t)
+
+		resp, err := http.Post(h.initiator.URL+"/initiator/todo", "application/json", strings.NewReader(`{"message":"someMessage"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var created todo.Created
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+		// The todo is created asynchronously by the consumer, whose span is the last one of the trace.
+		require.Eventually(t, func() bool {
+			return len(traceassert.Spans(h.tracer.FinishedSpans(), "todo_consumer")) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		message, ok := h.db.todo(created.ID)
+		require.True(t, ok)
+		assert.Equal(t, "someMessage", message)
+		assert.Equal(t, operation.StatusSucceeded, h.db.operation(created.OperationID))
+		assert.Equal(t, int64(1), h.topic.committedOffset())
+
+		var (
+			spans          = h.tracer.FinishedSpans()
+			initiatorSpan  = traceassert.Span(t, spans, "POST /initiator/todo")
+			clientSpan     = traceassert.Span(t, spans, "HTTP POST")
+			receiverSpan   = traceassert.Span(t, spans, "POST /receiver/todo")
+			grpcClientSpan = rpcSpan(t, spans, ext.SpanKindRPCClientEnum)
+			grpcServerSpan = rpcSpan(t, spans, ext.SpanKindRPCServerEnum)
+			operationSpan  = traceassert.Span(t, spans, "create_operation")
+			outboxSpan     = traceassert.Span(t, spans, "add_outbox_messages")
+			relaySpan      = traceassert.Span(t, spans, "outbox_relay")
+			consumerSpan   = traceassert.Span(t, spans, "todo_consumer")
+			todoSpan       = traceassert.Span(t, spans, "create_todos")
+			succeedSpan    = traceassert.Span(t, spans, "succeed_operation")
+		)
+
+		traceassert.Root(t, initiatorSpan)
+		traceassert.ChildOf(t, clientSpan, initiatorSpan)
+		traceassert.ChildOf(t, receiverSpan, clientSpan)
+		traceassert.ChildOf(t, grpcClientSpan, receiverSpan)
+		traceassert.ChildOf(t, grpcServerSpan, grpcClientSpan)
+		traceassert.ChildOf(t, operationSpan, grpcServerSpan)
+		traceassert.ChildOf(t, outboxSpan, grpcServerSpan)
+		traceassert.FollowsFrom(t, relaySpan, grpcServerSpan)
+		traceassert.FollowsFrom(t, consumerSpan, relaySpan)
+		traceassert.ChildOf(t, todoSpan, consumerSpan)
+		traceassert.ChildOf(t, succeedSpan, consumerSpan)
+
+		traceassert.SameTrace(t, initiatorSpan, relaySpan, consumerSpan, todoSpan, succeedSpan)
+		traceassert.TraceLen(t, spans, initiatorSpan, 11)
+		assert.Len(t, spans, 11)
+
+		traceassert.HasTag(t, initiatorSpan, string(ext.HTTPMethod), http.MethodPost)
+		traceassert.HasTag(t, clientSpan, string(ext.HTTPStatusCode), uint16(http.StatusOK))
+		traceassert.HasTag(t, receiverSpan, string(ext.SpanKind), ext.SpanKindRPCServerEnum)
+		traceassert.HasTag(t, relaySpan, string(ext.MessageBusDestination), "todos")
+		for _, s := range spans {
+			traceassert.NoTag(t, s, string(ext.Error))
+		}
+	})
+	t.Run("it should carry the tenant and user baggage from the initiator down to the database", func(t *testing.T) {
+		h := newHarness(t)
+
+		req, err := http.NewRequest(http.MethodPost, h.initiator.URL+"/initiator/todo", strings.NewReader(`{"message":"someMessage"}`))
+		require.NoError(t, err)
+
+		req.Header.Set("X-Tenant-Id", "someTenant")
+		req.Header.Set("X-User-Id", "someUser")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		require.Eventually(t, func() bool {
+			return len(traceassert.Spans(h.tracer.FinishedSpans(), "todo_consumer")) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		spans := h.tracer.FinishedSpans()
+		for _, name := range []string{"todo_consumer", "create_operation", "add_outbox_messages", "create_todos", "succeed_operation"} {
+			s := traceassert.Span(t, spans, name)
+			traceassert.HasTag(t, s, "baggage."+tracing.BaggageTenantID, "someTenant")
+			traceassert.HasTag(t, s, "baggage."+tracing.BaggageUserID, "someUser")
+		}
+	})
+	t.Run("it should mark the initiator span as failed because the todo is not valid", func(t *testing.T) {
+		h := newHarness(t)
+
+		resp, err := http.Post(h.initiator.URL+"/initiator/todo", "application/json", strings.NewReader(`{"message":""}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		initiatorSpan := traceassert.Span(t, h.tracer.FinishedSpans(), "POST /initiator/todo")
+		traceassert.HasTag(t, initiatorSpan, string(ext.Error), true)
+		traceassert.HasTag(t, initiatorSpan, string(ext.HTTPStatusCode), uint16(http.StatusBadRequest))
+		traceassert.HasLogField(t, initiatorSpan, "grpc.status_code", "InvalidArgument")
+	})
+}
+
+// rpcSpan returns the only span of the grpc Create call of the given kind, as the client and server spans
+// share the name of the method.
+func rpcSpan(t *testing.T, spans []recorder.FinishedSpan, kind ext.SpanKindEnum) recorder.FinishedSpan {
+	t.Helper()
+
+	var kindSpans []recorder.FinishedSpan
+	for _, s := range traceassert.Spans(spans, createMethod) {
+		if s.Tags[string(ext.SpanKind)] == kind {
+			kindSpans = append(kindSpans, s)
+		}
+	}
+
+	return traceassert.Span(t, kindSpans, createMethod)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/kafka-consumer/offset/repository/repository.go
+
+// Package offsetrepositorymock is a generated GoMock package.
+package offsetrepositorymock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	offset "github.com/andream16/go-opentracing-example/src/kafka-consumer/offset"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// Offsets mocks base method.
+func (m *MockStore) Offsets(ctx context.Context, group, topic string) (map[int32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Offsets", ctx, group, topic)
+	ret0, _ := ret[0].(map[int32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Offsets indicates an expected call of Offsets.
+func (mr *MockStoreMockRecorder) Offsets(ctx, group, topic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Offsets", reflect.TypeOf((*MockStore)(nil).Offsets), ctx, group, topic)
+}
+
+// Save mocks base method.
+func (m *MockStore) Save(ctx context.Context, p offset.Position) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockStoreMockRecorder) Save(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStore)(nil).Save), ctx, p)
+}
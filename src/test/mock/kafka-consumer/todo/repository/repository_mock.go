@@ -48,3 +48,17 @@ func (mr *MockCreatorMockRecorder) Create(ctx, todo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCreator)(nil).Create), ctx, todo)
 }
+
+// CreateBatch mocks base method.
+func (m *MockCreator) CreateBatch(ctx context.Context, todos []*todo.Todo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, todos)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockCreatorMockRecorder) CreateBatch(ctx, todos interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockCreator)(nil).CreateBatch), ctx, todos)
+}
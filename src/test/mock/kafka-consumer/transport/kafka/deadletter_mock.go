@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/kafka-consumer/transport/kafka/deadletter.go
+
+// Package transportkafkamock is a generated GoMock package.
+package transportkafkamock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sarama "github.com/Shopify/sarama"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeadLetterer is a mock of DeadLetterer interface.
+type MockDeadLetterer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLettererMockRecorder
+}
+
+// MockDeadLettererMockRecorder is the mock recorder for MockDeadLetterer.
+type MockDeadLettererMockRecorder struct {
+	mock *MockDeadLetterer
+}
+
+// NewMockDeadLetterer creates a new mock instance.
+func NewMockDeadLetterer(ctrl *gomock.Controller) *MockDeadLetterer {
+	mock := &MockDeadLetterer{ctrl: ctrl}
+	mock.recorder = &MockDeadLettererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterer) EXPECT() *MockDeadLettererMockRecorder {
+	return m.recorder
+}
+
+// DeadLetter mocks base method.
+func (m *MockDeadLetterer) DeadLetter(ctx context.Context, message *sarama.ConsumerMessage, reason error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetter", ctx, message, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetter indicates an expected call of DeadLetter.
+func (mr *MockDeadLettererMockRecorder) DeadLetter(ctx, message, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetter", reflect.TypeOf((*MockDeadLetterer)(nil).DeadLetter), ctx, message, reason)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/kafka-consumer/transport/kafka/retry.go
+
+// Package transportkafkamock is a generated GoMock package.
+package transportkafkamock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sarama "github.com/Shopify/sarama"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRetrier is a mock of Retrier interface.
+type MockRetrier struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetrierMockRecorder
+}
+
+// MockRetrierMockRecorder is the mock recorder for MockRetrier.
+type MockRetrierMockRecorder struct {
+	mock *MockRetrier
+}
+
+// NewMockRetrier creates a new mock instance.
+func NewMockRetrier(ctrl *gomock.Controller) *MockRetrier {
+	mock := &MockRetrier{ctrl: ctrl}
+	mock.recorder = &MockRetrierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetrier) EXPECT() *MockRetrierMockRecorder {
+	return m.recorder
+}
+
+// Retry mocks base method.
+func (m *MockRetrier) Retry(ctx context.Context, message *sarama.ConsumerMessage, reason error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, message, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockRetrierMockRecorder) Retry(ctx, message, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockRetrier)(nil).Retry), ctx, message, reason)
+}
@@ -47,3 +47,17 @@ func (mr *MockSenderMockRecorder) SendMessage(message interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockSender)(nil).SendMessage), message)
 }
+
+// SendMessages mocks base method.
+func (m *MockSender) SendMessages(messages []*sarama.ProducerMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessages", messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMessages indicates an expected call of SendMessages.
+func (mr *MockSenderMockRecorder) SendMessages(messages interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessages", reflect.TypeOf((*MockSender)(nil).SendMessages), messages)
+}
@@ -11,6 +11,7 @@ import (
 	v1 "github.com/andream16/go-opentracing-example/contracts/build/go/go_opentracing_example/grpc_server/todo/v1"
 	gomock "github.com/golang/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockTodoServiceClient is a mock of TodoServiceClient interface.
@@ -36,6 +37,26 @@ func (m *MockTodoServiceClient) EXPECT() *MockTodoServiceClientMockRecorder {
 	return m.recorder
 }
 
+// BatchCreate mocks base method.
+func (m *MockTodoServiceClient) BatchCreate(ctx context.Context, in *v1.BatchCreateRequest, opts ...grpc.CallOption) (*v1.BatchCreateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchCreate", varargs...)
+	ret0, _ := ret[0].(*v1.BatchCreateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreate indicates an expected call of BatchCreate.
+func (mr *MockTodoServiceClientMockRecorder) BatchCreate(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*MockTodoServiceClient)(nil).BatchCreate), varargs...)
+}
+
 // Create mocks base method.
 func (m *MockTodoServiceClient) Create(ctx context.Context, in *v1.CreateRequest, opts ...grpc.CallOption) (*v1.CreateResponse, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +77,249 @@ func (mr *MockTodoServiceClientMockRecorder) Create(ctx, in interface{}, opts ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoServiceClient)(nil).Create), varargs...)
 }
 
+// DeleteTodo mocks base method.
+func (m *MockTodoServiceClient) DeleteTodo(ctx context.Context, in *v1.DeleteTodoRequest, opts ...grpc.CallOption) (*v1.DeleteTodoResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteTodo", varargs...)
+	ret0, _ := ret[0].(*v1.DeleteTodoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTodo indicates an expected call of DeleteTodo.
+func (mr *MockTodoServiceClientMockRecorder) DeleteTodo(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockTodoServiceClient)(nil).DeleteTodo), varargs...)
+}
+
+// GetOperation mocks base method.
+func (m *MockTodoServiceClient) GetOperation(ctx context.Context, in *v1.GetOperationRequest, opts ...grpc.CallOption) (*v1.GetOperationResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetOperation", varargs...)
+	ret0, _ := ret[0].(*v1.GetOperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperation indicates an expected call of GetOperation.
+func (mr *MockTodoServiceClientMockRecorder) GetOperation(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockTodoServiceClient)(nil).GetOperation), varargs...)
+}
+
+// GetTodo mocks base method.
+func (m *MockTodoServiceClient) GetTodo(ctx context.Context, in *v1.GetTodoRequest, opts ...grpc.CallOption) (*v1.GetTodoResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetTodo", varargs...)
+	ret0, _ := ret[0].(*v1.GetTodoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTodo indicates an expected call of GetTodo.
+func (mr *MockTodoServiceClientMockRecorder) GetTodo(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodo", reflect.TypeOf((*MockTodoServiceClient)(nil).GetTodo), varargs...)
+}
+
+// ListTodos mocks base method.
+func (m *MockTodoServiceClient) ListTodos(ctx context.Context, in *v1.ListTodosRequest, opts ...grpc.CallOption) (*v1.ListTodosResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTodos", varargs...)
+	ret0, _ := ret[0].(*v1.ListTodosResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodos indicates an expected call of ListTodos.
+func (mr *MockTodoServiceClientMockRecorder) ListTodos(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockTodoServiceClient)(nil).ListTodos), varargs...)
+}
+
+// UpdateTodo mocks base method.
+func (m *MockTodoServiceClient) UpdateTodo(ctx context.Context, in *v1.UpdateTodoRequest, opts ...grpc.CallOption) (*v1.UpdateTodoResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateTodo", varargs...)
+	ret0, _ := ret[0].(*v1.UpdateTodoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodo indicates an expected call of UpdateTodo.
+func (mr *MockTodoServiceClientMockRecorder) UpdateTodo(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockTodoServiceClient)(nil).UpdateTodo), varargs...)
+}
+
+// WatchTodos mocks base method.
+func (m *MockTodoServiceClient) WatchTodos(ctx context.Context, in *v1.WatchTodosRequest, opts ...grpc.CallOption) (v1.TodoService_WatchTodosClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchTodos", varargs...)
+	ret0, _ := ret[0].(v1.TodoService_WatchTodosClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchTodos indicates an expected call of WatchTodos.
+func (mr *MockTodoServiceClientMockRecorder) WatchTodos(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchTodos", reflect.TypeOf((*MockTodoServiceClient)(nil).WatchTodos), varargs...)
+}
+
+// MockTodoService_WatchTodosClient is a mock of TodoService_WatchTodosClient interface.
+type MockTodoService_WatchTodosClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoService_WatchTodosClientMockRecorder
+}
+
+// MockTodoService_WatchTodosClientMockRecorder is the mock recorder for MockTodoService_WatchTodosClient.
+type MockTodoService_WatchTodosClientMockRecorder struct {
+	mock *MockTodoService_WatchTodosClient
+}
+
+// NewMockTodoService_WatchTodosClient creates a new mock instance.
+func NewMockTodoService_WatchTodosClient(ctrl *gomock.Controller) *MockTodoService_WatchTodosClient {
+	mock := &MockTodoService_WatchTodosClient{ctrl: ctrl}
+	mock.recorder = &MockTodoService_WatchTodosClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoService_WatchTodosClient) EXPECT() *MockTodoService_WatchTodosClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockTodoService_WatchTodosClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockTodoService_WatchTodosClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockTodoService_WatchTodosClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockTodoService_WatchTodosClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTodoService_WatchTodosClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTodoService_WatchTodosClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockTodoService_WatchTodosClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockTodoService_WatchTodosClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockTodoService_WatchTodosClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockTodoService_WatchTodosClient) Recv() (*v1.WatchTodosResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*v1.WatchTodosResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockTodoService_WatchTodosClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockTodoService_WatchTodosClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockTodoService_WatchTodosClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTodoService_WatchTodosClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTodoService_WatchTodosClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockTodoService_WatchTodosClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTodoService_WatchTodosClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTodoService_WatchTodosClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockTodoService_WatchTodosClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockTodoService_WatchTodosClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockTodoService_WatchTodosClient)(nil).Trailer))
+}
+
 // MockTodoServiceServer is a mock of TodoServiceServer interface.
 type MockTodoServiceServer struct {
 	ctrl     *gomock.Controller
@@ -79,6 +343,21 @@ func (m *MockTodoServiceServer) EXPECT() *MockTodoServiceServerMockRecorder {
 	return m.recorder
 }
 
+// BatchCreate mocks base method.
+func (m *MockTodoServiceServer) BatchCreate(arg0 context.Context, arg1 *v1.BatchCreateRequest) (*v1.BatchCreateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreate", arg0, arg1)
+	ret0, _ := ret[0].(*v1.BatchCreateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreate indicates an expected call of BatchCreate.
+func (mr *MockTodoServiceServerMockRecorder) BatchCreate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*MockTodoServiceServer)(nil).BatchCreate), arg0, arg1)
+}
+
 // Create mocks base method.
 func (m *MockTodoServiceServer) Create(arg0 context.Context, arg1 *v1.CreateRequest) (*v1.CreateResponse, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +373,95 @@ func (mr *MockTodoServiceServerMockRecorder) Create(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoServiceServer)(nil).Create), arg0, arg1)
 }
 
+// DeleteTodo mocks base method.
+func (m *MockTodoServiceServer) DeleteTodo(arg0 context.Context, arg1 *v1.DeleteTodoRequest) (*v1.DeleteTodoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTodo", arg0, arg1)
+	ret0, _ := ret[0].(*v1.DeleteTodoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTodo indicates an expected call of DeleteTodo.
+func (mr *MockTodoServiceServerMockRecorder) DeleteTodo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockTodoServiceServer)(nil).DeleteTodo), arg0, arg1)
+}
+
+// GetOperation mocks base method.
+func (m *MockTodoServiceServer) GetOperation(arg0 context.Context, arg1 *v1.GetOperationRequest) (*v1.GetOperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperation", arg0, arg1)
+	ret0, _ := ret[0].(*v1.GetOperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperation indicates an expected call of GetOperation.
+func (mr *MockTodoServiceServerMockRecorder) GetOperation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockTodoServiceServer)(nil).GetOperation), arg0, arg1)
+}
+
+// GetTodo mocks base method.
+func (m *MockTodoServiceServer) GetTodo(arg0 context.Context, arg1 *v1.GetTodoRequest) (*v1.GetTodoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTodo", arg0, arg1)
+	ret0, _ := ret[0].(*v1.GetTodoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTodo indicates an expected call of GetTodo.
+func (mr *MockTodoServiceServerMockRecorder) GetTodo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodo", reflect.TypeOf((*MockTodoServiceServer)(nil).GetTodo), arg0, arg1)
+}
+
+// ListTodos mocks base method.
+func (m *MockTodoServiceServer) ListTodos(arg0 context.Context, arg1 *v1.ListTodosRequest) (*v1.ListTodosResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodos", arg0, arg1)
+	ret0, _ := ret[0].(*v1.ListTodosResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodos indicates an expected call of ListTodos.
+func (mr *MockTodoServiceServerMockRecorder) ListTodos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockTodoServiceServer)(nil).ListTodos), arg0, arg1)
+}
+
+// UpdateTodo mocks base method.
+func (m *MockTodoServiceServer) UpdateTodo(arg0 context.Context, arg1 *v1.UpdateTodoRequest) (*v1.UpdateTodoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodo", arg0, arg1)
+	ret0, _ := ret[0].(*v1.UpdateTodoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodo indicates an expected call of UpdateTodo.
+func (mr *MockTodoServiceServerMockRecorder) UpdateTodo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockTodoServiceServer)(nil).UpdateTodo), arg0, arg1)
+}
+
+// WatchTodos mocks base method.
+func (m *MockTodoServiceServer) WatchTodos(arg0 *v1.WatchTodosRequest, arg1 v1.TodoService_WatchTodosServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchTodos", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchTodos indicates an expected call of WatchTodos.
+func (mr *MockTodoServiceServerMockRecorder) WatchTodos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchTodos", reflect.TypeOf((*MockTodoServiceServer)(nil).WatchTodos), arg0, arg1)
+}
+
 // MockUnsafeTodoServiceServer is a mock of UnsafeTodoServiceServer interface.
 type MockUnsafeTodoServiceServer struct {
 	ctrl     *gomock.Controller
@@ -128,3 +496,122 @@ func (mr *MockUnsafeTodoServiceServerMockRecorder) mustEmbedUnimplementedTodoSer
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedTodoServiceServer", reflect.TypeOf((*MockUnsafeTodoServiceServer)(nil).mustEmbedUnimplementedTodoServiceServer))
 }
+
+// MockTodoService_WatchTodosServer is a mock of TodoService_WatchTodosServer interface.
+type MockTodoService_WatchTodosServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoService_WatchTodosServerMockRecorder
+}
+
+// MockTodoService_WatchTodosServerMockRecorder is the mock recorder for MockTodoService_WatchTodosServer.
+type MockTodoService_WatchTodosServerMockRecorder struct {
+	mock *MockTodoService_WatchTodosServer
+}
+
+// NewMockTodoService_WatchTodosServer creates a new mock instance.
+func NewMockTodoService_WatchTodosServer(ctrl *gomock.Controller) *MockTodoService_WatchTodosServer {
+	mock := &MockTodoService_WatchTodosServer{ctrl: ctrl}
+	mock.recorder = &MockTodoService_WatchTodosServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoService_WatchTodosServer) EXPECT() *MockTodoService_WatchTodosServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockTodoService_WatchTodosServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTodoService_WatchTodosServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTodoService_WatchTodosServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockTodoService_WatchTodosServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTodoService_WatchTodosServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTodoService_WatchTodosServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockTodoService_WatchTodosServer) Send(arg0 *v1.WatchTodosResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockTodoService_WatchTodosServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockTodoService_WatchTodosServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockTodoService_WatchTodosServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockTodoService_WatchTodosServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockTodoService_WatchTodosServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockTodoService_WatchTodosServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTodoService_WatchTodosServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTodoService_WatchTodosServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockTodoService_WatchTodosServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockTodoService_WatchTodosServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockTodoService_WatchTodosServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockTodoService_WatchTodosServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockTodoService_WatchTodosServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockTodoService_WatchTodosServer)(nil).SetTrailer), arg0)
+}
@@ -0,0 +1,374 @@
+// Package recorder provides an in-memory tracer recording the finished spans, so that tests can
+// assert on the shape of the traces produced by the code under test.
+package recorder
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+)
+
+var _ tracing.Tracer = (*Tracer)(nil)
+
+const (
+	traceIDHeader       = "recorder-trace-id"
+	spanIDHeader        = "recorder-span-id"
+	baggageHeaderPrefix = "recorder-baggage-"
+)
+
+// SpanContext is the context of the spans started by Tracer.
+// Its ids follow the OpenTelemetry format, so that tracing.TraceID works with it.
+type SpanContext struct {
+	traceID oteltrace.TraceID
+	spanID  oteltrace.SpanID
+	baggage map[string]string
+}
+
+// TraceID returns the id of the trace the span belongs to.
+func (c SpanContext) TraceID() oteltrace.TraceID {
+	return c.traceID
+}
+
+// SpanID returns the id of the span.
+func (c SpanContext) SpanID() oteltrace.SpanID {
+	return c.spanID
+}
+
+// String returns the trace and span ids of c, handy in failure messages.
+func (c SpanContext) String() string {
+	return c.traceID.String() + ":" + c.spanID.String()
+}
+
+// ForeachBaggageItem calls handler for every baggage item until it returns false.
+func (c SpanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+func (c SpanContext) withBaggageItem(key, value string) SpanContext {
+	baggage := make(map[string]string, len(c.baggage)+1)
+	for k, v := range c.baggage {
+		baggage[k] = v
+	}
+	baggage[key] = value
+	c.baggage = baggage
+	return c
+}
+
+// Reference is a reference of a span to another span.
+type Reference struct {
+	Type    opentracing.SpanReferenceType
+	Context SpanContext
+}
+
+// FinishedSpan is the record of a finished span.
+type FinishedSpan struct {
+	OperationName string
+	Context       SpanContext
+	References    []Reference
+	Tags          map[string]interface{}
+	Logs          []opentracing.LogRecord
+	StartTime     time.Time
+	FinishTime    time.Time
+}
+
+// Tracer is an in-memory tracing.Tracer recording the spans it starts once they are finished.
+// Span contexts are injected into and extracted from text maps and http headers only.
+type Tracer struct {
+	lastID uint64
+
+	mu    sync.Mutex
+	spans []FinishedSpan
+}
+
+// New returns a new Tracer.
+func New() *Tracer {
+	return &Tracer{}
+}
+
+// FinishedSpans returns the spans finished so far, in the order they were finished.
+func (t *Tracer) FinishedSpans() []FinishedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]FinishedSpan, len(t.spans))
+	copy(spans, t.spans)
+	return spans
+}
+
+// Reset forgets the spans finished so far.
+func (t *Tracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = nil
+}
+
+// StartSpan starts a new span. It belongs to the trace of its first ChildOf reference or,
+// when it has none, of its first reference. It starts a new trace when it has no references.
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	var options opentracing.StartSpanOptions
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+
+	s := &span{
+		tracer:        t,
+		operationName: operationName,
+		tags:          make(map[string]interface{}, len(options.Tags)),
+		startTime:     options.StartTime,
+	}
+	if s.startTime.IsZero() {
+		s.startTime = time.Now()
+	}
+	for k, v := range options.Tags {
+		s.tags[k] = v
+	}
+
+	var (
+		parent        *SpanContext
+		parentIsChild bool
+	)
+	for _, ref := range options.References {
+		sc, ok := ref.ReferencedContext.(SpanContext)
+		if !ok {
+			continue
+		}
+		s.references = append(s.references, Reference{Type: ref.Type, Context: sc})
+		if parent == nil || (ref.Type == opentracing.ChildOfRef && !parentIsChild) {
+			parent, parentIsChild = &sc, ref.Type == opentracing.ChildOfRef
+		}
+	}
+
+	if parent != nil {
+		s.context = SpanContext{traceID: parent.traceID, baggage: parent.baggage}
+	} else {
+		s.context = SpanContext{traceID: t.newTraceID()}
+	}
+	s.context.spanID = t.newSpanID()
+
+	return s
+}
+
+// Inject writes sc into carrier.
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	sc, ok := sm.(SpanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return opentracing.ErrUnsupportedFormat
+	}
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	writer.Set(traceIDHeader, sc.traceID.String())
+	writer.Set(spanIDHeader, sc.spanID.String())
+	for k, v := range sc.baggage {
+		writer.Set(baggageHeaderPrefix+k, v)
+	}
+
+	return nil
+}
+
+// Extract reads the span context carried by carrier.
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+
+	var (
+		sc                    SpanContext
+		hasTrace, hasSpan     bool
+		traceIDErr, spanIDErr error
+	)
+	if err := reader.ForeachKey(func(key, value string) error {
+		switch key = strings.ToLower(key); {
+		case key == traceIDHeader:
+			sc.traceID, traceIDErr = oteltrace.TraceIDFromHex(value)
+			hasTrace = true
+		case key == spanIDHeader:
+			sc.spanID, spanIDErr = oteltrace.SpanIDFromHex(value)
+			hasSpan = true
+		case strings.HasPrefix(key, baggageHeaderPrefix):
+			sc = sc.withBaggageItem(strings.TrimPrefix(key, baggageHeaderPrefix), value)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !hasTrace && !hasSpan:
+		return nil, opentracing.ErrSpanContextNotFound
+	case !hasTrace, !hasSpan, traceIDErr != nil, spanIDErr != nil:
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	return sc, nil
+}
+
+// Close does nothing, the finished spans are kept in memory.
+func (t *Tracer) Close() error {
+	return nil
+}
+
+func (t *Tracer) newTraceID() oteltrace.TraceID {
+	var id oteltrace.TraceID
+	binary.BigEndian.PutUint64(id[8:], atomic.AddUint64(&t.lastID, 1))
+	return id
+}
+
+func (t *Tracer) newSpanID() oteltrace.SpanID {
+	var id oteltrace.SpanID
+	binary.BigEndian.PutUint64(id[:], atomic.AddUint64(&t.lastID, 1))
+	return id
+}
+
+func (t *Tracer) record(s FinishedSpan) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = append(t.spans, s)
+}
+
+type span struct {
+	tracer *Tracer
+
+	mu            sync.Mutex
+	operationName string
+	context       SpanContext
+	references    []Reference
+	tags          map[string]interface{}
+	logs          []opentracing.LogRecord
+	startTime     time.Time
+	finished      bool
+}
+
+func (s *span) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+func (s *span) FinishWithOptions(opts opentracing.FinishOptions) {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+
+	finishTime := opts.FinishTime
+	if finishTime.IsZero() {
+		finishTime = time.Now()
+	}
+
+	tags := make(map[string]interface{}, len(s.tags))
+	for k, v := range s.tags {
+		tags[k] = v
+	}
+
+	finished := FinishedSpan{
+		OperationName: s.operationName,
+		Context:       s.context,
+		References:    append([]Reference(nil), s.references...),
+		Tags:          tags,
+		Logs:          append(append([]opentracing.LogRecord(nil), s.logs...), opts.LogRecords...),
+		StartTime:     s.startTime,
+		FinishTime:    finishTime,
+	}
+	s.mu.Unlock()
+
+	s.tracer.record(finished)
+}
+
+func (s *span) Context() opentracing.SpanContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.context
+}
+
+func (s *span) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.operationName = operationName
+	return s
+}
+
+func (s *span) SetTag(key string, value interface{}) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tags[key] = value
+	return s
+}
+
+func (s *span) LogFields(fields ...log.Field) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logs = append(s.logs, opentracing.LogRecord{Timestamp: time.Now(), Fields: fields})
+}
+
+func (s *span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		s.LogFields(log.Error(err), log.String("function", "LogKV"))
+		return
+	}
+	s.LogFields(fields...)
+}
+
+func (s *span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.context = s.context.withBaggageItem(restrictedKey, value)
+	return s
+}
+
+func (s *span) BaggageItem(restrictedKey string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.context.baggage[restrictedKey]
+}
+
+func (s *span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+func (s *span) LogEvent(event string) {
+	s.Log(opentracing.LogData{Event: event})
+}
+
+func (s *span) LogEventWithPayload(event string, payload interface{}) {
+	s.Log(opentracing.LogData{Event: event, Payload: payload})
+}
+
+func (s *span) Log(data opentracing.LogData) {
+	record := data.ToLogRecord()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logs = append(s.logs, record)
+}
@@ -0,0 +1,116 @@
+package recorder_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/shared/tracing"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+)
+
+func TestTracer_StartSpan(t *testing.T) {
+	t.Run("it should record the spans once finished", func(t *testing.T) {
+		tracer := recorder.New()
+
+		var (
+			startTime = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+			root      = tracer.StartSpan("root", opentracing.StartTime(startTime), opentracing.Tag{Key: "some", Value: "tag"})
+			child     = tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+		)
+
+		child.SetTag("error", true)
+		child.LogKV("event", "someEvent")
+		child.Finish()
+		child.Finish()
+
+		assert.Len(t, tracer.FinishedSpans(), 1)
+
+		root.SetOperationName("renamed")
+		root.FinishWithOptions(opentracing.FinishOptions{
+			LogRecords: []opentracing.LogRecord{{Fields: []log.Field{log.String("some", "log")}}},
+		})
+
+		spans := tracer.FinishedSpans()
+		require.Len(t, spans, 2)
+
+		assert.Equal(t, "child", spans[0].OperationName)
+		assert.Equal(t, map[string]interface{}{"error": true}, spans[0].Tags)
+		require.Len(t, spans[0].Logs, 1)
+		assert.Equal(t, "event", spans[0].Logs[0].Fields[0].Key())
+		assert.Equal(t, []recorder.Reference{{Type: opentracing.ChildOfRef, Context: spans[1].Context}}, spans[0].References)
+		assert.Equal(t, spans[1].Context.TraceID(), spans[0].Context.TraceID())
+		assert.NotEqual(t, spans[1].Context.SpanID(), spans[0].Context.SpanID())
+
+		assert.Equal(t, "renamed", spans[1].OperationName)
+		assert.Equal(t, map[string]interface{}{"some": "tag"}, spans[1].Tags)
+		assert.Equal(t, startTime, spans[1].StartTime)
+		assert.Empty(t, spans[1].References)
+		require.Len(t, spans[1].Logs, 1)
+		assert.Equal(t, "some", spans[1].Logs[0].Fields[0].Key())
+
+		tracer.Reset()
+		assert.Empty(t, tracer.FinishedSpans())
+	})
+	t.Run("it should start a new trace for every span without references", func(t *testing.T) {
+		tracer := recorder.New()
+
+		first, second := tracer.StartSpan("first"), tracer.StartSpan("second")
+		assert.NotEqual(t, tracing.TraceID(first), tracing.TraceID(second))
+		assert.Len(t, tracing.TraceID(first), 32)
+	})
+	t.Run("it should join the trace of the first child of reference", func(t *testing.T) {
+		tracer := recorder.New()
+
+		var (
+			origin = tracer.StartSpan("origin")
+			parent = tracer.StartSpan("parent")
+			span   = tracer.StartSpan("span", opentracing.FollowsFrom(origin.Context()), opentracing.ChildOf(parent.Context()))
+		)
+
+		assert.Equal(t, tracing.TraceID(parent), tracing.TraceID(span))
+	})
+	t.Run("it should propagate the baggage to the children", func(t *testing.T) {
+		tracer := recorder.New()
+
+		parent := tracer.StartSpan("parent")
+		parent.SetBaggageItem("tenant", "someTenant")
+
+		child := tracer.StartSpan("child", opentracing.ChildOf(parent.Context()))
+		assert.Equal(t, "someTenant", child.BaggageItem("tenant"))
+	})
+}
+
+func TestTracer_InjectExtract(t *testing.T) {
+	t.Run("it should extract the span context it injected", func(t *testing.T) {
+		tracer := recorder.New()
+
+		span := tracer.StartSpan("span")
+		span.SetBaggageItem("tenant", "someTenant")
+
+		header := http.Header{}
+		require.NoError(t, tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)))
+
+		sc, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+		require.NoError(t, err)
+		assert.Equal(t, span.Context(), sc)
+	})
+	t.Run("it should return an error because the carrier holds no span context", func(t *testing.T) {
+		_, err := recorder.New().Extract(opentracing.TextMap, opentracing.TextMapCarrier{})
+		assert.Equal(t, opentracing.ErrSpanContextNotFound, err)
+	})
+	t.Run("it should return an error because the carrier holds a corrupted span context", func(t *testing.T) {
+		_, err := recorder.New().Extract(opentracing.TextMap, opentracing.TextMapCarrier{"recorder-trace-id": "someID"})
+		assert.Equal(t, opentracing.ErrSpanContextCorrupted, err)
+	})
+	t.Run("it should return an error because the format is not supported", func(t *testing.T) {
+		tracer := recorder.New()
+
+		assert.Equal(t, opentracing.ErrUnsupportedFormat, tracer.Inject(tracer.StartSpan("span").Context(), opentracing.Binary, nil))
+	})
+}
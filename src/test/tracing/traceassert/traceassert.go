@@ -0,0 +1,223 @@
+// Package traceassert provides assertions on the spans recorded by recorder.Tracer.
+// Like testify's assert, every assertion reports its failure to t and returns whether it holds,
+// except for Span which stops the test like testify's require.
+package traceassert
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+)
+
+// TestingT is the subset of testing.T the assertions need.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	FailNow()
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// Span returns the only span named operationName. It stops the test when there is none or more than one.
+func Span(t TestingT, spans []recorder.FinishedSpan, operationName string) recorder.FinishedSpan {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	named := Spans(spans, operationName)
+	if len(named) != 1 {
+		t.Errorf("expected a single span named %q, got %d among %s", operationName, len(named), operationNames(spans))
+		t.FailNow()
+		return recorder.FinishedSpan{}
+	}
+
+	return named[0]
+}
+
+// Spans returns the spans named operationName.
+func Spans(spans []recorder.FinishedSpan, operationName string) []recorder.FinishedSpan {
+	var named []recorder.FinishedSpan
+	for _, s := range spans {
+		if s.OperationName == operationName {
+			named = append(named, s)
+		}
+	}
+	return named
+}
+
+// TraceLen asserts that the trace of span is made of n of the given spans.
+func TraceLen(t TestingT, spans []recorder.FinishedSpan, span recorder.FinishedSpan, n int) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	var count int
+	for _, s := range spans {
+		if s.Context.TraceID() == span.Context.TraceID() {
+			count++
+		}
+	}
+	if count != n {
+		t.Errorf("expected the trace of span %q to have %d spans, got %d", span.OperationName, n, count)
+		return false
+	}
+
+	return true
+}
+
+// SameTrace asserts that the spans belong to the same trace.
+func SameTrace(t TestingT, spans ...recorder.FinishedSpan) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	for i := 1; i < len(spans); i++ {
+		if s := spans[i]; s.Context.TraceID() != spans[0].Context.TraceID() {
+			t.Errorf(
+				"expected span %q to belong to trace %s, got %s",
+				s.OperationName,
+				spans[0].Context.TraceID(),
+				s.Context.TraceID(),
+			)
+			return false
+		}
+	}
+
+	return true
+}
+
+// Root asserts that span starts its trace.
+func Root(t TestingT, span recorder.FinishedSpan) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if len(span.References) > 0 {
+		t.Errorf("expected span %q to be a root span, got %d references", span.OperationName, len(span.References))
+		return false
+	}
+
+	return true
+}
+
+// ChildOf asserts that child references parent with a ChildOf reference.
+func ChildOf(t TestingT, child, parent recorder.FinishedSpan) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return references(t, child, parent, opentracing.ChildOfRef)
+}
+
+// FollowsFrom asserts that span references origin with a FollowsFrom reference.
+func FollowsFrom(t TestingT, span, origin recorder.FinishedSpan) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return references(t, span, origin, opentracing.FollowsFromRef)
+}
+
+// HasTag asserts that span is tagged with key set to value.
+func HasTag(t TestingT, span recorder.FinishedSpan, key string, value interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	got, ok := span.Tags[key]
+	switch {
+	case !ok:
+		t.Errorf("expected span %q to be tagged with %s, got tags %v", span.OperationName, key, span.Tags)
+		return false
+	case !reflect.DeepEqual(got, value):
+		t.Errorf("expected tag %s of span %q to be %#v, got %#v", key, span.OperationName, value, got)
+		return false
+	}
+
+	return true
+}
+
+// NoTag asserts that span is not tagged with key.
+func NoTag(t TestingT, span recorder.FinishedSpan, key string) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if got, ok := span.Tags[key]; ok {
+		t.Errorf("expected span %q not to be tagged with %s, got %#v", span.OperationName, key, got)
+		return false
+	}
+
+	return true
+}
+
+// HasLogField asserts that span logged a field named key set to value.
+func HasLogField(t TestingT, span recorder.FinishedSpan, key string, value interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	var logged []interface{}
+	for _, record := range span.Logs {
+		for _, field := range record.Fields {
+			if field.Key() != key {
+				continue
+			}
+			if reflect.DeepEqual(field.Value(), value) {
+				return true
+			}
+			logged = append(logged, field.Value())
+		}
+	}
+
+	t.Errorf("expected span %q to log %s=%#v, got %v", span.OperationName, key, value, logged)
+	return false
+}
+
+func references(t TestingT, span, referenced recorder.FinishedSpan, refType opentracing.SpanReferenceType) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	for _, ref := range span.References {
+		if ref.Type == refType && ref.Context.SpanID() == referenced.Context.SpanID() {
+			return true
+		}
+	}
+
+	t.Errorf(
+		"expected span %q to reference span %q with a %s reference, got %s",
+		span.OperationName,
+		referenced.OperationName,
+		refTypeName(refType),
+		referenceNames(span.References),
+	)
+	return false
+}
+
+func refTypeName(refType opentracing.SpanReferenceType) string {
+	if refType == opentracing.ChildOfRef {
+		return "ChildOf"
+	}
+	return "FollowsFrom"
+}
+
+func referenceNames(refs []recorder.Reference) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, fmt.Sprintf("%s(%s)", refTypeName(ref.Type), ref.Context))
+	}
+	return names
+}
+
+func operationNames(spans []recorder.FinishedSpan) []string {
+	names := make([]string, 0, len(spans))
+	for _, s := range spans {
+		names = append(names, s.OperationName)
+	}
+	return names
+}
@@ -0,0 +1,91 @@
+package traceassert_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/andream16/go-opentracing-example/src/test/tracing/recorder"
+	"github.com/andream16/go-opentracing-example/src/test/tracing/traceassert"
+)
+
+type fakeT struct {
+	errors []string
+	failed bool
+}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) FailNow() {
+	f.failed = true
+}
+
+func recordTrace() []recorder.FinishedSpan {
+	tracer := recorder.New()
+
+	root := tracer.StartSpan("root", opentracing.Tag{Key: "error", Value: true})
+	child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+	child.LogKV("event", "someEvent")
+	follower := tracer.StartSpan("follower", opentracing.FollowsFrom(child.Context()))
+	other := tracer.StartSpan("other")
+
+	for _, s := range []opentracing.Span{other, follower, child, root} {
+		s.Finish()
+	}
+
+	return tracer.FinishedSpans()
+}
+
+func TestAssertions(t *testing.T) {
+	var (
+		spans    = recordTrace()
+		root     = traceassert.Span(t, spans, "root")
+		child    = traceassert.Span(t, spans, "child")
+		follower = traceassert.Span(t, spans, "follower")
+		other    = traceassert.Span(t, spans, "other")
+	)
+
+	t.Run("it should hold", func(t *testing.T) {
+		traceassert.Root(t, root)
+		traceassert.Root(t, other)
+		traceassert.ChildOf(t, child, root)
+		traceassert.FollowsFrom(t, follower, child)
+		traceassert.SameTrace(t, root, child, follower)
+		traceassert.TraceLen(t, spans, root, 3)
+		traceassert.TraceLen(t, spans, other, 1)
+		traceassert.HasTag(t, root, "error", true)
+		traceassert.NoTag(t, child, "error")
+		traceassert.HasLogField(t, child, "event", "someEvent")
+		assert.Len(t, traceassert.Spans(spans, "child"), 1)
+	})
+	t.Run("it should fail", func(t *testing.T) {
+		for name, holds := range map[string]func(ft *fakeT) bool{
+			"root":         func(ft *fakeT) bool { return traceassert.Root(ft, child) },
+			"child of":     func(ft *fakeT) bool { return traceassert.ChildOf(ft, follower, child) },
+			"follows from": func(ft *fakeT) bool { return traceassert.FollowsFrom(ft, child, root) },
+			"same trace":   func(ft *fakeT) bool { return traceassert.SameTrace(ft, root, other) },
+			"trace len":    func(ft *fakeT) bool { return traceassert.TraceLen(ft, spans, root, 4) },
+			"missing tag":  func(ft *fakeT) bool { return traceassert.HasTag(ft, child, "error", true) },
+			"tag value":    func(ft *fakeT) bool { return traceassert.HasTag(ft, root, "error", false) },
+			"no tag":       func(ft *fakeT) bool { return traceassert.NoTag(ft, root, "error") },
+			"log field":    func(ft *fakeT) bool { return traceassert.HasLogField(ft, child, "event", "otherEvent") },
+		} {
+			ft := &fakeT{}
+			assert.False(t, holds(ft), name)
+			assert.Len(t, ft.errors, 1, name)
+			assert.False(t, ft.failed, name)
+		}
+	})
+	t.Run("it should stop the test because the span is missing", func(t *testing.T) {
+		ft := &fakeT{}
+		traceassert.Span(ft, spans, "missing")
+		require.Len(t, ft.errors, 1)
+		assert.Contains(t, ft.errors[0], `"missing"`)
+		assert.True(t, ft.failed)
+	})
+}